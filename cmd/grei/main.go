@@ -25,6 +25,10 @@ func init() {
 	cli.AddVerifyCommand(rootCmd)
 	cli.AddInstallHooksCommand(rootCmd)
 	cli.AddDoctorCommand(rootCmd)
+	cli.AddUpgradeCommand(rootCmd)
+	cli.AddScaffoldCommand(rootCmd)
+	cli.AddRecipeCommand(rootCmd)
+	cli.AddDetectCommand(rootCmd)
 }
 
 func main() {
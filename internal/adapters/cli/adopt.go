@@ -0,0 +1,248 @@
+package cli
+
+import (
+	"fmt"
+	"grei-cli/internal/adapters/filesystem"
+	"grei-cli/internal/core/detector"
+	"grei-cli/internal/core/initializer"
+	"grei-cli/internal/core/recipe"
+	"grei-cli/internal/core/scaffolder"
+	"grei-cli/internal/ports/inbound"
+	"grei-cli/internal/ports/outbound"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// adoptProject runs init --adopt: it writes a grei.yml for the existing
+// project at targetPath, from its detected stack, and adds the standard
+// files it lacks without touching the rest of the project.
+func adoptProject(cmd *cobra.Command, targetPath, cacheDir string, initializerService inbound.InitializerService, scaffolderService inbound.ScaffolderService, previewService inbound.PreviewService, hooksService inbound.HooksService) error {
+	info, err := os.Stat(targetPath)
+	if err != nil || !info.IsDir() {
+		return fmt.Errorf("'%s' no es un directorio existente; --adopt trabaja sobre un repositorio existente", targetPath)
+	}
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return err
+	}
+
+	detection, err := detector.Detect(os.DirFS(targetPath))
+	if err != nil {
+		return fmt.Errorf("no se pudo detectar la pila del proyecto: %w", err)
+	}
+	match := detection.Stack
+	if match != nil {
+		color.Cyan("Pila detectada: %s (%s)", match.Value, strings.Join(match.Evidence, ", "))
+	} else {
+		color.Yellow("No se reconoció la pila del proyecto.")
+	}
+
+	var answers recipe.Recipe
+	var bundle *outbound.TemplateBundle
+	switch {
+	case noInteractive && recipeFile != "":
+		answers, bundle, err = loadRecipeFile(cmd, cacheDir, recipeFile)
+		if err != nil {
+			return err
+		}
+	case noInteractive:
+		if match == nil {
+			return fmt.Errorf("no se pudo detectar la pila del proyecto; indica una receta con --recipe-file")
+		}
+		bundle, err = LoadTemplates(cmd, cacheDir, true, nil)
+		if err != nil {
+			return err
+		}
+		answers.Project = recipe.Project{Name: filepath.Base(absPath), Customer: "Greicodex", Type: match.Value}
+		manifest, err := FindManifest(bundle.FS, answers.Project.Type)
+		if err != nil {
+			return err
+		}
+		if manifest != nil {
+			proposeDetected(manifest.Options, detection)
+			if err := manifest.Options.ApplyOptions(&answers); err != nil {
+				return fmt.Errorf("la receta no es válida para '%s': %w", answers.Project.Type, err)
+			}
+		}
+	default:
+		bundle, err = LoadTemplates(cmd, cacheDir, true, nil)
+		if err != nil {
+			return err
+		}
+		if err := askAdoption(bundle, filepath.Base(absPath), detection, &answers); err != nil {
+			return err
+		}
+	}
+	answers.APIVersion = recipe.APIVersion
+	answers.Templates = PinTemplates(bundle)
+
+	yamlData, err := yaml.Marshal(&answers)
+	if err != nil {
+		return fmt.Errorf("error al generar el archivo YAML: %w", err)
+	}
+
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		return previewAdopt(previewService, targetPath, bundle, &answers, yamlData)
+	}
+
+	if !noInteractive {
+		fmt.Printf("\nReceta propuesta:\n\n%s\n", yamlData)
+		confirmed := false
+		if err := survey.AskOne(&survey.Confirm{Message: "¿Crear grei.yml con esta receta?", Default: true}, &confirmed); err != nil {
+			return fmt.Errorf("error durante la encuesta: %w", err)
+		}
+		if !confirmed {
+			color.Yellow("No se adoptó el proyecto.")
+			return nil
+		}
+	}
+
+	recipePath := filepath.Join(targetPath, "grei.yml")
+	if err := os.WriteFile(recipePath, yamlData, 0644); err != nil {
+		return fmt.Errorf("error al escribir el archivo grei.yml: %w", err)
+	}
+	color.Green("✅ Receta del proyecto creada exitosamente en '%s'.", recipePath)
+
+	// An existing repository keeps its history and branches.
+	_, err = os.Stat(filepath.Join(targetPath, ".git"))
+	gitInit := os.IsNotExist(err)
+	if err := initializerService.AdoptProject(targetPath, bundle.FS, gitInit); err != nil {
+		return fmt.Errorf("error durante la inicialización: %w", err)
+	}
+	created, err := scaffolderService.Adopt(targetPath, bundle.FS, &answers)
+	if err != nil {
+		return fmt.Errorf("error al agregar los archivos estándar: %w", err)
+	}
+
+	if len(created) == 0 {
+		fmt.Println("\nEl proyecto ya tenía todos los archivos estándar.")
+	} else {
+		fmt.Println("\nArchivos estándar agregados:")
+		for _, file := range created {
+			color.Green("  + %s", file)
+		}
+	}
+	if gitInit {
+		// A project that was not under git gets the hooks and remote of a new
+		// one, but nothing is committed: the existing files are the user's to
+		// review, and the pre-commit hook must scan them for secrets.
+		if err := hooksService.InstallHooks(targetPath); err != nil {
+			return fmt.Errorf("error al instalar los Git hooks: %w", err)
+		}
+		if remote, _ := cmd.Flags().GetString("remote"); remote != "" {
+			if err := initializerService.AddRemote(targetPath, remote); err != nil {
+				return fmt.Errorf("error al preparar el repositorio git: %w", err)
+			}
+		}
+		fmt.Println("\n🚀 ¡Proyecto adoptado exitosamente!")
+		color.Cyan("Revisa los archivos y crea el primer commit en '%s'; el hook pre-commit buscará secretos:", targetPath)
+		fmt.Printf("  git add --all && git commit -m %q && git checkout -b %s\n", "chore: adopt project with grei", initializer.DevelopBranch)
+		color.Cyan("Después, ejecuta 'grei verify' para revisar el proyecto.")
+		return nil
+	}
+
+	fmt.Println("\n🚀 ¡Proyecto adoptado exitosamente!")
+	color.Cyan("Ejecuta 'grei install-hooks' para activar los Git hooks y 'grei verify' para revisar el proyecto.")
+	return nil
+}
+
+// askAdoption asks for the recipe of an adopted project, proposing the
+// detected stack and stack values.
+func askAdoption(bundle *outbound.TemplateBundle, name string, detection *detector.Detection, answers *recipe.Recipe) error {
+	match := detection.Stack
+	codeStacks, _, _ := CategorizeStacks(bundle.FS)
+	stackPrompt := &survey.Select{
+		Message: "¿Qué pila de código usa el proyecto?",
+		Options: codeStacks,
+	}
+	if match != nil {
+		for _, stack := range codeStacks {
+			if stack == match.Value {
+				stackPrompt.Default = match.Value
+			}
+		}
+	}
+
+	questions := []*survey.Question{
+		{
+			Name:     "name",
+			Prompt:   &survey.Input{Message: "¿Cuál es el nombre del proyecto?", Default: name},
+			Validate: survey.Required,
+		},
+		{
+			Name:     "customer",
+			Prompt:   &survey.Input{Message: "¿Quién es el cliente para este proyecto?", Default: "Greicodex"},
+			Validate: survey.Required,
+		},
+		{
+			Name:   "type",
+			Prompt: stackPrompt,
+		},
+	}
+	if err := survey.Ask(questions, &answers.Project); err != nil {
+		return fmt.Errorf("error durante la encuesta: %w", err)
+	}
+
+	manifest, err := FindManifest(bundle.FS, answers.Project.Type)
+	if err != nil {
+		return err
+	}
+	if manifest != nil {
+		proposeDetected(manifest.Options, detection)
+		return askOptions(manifest.Options, answers)
+	}
+	return nil
+}
+
+// proposeDetected makes the values detected in the project the defaults of
+// the stack options they answer, when the option accepts them.
+func proposeDetected(options scaffolder.Options, detection *detector.Detection) {
+	detected := map[string]string{
+		"linter":      detector.Best(detection.Linter),
+		"tests":       detector.Best(detection.Tests),
+		"persistence": detector.Best(detection.Persistence),
+		"ci":          detector.Best(detection.CI),
+	}
+	for i := range options {
+		value := detected[options[i].Name]
+		if value == "" {
+			continue
+		}
+		for _, v := range options[i].Values {
+			if v == value {
+				options[i].Default = value
+			}
+		}
+	}
+}
+
+// previewAdopt shows the recipe and the standard files adopt would add to
+// targetPath. Existing files are left out, as adopt never changes them.
+func previewAdopt(previewService inbound.PreviewService, targetPath string, bundle *outbound.TemplateBundle, answers *recipe.Recipe, yamlData []byte) error {
+	memRepo := filesystem.NewMemoryRepository()
+	if _, err := scaffolder.NewService(memRepo).Adopt(".", bundle.FS, answers); err != nil {
+		return fmt.Errorf("error al generar los archivos estándar: %w", err)
+	}
+
+	files := map[string][]byte{"grei.yml": yamlData}
+	for file, content := range memRepo.Files() {
+		if _, err := os.Stat(filepath.Join(targetPath, filepath.FromSlash(file))); os.IsNotExist(err) {
+			files[file] = content
+		}
+	}
+	if _, err := previewService.Preview(inbound.PreviewOptions{
+		Path:  targetPath,
+		Files: files,
+		Dirs:  memRepo.Dirs(),
+	}); err != nil {
+		return fmt.Errorf("error al comparar con los archivos existentes: %w", err)
+	}
+	color.Cyan("Simulación: no se escribió nada. Los archivos existentes no se modifican.")
+	return nil
+}
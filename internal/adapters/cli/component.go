@@ -0,0 +1,213 @@
+package cli
+
+import (
+	"fmt"
+	"grei-cli/internal/adapters/filesystem"
+	"grei-cli/internal/core/recipe"
+	"grei-cli/internal/ports/inbound"
+	"grei-cli/internal/ports/outbound"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// NewScaffoldComponentCommand creates the scaffold component command, which
+// adds a component to a monorepo.
+func NewScaffoldComponentCommand(initializerService inbound.InitializerService, scaffolderService inbound.ScaffolderService, previewService inbound.PreviewService, conflictRepo *filesystem.ConflictRepository) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "component <name> [path]",
+		Short: "Agrega un componente al monorepo, con su propia pila y su propio 'grei.yml'.",
+		Long: `Crea un nuevo componente en 'apps/<name>' (o en --path) con la pila elegida
+y lo agrega a la lista 'components' del 'grei.yml' de la raíz del repositorio.
+El componente recibe solo los archivos de su pila y sus mixins; LICENSE,
+CONTRIBUTING.md, .githooks y docs/adr pertenecen a la raíz. 'grei verify' verifica luego cada componente con
+su propio 'grei.yml'.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			rootPath := "."
+			if len(args) > 1 {
+				rootPath = args[1]
+			}
+			if err := recipe.CheckComponentName(name); err != nil {
+				return err
+			}
+			if err := applyConflictPolicy(cmd, conflictRepo); err != nil {
+				return err
+			}
+
+			recipePath := filepath.Join(rootPath, "grei.yml")
+			recipeData, err := os.ReadFile(recipePath)
+			if err != nil {
+				return fmt.Errorf("no se pudo leer el archivo 'grei.yml' en '%s'. Asegúrate de que el repositorio ha sido inicializado", rootPath)
+			}
+			document, rootRecipe, err := loadRecipe(recipePath, recipeData)
+			if err != nil {
+				return err
+			}
+			if document == nil || rootRecipe.APIVersion != recipe.APIVersion {
+				return fmt.Errorf("%s usa un formato anterior; ejecuta 'grei recipe migrate' primero", recipePath)
+			}
+			if rootRecipe.Component(name) != nil {
+				return fmt.Errorf("el componente '%s' ya existe en %s", name, recipePath)
+			}
+
+			component := recipe.Component{Name: name, Path: path.Join(recipe.ComponentsDir, name)}
+			if dir, _ := cmd.Flags().GetString("path"); dir != "" {
+				component.Path = path.Clean(filepath.ToSlash(dir))
+			}
+			rootRecipe.Components = append(rootRecipe.Components, component)
+			if err := rootRecipe.CheckComponents(); err != nil {
+				return err
+			}
+			componentPath := filepath.Join(rootPath, filepath.FromSlash(component.Path))
+			if _, err := os.Stat(filepath.Join(componentPath, "grei.yml")); err == nil {
+				return fmt.Errorf("'%s' ya contiene un proyecto 'grei' (grei.yml encontrado)", componentPath)
+			}
+
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("error getting user home directory: %w", err)
+			}
+			bundle, err := LoadTemplates(cmd, filepath.Join(homeDir, ".grei"), true, rootRecipe.Templates)
+			if err != nil {
+				return err
+			}
+
+			answers := recipe.Recipe{
+				APIVersion: recipe.APIVersion,
+				Project:    recipe.Project{Name: name, Customer: rootRecipe.Project.Customer},
+			}
+			if err := askComponentStack(cmd, bundle.FS, &answers); err != nil {
+				return err
+			}
+			answers.Templates = PinTemplates(bundle)
+
+			if err := recipe.AddComponent(document, component); err != nil {
+				return fmt.Errorf("no se pudo agregar el componente a %s: %w", recipePath, err)
+			}
+			rootData, err := recipe.Encode(document, recipe.Indentation(recipeData))
+			if err != nil {
+				return err
+			}
+
+			if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+				return previewComponent(previewService, rootPath, component, bundle, &answers, rootData)
+			}
+
+			componentData, err := yaml.Marshal(&answers)
+			if err != nil {
+				return fmt.Errorf("error al generar el archivo YAML: %w", err)
+			}
+			// Repository-level files, git and the generic skeleton stay with
+			// the root: the component only gets its stack.
+			if err := initializerService.AdoptProject(componentPath, bundle.FS, false); err != nil {
+				return fmt.Errorf("error durante la inicialización: %w", err)
+			}
+			if err := os.MkdirAll(componentPath, 0755); err != nil {
+				return fmt.Errorf("error al crear el directorio '%s': %w", componentPath, err)
+			}
+			if err := os.WriteFile(filepath.Join(componentPath, "grei.yml"), componentData, 0644); err != nil {
+				return fmt.Errorf("error al escribir el archivo grei.yml: %w", err)
+			}
+			if err := scaffolderService.ScaffoldComponent(componentPath, bundle.FS, &answers); err != nil {
+				return fmt.Errorf("error durante el scaffolding: %w", err)
+			}
+			printConflictSummary(conflictRepo.Summary())
+
+			if err := os.WriteFile(recipePath, rootData, 0644); err != nil {
+				return fmt.Errorf("error al escribir el archivo grei.yml: %w", err)
+			}
+			color.Green("¡Componente '%s' (%s) creado en '%s'!", name, answers.Project.Type, componentPath)
+			return nil
+		},
+	}
+	cmd.Flags().String("stack", "", "Pila de código del componente, p. ej. typescript-angular. Se pregunta si no se indica.")
+	cmd.Flags().String("path", "", "Directorio del componente, relativo a la raíz del repositorio (por defecto apps/<name>).")
+	cmd.Flags().Bool("no-interactive", false, "No pregunta nada: usa --stack y los valores por defecto de sus opciones.")
+	cmd.Flags().Bool("dry-run", false, "Muestra los archivos que se crearían o modificarían, con sus diferencias, sin escribir nada.")
+	addConflictFlag(cmd)
+	addTemplatesFlag(cmd)
+	cmd.Flags().String("templates-ref", "", "Rama, etiqueta o commit del repositorio de plantillas a usar, p. ej. v1.2.0")
+	return cmd
+}
+
+// askComponentStack fills in the stack of a new component and the answers
+// to its options, from --stack and the option defaults when not
+// interactive.
+func askComponentStack(cmd *cobra.Command, templatesFS fs.FS, answers *recipe.Recipe) error {
+	codeStacks, _, _ := CategorizeStacks(templatesFS)
+	stack, _ := cmd.Flags().GetString("stack")
+	nonInteractive, _ := cmd.Flags().GetBool("no-interactive")
+
+	switch {
+	case stack != "":
+		known := false
+		for _, s := range codeStacks {
+			known = known || s == stack
+		}
+		if !known {
+			return fmt.Errorf("la pila '%s' no existe en las plantillas", stack)
+		}
+	case nonInteractive:
+		return fmt.Errorf("--stack es requerido en modo no interactivo")
+	default:
+		prompt := &survey.Select{Message: "¿Qué pila de código usará el componente?", Options: codeStacks}
+		if err := survey.AskOne(prompt, &stack); err != nil {
+			return fmt.Errorf("error durante la encuesta: %w", err)
+		}
+	}
+	answers.Project.Type = stack
+
+	manifest, err := FindManifest(templatesFS, stack)
+	if err != nil || manifest == nil {
+		return err
+	}
+	if !nonInteractive {
+		return askOptions(manifest.Options, answers)
+	}
+	if err := manifest.Options.ApplyOptions(answers); err != nil {
+		return fmt.Errorf("la receta no es válida para '%s': %w", stack, err)
+	}
+	return nil
+}
+
+// previewComponent shows the files a new component would get, and the
+// change to the root grei.yml.
+func previewComponent(previewService inbound.PreviewService, rootPath string, component recipe.Component, bundle *outbound.TemplateBundle, answers *recipe.Recipe, rootData []byte) error {
+	rendered, err := renderComponent(bundle, answers)
+	if err != nil {
+		return fmt.Errorf("error al generar el componente: %w", err)
+	}
+	componentData, err := yaml.Marshal(answers)
+	if err != nil {
+		return fmt.Errorf("error al generar el archivo YAML: %w", err)
+	}
+
+	files := map[string][]byte{
+		"grei.yml":                            rootData,
+		path.Join(component.Path, "grei.yml"): componentData,
+	}
+	for file, content := range rendered.Files() {
+		files[path.Join(component.Path, file)] = content
+	}
+	var dirs []string
+	for _, dir := range rendered.Dirs() {
+		dirs = append(dirs, path.Join(component.Path, dir))
+	}
+	if _, err := previewService.Preview(inbound.PreviewOptions{
+		Path:  rootPath,
+		Files: files,
+		Dirs:  dirs,
+	}); err != nil {
+		return fmt.Errorf("error al comparar con los archivos existentes: %w", err)
+	}
+	color.Cyan("Simulación: no se escribió nada.")
+	return nil
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"fmt"
+	"grei-cli/internal/adapters/filesystem"
+	"grei-cli/internal/core/diff"
+	"os"
+	"sort"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+const (
+	conflictOptionOverwrite    = "Sobrescribir"
+	conflictOptionSkip         = "Conservar el archivo actual"
+	conflictOptionBackup       = "Respaldar en .bak y sobrescribir"
+	conflictOptionDiff         = "Ver diferencias"
+	conflictOptionOverwriteAll = "Sobrescribir este y los siguientes"
+	conflictOptionSkipAll      = "Conservar este y los siguientes"
+	conflictOptionBackupAll    = "Respaldar este y los siguientes"
+)
+
+// addConflictFlag registers the --on-conflict flag on cmd.
+func addConflictFlag(cmd *cobra.Command) {
+	cmd.Flags().String("on-conflict", string(filesystem.ConflictPrompt), "Qué hacer con los archivos existentes que cambiarían: skip, overwrite, prompt o backup")
+}
+
+// applyConflictPolicy sets the policy chosen with --on-conflict on repo. The
+// prompt policy only asks when a terminal is attached; otherwise existing
+// files are kept.
+func applyConflictPolicy(cmd *cobra.Command, repo *filesystem.ConflictRepository) error {
+	name, _ := cmd.Flags().GetString("on-conflict")
+	policy, err := filesystem.ParseConflictPolicy(name)
+	if err != nil {
+		return fmt.Errorf("valor inválido para --on-conflict: %w", err)
+	}
+	if policy == filesystem.ConflictPrompt && !isTerminal() {
+		color.Yellow("No hay una terminal interactiva: se conservarán los archivos existentes que cambiarían (--on-conflict=skip).")
+		policy = filesystem.ConflictSkip
+	}
+	repo.Policy = policy
+	return nil
+}
+
+// isTerminal reports whether both stdin and stdout are attached to a
+// terminal, which survey needs to prompt.
+func isTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// promptConflict asks the user how to resolve a conflicting file.
+func promptConflict(path string, current, content []byte) (filesystem.ConflictPolicy, bool, error) {
+	for {
+		var answer string
+		prompt := &survey.Select{
+			Message: fmt.Sprintf("'%s' ya existe y es distinto a la plantilla. ¿Qué hacer?", path),
+			Options: []string{
+				conflictOptionSkip,
+				conflictOptionOverwrite,
+				conflictOptionBackup,
+				conflictOptionDiff,
+				conflictOptionSkipAll,
+				conflictOptionOverwriteAll,
+				conflictOptionBackupAll,
+			},
+		}
+		if err := survey.AskOne(prompt, &answer); err != nil {
+			return "", false, fmt.Errorf("error durante la encuesta: %w", err)
+		}
+
+		switch answer {
+		case conflictOptionSkip:
+			return filesystem.ConflictSkip, false, nil
+		case conflictOptionOverwrite:
+			return filesystem.ConflictOverwrite, false, nil
+		case conflictOptionBackup:
+			return filesystem.ConflictBackup, false, nil
+		case conflictOptionSkipAll:
+			return filesystem.ConflictSkip, true, nil
+		case conflictOptionOverwriteAll:
+			return filesystem.ConflictOverwrite, true, nil
+		case conflictOptionBackupAll:
+			return filesystem.ConflictBackup, true, nil
+		}
+		fmt.Print(diff.Unified("a/"+path, "b/"+path, current, content))
+	}
+}
+
+// printConflictSummary lists the existing files that were skipped,
+// overwritten or backed up.
+func printConflictSummary(summary filesystem.ConflictSummary) {
+	if len(summary.Skipped) > 0 {
+		color.Yellow("Archivos existentes conservados (%d):", len(summary.Skipped))
+		for _, path := range summary.Skipped {
+			fmt.Printf("  - %s\n", path)
+		}
+	}
+	if len(summary.Overwritten) > 0 {
+		color.Yellow("Archivos existentes sobrescritos (%d):", len(summary.Overwritten))
+		for _, path := range summary.Overwritten {
+			fmt.Printf("  - %s\n", path)
+		}
+	}
+	if len(summary.BackedUp) > 0 {
+		color.Yellow("Archivos existentes sobrescritos con respaldo (%d):", len(summary.BackedUp))
+		paths := make([]string, 0, len(summary.BackedUp))
+		for path := range summary.BackedUp {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			fmt.Printf("  - %s -> %s\n", path, summary.BackedUp[path])
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"grei-cli/internal/core/detector"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// AddDetectCommand adds the detect command to the root command.
+func AddDetectCommand(root *cobra.Command) {
+	cmd := NewDetectCommand()
+	cmd.Flags().Bool("json", false, "Muestra la salida en formato JSON.")
+	root.AddCommand(cmd)
+}
+
+// NewDetectCommand creates a new detect command.
+func NewDetectCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "detect [path]",
+		Short: "Detecta la pila de un proyecto existente a partir de sus archivos.",
+		Long: `Inspecciona los manifiestos, lockfiles y archivos de configuración del proyecto
+para inferir su lenguaje, framework, pruebas, linter, gestor de paquetes, CI y
+persistencia, cada uno con un grado de confianza entre 0 y 1. No necesita
+'grei.yml'.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			targetPath := "."
+			if len(args) > 0 {
+				targetPath = args[0]
+			}
+			if info, err := os.Stat(targetPath); err != nil || !info.IsDir() {
+				return fmt.Errorf("'%s' no es un directorio existente", targetPath)
+			}
+
+			detection, err := detector.Detect(os.DirFS(targetPath))
+			if err != nil {
+				return fmt.Errorf("no se pudo analizar el proyecto: %w", err)
+			}
+
+			if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+				data, err := json.MarshalIndent(detection, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(data))
+				return nil
+			}
+			printDetection(cmd.OutOrStdout(), detection)
+			return nil
+		},
+	}
+}
+
+func printDetection(out io.Writer, detection *detector.Detection) {
+	if detection.Stack != nil {
+		fmt.Fprintf(out, "%s %s\n", color.New(color.Bold).Sprint("Pila:"), formatFinding(*detection.Stack))
+	} else {
+		fmt.Fprintf(out, "%s %s\n", color.New(color.Bold).Sprint("Pila:"), "no reconocida")
+	}
+	for _, aspect := range []struct {
+		label    string
+		findings []detector.Finding
+	}{
+		{"Lenguaje", detection.Language},
+		{"Framework", detection.Framework},
+		{"Pruebas", detection.Tests},
+		{"Linter", detection.Linter},
+		{"Gestor de paquetes", detection.PackageManager},
+		{"CI", detection.CI},
+		{"Persistencia", detection.Persistence},
+	} {
+		if len(aspect.findings) == 0 {
+			fmt.Fprintf(out, "%s -\n", color.New(color.Bold).Sprint(aspect.label+":"))
+			continue
+		}
+		fmt.Fprintf(out, "%s %s\n", color.New(color.Bold).Sprint(aspect.label+":"), formatFinding(aspect.findings[0]))
+		for _, f := range aspect.findings[1:] {
+			fmt.Fprintf(out, "  también: %s\n", formatFinding(f))
+		}
+	}
+}
+
+func formatFinding(f detector.Finding) string {
+	return fmt.Sprintf("%s (%.0f%%) — %s", f.Value, f.Confidence*100, strings.Join(f.Evidence, ", "))
+}
@@ -1,14 +1,20 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
-	"grei-cli/internal/adapters/downloader"
 	"grei-cli/internal/adapters/filesystem"
 	"grei-cli/internal/adapters/git"
+	"grei-cli/internal/adapters/shell"
+	"grei-cli/internal/core/hooks"
 	"grei-cli/internal/core/initializer"
+	"grei-cli/internal/core/posthooks"
+	"grei-cli/internal/core/preview"
 	"grei-cli/internal/core/recipe"
 	"grei-cli/internal/core/scaffolder"
 	"grei-cli/internal/ports/inbound"
+	"grei-cli/internal/ports/outbound"
+	"io/fs"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -29,25 +35,50 @@ var (
 // AddInitCommand adds the init command to the root command.
 func AddInitCommand(root *cobra.Command) {
 	fsRepo := filesystem.NewRepository()
+	conflictRepo := filesystem.NewConflictRepository(fsRepo, filesystem.ConflictPrompt, promptConflict)
 	gitRepo := git.NewRepository()
-	initializerService := initializer.NewService(fsRepo, gitRepo)
-	scaffolderService := scaffolder.NewService(fsRepo)
+	initializerService := initializer.NewService(conflictRepo, gitRepo)
+	scaffolderService := scaffolder.NewService(conflictRepo)
+	previewService := preview.NewService(fsRepo)
+	postHooksService := posthooks.NewService(shell.NewRunner(), os.Stdout, os.Stderr)
+	hooksService := hooks.NewService(gitRepo)
 
-	cmd := NewInitCommand(initializerService, scaffolderService)
+	cmd := NewInitCommand(initializerService, scaffolderService, previewService, postHooksService, hooksService, conflictRepo)
 	cmd.Flags().BoolVar(&noInteractive, "no-interactive", false, "Desactiva el modo interactivo y usa un archivo de receta")
 	cmd.Flags().StringVar(&recipeFile, "recipe-file", "", "Ruta al archivo de receta (grei.yml) para usar en modo no interactivo")
+	cmd.Flags().Bool("dry-run", false, "Muestra los archivos que se crearían o modificarían, con sus diferencias, sin escribir nada.")
+	cmd.Flags().Bool("adopt", false, "Adopta un repositorio existente: detecta su pila, propone un grei.yml y agrega solo los archivos estándar que falten.")
+	cmd.Flags().Bool("skip-post-hooks", false, "No ejecuta los pasos posteriores a la generación de la pila (npm install, go mod tidy, ...).")
+	cmd.Flags().Duration("post-hooks-timeout", 10*time.Minute, "Tiempo máximo de cada paso posterior a la generación que no declara el suyo.")
+	cmd.Flags().String("remote", "", "URL del repositorio remoto a agregar como 'origin', p. ej. git@bitbucket.org:greicodex/proyecto.git")
+	addConflictFlag(cmd)
+	addTemplatesFlag(cmd)
+	cmd.Flags().String("templates-ref", "", "Rama, etiqueta o commit del repositorio de plantillas a usar, p. ej. v1.2.0")
 	root.AddCommand(cmd)
 }
 
 // NewInitCommand creates a new init command with its dependencies.
-func NewInitCommand(initializerService inbound.InitializerService, scaffolderService inbound.ScaffolderService) *cobra.Command {
+func NewInitCommand(initializerService inbound.InitializerService, scaffolderService inbound.ScaffolderService, previewService inbound.PreviewService, postHooksService inbound.PostHooksService, hooksService inbound.HooksService, conflictRepo *filesystem.ConflictRepository) *cobra.Command {
 	return &cobra.Command{
 		Use:   "init [path]",
 		Short: "Inicializa un nuevo proyecto con la estructura estándar de Greicodex.",
 		Long: `Crea un nuevo proyecto con una estructura de directorios estándar,
 archivos de configuración y plantillas iniciales. Este comando te guiará
 a través de una serie de preguntas para configurar el 'grei.yml', el archivo
-de receta del proyecto.`,
+de receta del proyecto.
+
+Con --adopt trabaja sobre un repositorio existente: detecta su pila (go.mod,
+package.json, composer.json, pyproject.toml), propone un 'grei.yml' y agrega
+solo los archivos estándar que falten (LICENSE, CONTRIBUTING.md, .githooks,
+docs/adr), sin modificar ningún archivo existente. No crea ningún commit:
+los archivos existentes deben revisarse y registrarse a mano, para que el
+hook pre-commit busque secretos en ellos.
+
+Tras generar los archivos, init ejecuta los pasos posteriores que declara la
+pila en su manifiesto (p. ej. npm install o go mod tidy), mostrando su salida.
+Usa --skip-post-hooks para omitirlos. Por último registra los archivos
+generados en un commit inicial en 'main', crea 'develop' a partir de él,
+instala los Git hooks y, con --remote, agrega el repositorio remoto 'origin'.`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			homeDir, err := os.UserHomeDir()
@@ -56,13 +87,6 @@ de receta del proyecto.`,
 			}
 			cacheDir := filepath.Join(homeDir, ".grei")
 
-			downloader := downloader.NewGitDownloader()
-
-			color.Blue("Downloading templates...")
-			if err := downloader.Download(cmd.Context(), "https://github.com/GreicodexJM/greicodex-cli.git", "master", cacheDir); err != nil {
-				color.Yellow("Could not download remote templates: %v", err)
-			}
-
 			targetPath := "."
 			if len(args) > 0 {
 				targetPath = args[0]
@@ -73,28 +97,40 @@ de receta del proyecto.`,
 				return fmt.Errorf("este directorio ya contiene un proyecto 'grei' (grei.yml encontrado)")
 			}
 
+			if adopt, _ := cmd.Flags().GetBool("adopt"); adopt {
+				return adoptProject(cmd, targetPath, cacheDir, initializerService, scaffolderService, previewService, hooksService)
+			}
+
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			if err := applyConflictPolicy(cmd, conflictRepo); err != nil {
+				return err
+			}
 			answers := recipe.Recipe{}
+			var bundle *outbound.TemplateBundle
 
 			if noInteractive {
-				if err := os.MkdirAll(targetPath, os.ModePerm); err != nil {
-					return fmt.Errorf("error al crear el directorio del proyecto: %w", err)
+				if !dryRun {
+					if err := os.MkdirAll(targetPath, os.ModePerm); err != nil {
+						return fmt.Errorf("error al crear el directorio del proyecto: %w", err)
+					}
 				}
 				if recipeFile == "" {
 					return fmt.Errorf("--recipe-file es requerido en modo no interactivo")
 				}
-				yamlFile, err := os.ReadFile(recipeFile)
+				answers, bundle, err = loadRecipeFile(cmd, cacheDir, recipeFile)
 				if err != nil {
-					return fmt.Errorf("error al leer el archivo de receta: %w", err)
+					return err
 				}
-				err = yaml.Unmarshal(yamlFile, &answers)
+			} else {
+				bundle, err = LoadTemplates(cmd, cacheDir, true, nil)
 				if err != nil {
-					return fmt.Errorf("error al parsear el archivo de receta: %w", err)
+					return err
 				}
-			} else {
+
 				fmt.Println("🚀 ¡Bienvenido al inicializador de proyectos de Greicodex!")
 				fmt.Println("---------------------------------------------------------")
 
-				codeStacks, _, _ := CategorizeStacks(cacheDir)
+				codeStacks, _, _ := CategorizeStacks(bundle.FS)
 
 				projectQuestions := []*survey.Question{
 					{
@@ -120,41 +156,25 @@ de receta del proyecto.`,
 					return fmt.Errorf("error durante la encuesta: %w", err)
 				}
 
-				answers.Stack = make(map[string]interface{})
-				skeletonsRoot := filepath.Join(cacheDir, "templates", "skeletons")
-				err = filepath.Walk(skeletonsRoot, func(path string, info os.FileInfo, err error) error {
-					if err != nil {
+				manifest, err := FindManifest(bundle.FS, answers.Project.Type)
+				if err != nil {
+					return err
+				}
+				if manifest != nil {
+					if err := askOptions(manifest.Options, &answers); err != nil {
 						return err
 					}
+				}
+			}
 
-					if !info.IsDir() && info.Name() == "manifest.yml" {
-						manifest, err := GetManifest(path)
-						if err != nil {
-							return err
-						}
-
-						if manifest.Name == answers.Project.Type {
-							for key, value := range manifest.Options {
-								question := &survey.Question{
-									Name: key,
-									Prompt: &survey.Select{
-										Message: value.Message,
-										Options: value.Values,
-									},
-								}
-								var option string
-								if err := survey.Ask([]*survey.Question{question}, &option); err != nil {
-									return fmt.Errorf("error durante la encuesta: %w", err)
-								}
-								answers.Stack[key] = option
-							}
-						}
-					}
-					return nil
-				})
-				if err != nil {
+			answers.APIVersion = recipe.APIVersion
+			answers.Templates = PinTemplates(bundle)
+
+			if dryRun {
+				if err := previewInit(previewService, targetPath, bundle, &answers); err != nil {
 					return err
 				}
+				return previewPostHooks(cmd, scaffolderService, bundle, &answers)
 			}
 
 			s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
@@ -175,13 +195,25 @@ de receta del proyecto.`,
 			s.Stop()
 			color.Green("✅ Receta del proyecto creada exitosamente en '%s'.", recipePath)
 
-			if err := initializerService.InitializeProject(targetPath, cacheDir, true, &answers); err != nil {
+			if err := initializerService.InitializeProject(targetPath, bundle.FS, true, &answers); err != nil {
 				return fmt.Errorf("error durante la inicialización: %w", err)
 			}
 
-			if err := scaffolderService.Scaffold(targetPath, cacheDir, &answers); err != nil {
+			if err := scaffolderService.Scaffold(targetPath, bundle.FS, &answers); err != nil {
 				return fmt.Errorf("error durante el scaffolding: %w", err)
 			}
+			printConflictSummary(conflictRepo.Summary())
+
+			if skip, _ := cmd.Flags().GetBool("skip-post-hooks"); !skip {
+				if err := runPostHooks(cmd, scaffolderService, postHooksService, targetPath, bundle, &answers); err != nil {
+					return err
+				}
+			}
+
+			remote, _ := cmd.Flags().GetString("remote")
+			if err := setupRepository(initializerService, hooksService, targetPath, remote); err != nil {
+				return err
+			}
 
 			fmt.Println("\n🚀 ¡Proyecto inicializado exitosamente!")
 			return nil
@@ -189,21 +221,108 @@ de receta del proyecto.`,
 	}
 }
 
-func CategorizeStacks(cacheDir string) ([]string, []string, []string) {
+// setupRepository installs the Git hooks of a new project at targetPath,
+// so that they are committed executable, makes its initial commit and
+// creates develop. A commit git refuses, e.g. for lack of an identity, is
+// reported with the commands to finish by hand instead of failing init, as
+// the project itself is complete.
+func setupRepository(initializerService inbound.InitializerService, hooksService inbound.HooksService, targetPath, remote string) error {
+	if err := hooksService.InstallHooks(targetPath); err != nil {
+		return fmt.Errorf("error al instalar los Git hooks: %w", err)
+	}
+
+	err := initializerService.SetupRepository(targetPath, remote)
+	if errors.Is(err, initializer.ErrInitialCommit) {
+		color.Yellow("⚠️  No se pudo crear el commit inicial: %v", err)
+		color.Yellow("Configura tu identidad (git config --global user.name / user.email) y ejecuta en '%s':", targetPath)
+		fmt.Printf("  git commit -m %q && git checkout -b %s\n", initializer.InitialCommitMessage, initializer.DevelopBranch)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error al preparar el repositorio git: %w", err)
+	}
+	return nil
+}
+
+// loadRecipeFile reads the recipe at path for a non-interactive init,
+// loads the templates it is pinned to, validates it and fills in the
+// defaults of the options it does not answer.
+func loadRecipeFile(cmd *cobra.Command, cacheDir, path string) (recipe.Recipe, *outbound.TemplateBundle, error) {
+	yamlFile, err := os.ReadFile(path)
+	if err != nil {
+		return recipe.Recipe{}, nil, fmt.Errorf("error al leer el archivo de receta: %w", err)
+	}
+	document, fileRecipe, err := loadRecipe(path, yamlFile)
+	if err != nil {
+		return recipe.Recipe{}, nil, err
+	}
+	answers := *fileRecipe
+
+	// A recipe from an existing project reuses its pinned templates.
+	bundle, err := LoadTemplates(cmd, cacheDir, true, answers.Templates)
+	if err != nil {
+		return recipe.Recipe{}, nil, err
+	}
+	if err := validateRecipe(bundle.FS, path, document); err != nil {
+		return recipe.Recipe{}, nil, err
+	}
+
+	manifest, err := FindManifest(bundle.FS, answers.Project.Type)
+	if err != nil {
+		return recipe.Recipe{}, nil, err
+	}
+	if manifest != nil {
+		if err := manifest.Options.ApplyOptions(&answers); err != nil {
+			return recipe.Recipe{}, nil, fmt.Errorf("la receta no es válida para '%s': %w", answers.Project.Type, err)
+		}
+	}
+	return answers, bundle, nil
+}
+
+// previewInit renders the project in memory and shows what init would write
+// to targetPath, including the recipe itself.
+func previewInit(previewService inbound.PreviewService, targetPath string, bundle *outbound.TemplateBundle, answers *recipe.Recipe) error {
+	yamlData, err := yaml.Marshal(answers)
+	if err != nil {
+		return fmt.Errorf("error al generar el archivo YAML: %w", err)
+	}
+
+	rendered, err := renderProject(bundle, answers)
+	if err != nil {
+		return fmt.Errorf("error al generar el proyecto: %w", err)
+	}
+	files := rendered.Files()
+	files["grei.yml"] = yamlData
+
+	if _, err := previewService.Preview(inbound.PreviewOptions{
+		Path:  targetPath,
+		Files: files,
+		Dirs:  rendered.Dirs(),
+	}); err != nil {
+		return fmt.Errorf("error al comparar con los archivos existentes: %w", err)
+	}
+	color.Cyan("Simulación: no se escribió nada ni se inicializó git.")
+	return nil
+}
+
+func CategorizeStacks(templatesFS fs.FS) ([]string, []string, []string) {
 	var codeStacks []string
-	skeletonsRoot := filepath.Join(cacheDir, "templates", "skeletons")
 
-	err := filepath.Walk(skeletonsRoot, func(path string, info os.FileInfo, err error) error {
+	err := fs.WalkDir(templatesFS, "skeletons", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
-		if !info.IsDir() && info.Name() == "manifest.yml" {
-			manifest, err := GetManifest(path)
+		if !d.IsDir() && d.Name() == "manifest.yml" {
+			manifest, err := GetManifest(templatesFS, path)
 			if err != nil {
 				color.Yellow("...skip %v", err)
 				return nil
 			}
+			// Mixins are only included by other skeletons.
+			if manifest.Type == scaffolder.MixinType {
+				return nil
+			}
 			codeStacks = append(codeStacks, manifest.Name)
 		}
 		return nil
@@ -217,8 +336,8 @@ func CategorizeStacks(cacheDir string) ([]string, []string, []string) {
 	return codeStacks, nil, nil
 }
 
-func GetManifest(manifestPath string) (*scaffolder.Manifest, error) {
-	manifestFile, err := os.ReadFile(manifestPath)
+func GetManifest(templatesFS fs.FS, manifestPath string) (*scaffolder.Manifest, error) {
+	manifestFile, err := fs.ReadFile(templatesFS, manifestPath)
 	if err != nil {
 		return nil, err
 	}
@@ -231,6 +350,32 @@ func GetManifest(manifestPath string) (*scaffolder.Manifest, error) {
 	return &manifest, nil
 }
 
+// FindManifest returns the manifest of the skeleton named name, or nil if no
+// skeleton in the templates declares it.
+func FindManifest(templatesFS fs.FS, name string) (*scaffolder.Manifest, error) {
+	var found *scaffolder.Manifest
+	err := fs.WalkDir(templatesFS, "skeletons", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || found != nil {
+			return err
+		}
+
+		if !d.IsDir() && d.Name() == "manifest.yml" {
+			manifest, err := GetManifest(templatesFS, path)
+			if err != nil {
+				return err
+			}
+			if manifest.Name == name {
+				found = manifest
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return found, nil
+}
+
 var adjectives = []string{
 	"Adaptable", "Agil", "Alegre", "Ambicioso", "Amable", "Audaz", "Brillante", "Calmado", "Capaz", "Carismatico",
 	"Compasivo", "Confiable", "Creativo", "Curioso", "Decidido", "Dedicado", "Dinamico", "Eficiente", "Elegante", "Empatico",
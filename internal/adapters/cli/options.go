@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"fmt"
+	"grei-cli/internal/core/recipe"
+	"grei-cli/internal/core/scaffolder"
+	"regexp"
+
+	"github.com/AlecAivazis/survey/v2"
+)
+
+// askOptions asks the stack options in order, skipping those whose
+// condition does not hold for the answers given so far.
+func askOptions(options scaffolder.Options, answers *recipe.Recipe) error {
+	for i := range options {
+		option := &options[i]
+		if err := option.Check(); err != nil {
+			return fmt.Errorf("el manifiesto de '%s' no es válido: %w", answers.Project.Type, err)
+		}
+		applies, err := option.Applies(answers)
+		if err != nil {
+			return err
+		}
+		if !applies {
+			continue
+		}
+
+		value, err := askOption(option)
+		if err != nil {
+			return fmt.Errorf("error durante la encuesta: %w", err)
+		}
+		normalized, err := option.Normalize(value)
+		if err != nil {
+			return err
+		}
+		if err := answers.Stack.Set(option.Name, normalized); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func askOption(option *scaffolder.Option) (interface{}, error) {
+	message := option.Message
+	if message == "" {
+		message = option.Name
+	}
+	def := option.DefaultValue()
+
+	switch option.Kind() {
+	case scaffolder.OptionMultiSelect:
+		prompt := &survey.MultiSelect{Message: message, Options: option.Values, Help: option.Help}
+		if selected := multiSelectDefault(option); selected != nil {
+			prompt.Default = selected
+		}
+		var answer []string
+		err := survey.AskOne(prompt, &answer)
+		return answer, err
+
+	case scaffolder.OptionConfirm:
+		prompt := &survey.Confirm{Message: message, Help: option.Help}
+		if b, ok := def.(bool); ok {
+			prompt.Default = b
+		}
+		var answer bool
+		err := survey.AskOne(prompt, &answer)
+		return answer, err
+
+	case scaffolder.OptionInput:
+		prompt := &survey.Input{Message: message, Help: option.Help}
+		if s, ok := def.(string); ok {
+			prompt.Default = s
+		}
+		var validators []survey.AskOpt
+		if def == nil {
+			validators = append(validators, survey.WithValidator(survey.Required))
+		}
+		if option.Pattern != "" {
+			pattern := regexp.MustCompile(option.Pattern)
+			validators = append(validators, survey.WithValidator(func(answer interface{}) error {
+				if s, _ := answer.(string); !pattern.MatchString(s) {
+					return fmt.Errorf("el valor debe cumplir el patrón %s", option.Pattern)
+				}
+				return nil
+			}))
+		}
+		var answer string
+		err := survey.AskOne(prompt, &answer, validators...)
+		return answer, err
+
+	default:
+		prompt := &survey.Select{Message: message, Options: option.Values, Help: option.Help}
+		if def != nil {
+			prompt.Default = def
+		}
+		var answer string
+		err := survey.AskOne(prompt, &answer)
+		return answer, err
+	}
+}
+
+// multiSelectDefault returns the default of a multiselect option as the
+// []string survey expects; YAML decodes lists as []interface{}, which survey
+// ignores. It returns nil when the option has no valid default.
+func multiSelectDefault(option *scaffolder.Option) []string {
+	def := option.DefaultValue()
+	if def == nil {
+		return nil
+	}
+	normalized, err := option.Normalize(def)
+	if err != nil {
+		return nil
+	}
+	selected, _ := normalized.([]string)
+	return selected
+}
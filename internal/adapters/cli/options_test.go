@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"grei-cli/internal/core/scaffolder"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMultiSelectDefault(t *testing.T) {
+	var manifest scaffolder.Manifest
+	err := yaml.Unmarshal([]byte(`
+name: test-stack
+options:
+  features:
+    type: multiselect
+    values: ["auth", "metrics", "tracing"]
+    default: ["metrics", "tracing"]
+  extras:
+    type: multiselect
+    values: ["auth", "metrics"]
+  invalid:
+    type: multiselect
+    values: ["auth", "metrics"]
+    default: ["logging"]
+`), &manifest)
+	if err != nil {
+		t.Fatalf("Failed to parse the manifest: %v", err)
+	}
+
+	want := map[string][]string{
+		"features": {"metrics", "tracing"},
+		"extras":   nil,
+		"invalid":  nil,
+	}
+	for i := range manifest.Options {
+		option := &manifest.Options[i]
+		if got := multiSelectDefault(option); !reflect.DeepEqual(got, want[option.Name]) {
+			t.Errorf("multiSelectDefault(%s) = %#v, want %#v", option.Name, got, want[option.Name])
+		}
+	}
+}
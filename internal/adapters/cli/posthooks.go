@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"grei-cli/internal/core/posthooks"
+	"grei-cli/internal/core/recipe"
+	"grei-cli/internal/ports/inbound"
+	"grei-cli/internal/ports/outbound"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// runPostHooks runs the post-generate steps of the new project at
+// targetPath. When a step fails, the files stay as generated and the error
+// lists the steps left to run by hand.
+func runPostHooks(cmd *cobra.Command, scaffolderService inbound.ScaffolderService, postHooksService inbound.PostHooksService, targetPath string, bundle *outbound.TemplateBundle, answers *recipe.Recipe) error {
+	steps, err := scaffolderService.PostGenerateSteps(bundle.FS, answers)
+	if err != nil {
+		return fmt.Errorf("no se pudieron leer los pasos posteriores a la generación: %w", err)
+	}
+	timeout, _ := cmd.Flags().GetDuration("post-hooks-timeout")
+
+	err = postHooksService.RunPostHooks(targetPath, steps, timeout)
+	var stepErr *posthooks.StepError
+	if !errors.As(err, &stepErr) {
+		return err
+	}
+
+	pending := make([]string, 0, len(steps)-stepErr.Index)
+	for _, step := range steps[stepErr.Index:] {
+		pending = append(pending, fmt.Sprintf("  (cd %s && %s)", filepath.Join(targetPath, filepath.FromSlash(step.Dir)), step.Run))
+	}
+	return fmt.Errorf("el proyecto se generó en '%s', pero falló el paso '%s': %w\nCorrige el problema y ejecuta a mano los pasos pendientes:\n%s",
+		targetPath, stepErr.Step.Name, stepErr.Err, strings.Join(pending, "\n"))
+}
+
+// previewPostHooks lists the post-generate steps init would run.
+func previewPostHooks(cmd *cobra.Command, scaffolderService inbound.ScaffolderService, bundle *outbound.TemplateBundle, answers *recipe.Recipe) error {
+	if skip, _ := cmd.Flags().GetBool("skip-post-hooks"); skip {
+		return nil
+	}
+	steps, err := scaffolderService.PostGenerateSteps(bundle.FS, answers)
+	if err != nil {
+		return fmt.Errorf("no se pudieron leer los pasos posteriores a la generación: %w", err)
+	}
+	if len(steps) == 0 {
+		return nil
+	}
+	color.Cyan("Pasos posteriores que se ejecutarían:")
+	for _, step := range steps {
+		fmt.Printf("  $ %s  (en %s)\n", step.Run, step.Dir)
+	}
+	return nil
+}
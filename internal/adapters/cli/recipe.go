@@ -0,0 +1,266 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"grei-cli/internal/adapters/filesystem"
+	"grei-cli/internal/core/recipe"
+	"grei-cli/internal/core/scaffolder"
+	"grei-cli/internal/core/schema"
+	"grei-cli/internal/ports/inbound"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// AddRecipeCommand adds the recipe command, which groups the commands that
+// work on grei.yml, to the root command.
+func AddRecipeCommand(root *cobra.Command) {
+	scaffolderService := scaffolder.NewService(filesystem.NewRepository())
+
+	cmd := NewRecipeCommand(scaffolderService)
+	root.AddCommand(cmd)
+}
+
+// NewRecipeCommand creates the recipe command and its subcommands.
+func NewRecipeCommand(scaffolderService inbound.ScaffolderService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recipe",
+		Short: "Comandos para trabajar con la receta del proyecto (grei.yml).",
+	}
+
+	validate := &cobra.Command{
+		Use:   "validate [path]",
+		Short: "Valida 'grei.yml' contra el esquema de recetas y las opciones de su pila.",
+		Long: `Comprueba que 'grei.yml' tenga los campos y tipos esperados y que los valores
+de la sección 'stack' sean opciones válidas de la pila del proyecto. Los errores
+indican la línea y la columna de cada problema. path puede ser el directorio del
+proyecto o el archivo de receta.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			recipePath := recipeFileArg(args)
+
+			data, err := os.ReadFile(recipePath)
+			if err != nil {
+				return fmt.Errorf("no se pudo leer el archivo de receta '%s': %w", recipePath, err)
+			}
+			document, projRecipe, err := loadRecipe(recipePath, data)
+			if err != nil {
+				return err
+			}
+
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("error getting user home directory: %w", err)
+			}
+			cacheDir := filepath.Join(homeDir, ".grei")
+
+			bundle, err := LoadTemplates(cmd, cacheDir, false, projRecipe.Templates)
+			if err != nil {
+				return err
+			}
+			if err := validateRecipe(bundle.FS, recipePath, document); err != nil {
+				return err
+			}
+
+			color.Green("¡%s es válido!", recipePath)
+			return nil
+		},
+	}
+	addTemplatesFlag(validate)
+	validate.Flags().String("templates-ref", "", "Rama, etiqueta o commit del repositorio de plantillas a usar, p. ej. v1.2.0")
+
+	schemaCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Muestra el JSON Schema de 'grei.yml' para las pilas de las plantillas.",
+		Long: `Imprime el JSON Schema de 'grei.yml' (apiVersion ` + recipe.APIVersion + `) con las opciones de cada
+pila de las plantillas. Sirve para validar recetas y autocompletarlas en el editor.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("error getting user home directory: %w", err)
+			}
+			cacheDir := filepath.Join(homeDir, ".grei")
+
+			// Load the templates quietly, so the output can be redirected
+			// to a file.
+			spec, err := templatesSpec(cmd)
+			if err != nil {
+				return err
+			}
+			source, err := NewTemplateSource(spec, "", cacheDir, false)
+			if err != nil {
+				return err
+			}
+			bundle, err := source.Load(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("no se pudieron cargar las plantillas: %w", err)
+			}
+			recipeSchema, err := RecipeSchema(bundle.FS)
+			if err != nil {
+				return err
+			}
+			data, err := json.MarshalIndent(recipeSchema, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(data))
+			return nil
+		},
+	}
+	addTemplatesFlag(schemaCmd)
+
+	migrate := &cobra.Command{
+		Use:   "migrate [path]",
+		Short: "Actualiza 'grei.yml' al formato actual de recetas.",
+		Long: `Convierte una receta escrita con una versión anterior de grei al formato actual
+(apiVersion ` + recipe.APIVersion + `) y la guarda. Usa --dry-run para ver el resultado
+sin escribirlo. path puede ser el directorio del proyecto o el archivo de receta.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			recipePath := recipeFileArg(args)
+			data, err := os.ReadFile(recipePath)
+			if err != nil {
+				return fmt.Errorf("no se pudo leer el archivo de receta '%s': %w", recipePath, err)
+			}
+
+			var document yaml.Node
+			if err := yaml.Unmarshal(data, &document); err != nil {
+				return fmt.Errorf("no se pudo parsear el archivo de receta '%s': %w", recipePath, err)
+			}
+			if len(document.Content) == 0 {
+				return fmt.Errorf("el archivo de receta '%s' está vacío", recipePath)
+			}
+			applied, err := recipe.Migrate(&document)
+			if err != nil {
+				return fmt.Errorf("no se pudo migrar '%s': %w", recipePath, err)
+			}
+			if len(applied) == 0 {
+				color.Green("%s ya usa el formato actual (%s).", recipePath, recipe.APIVersion)
+				return nil
+			}
+
+			migrated, err := recipe.Encode(&document, recipe.Indentation(data))
+			if err != nil {
+				return fmt.Errorf("error al generar el archivo YAML: %w", err)
+			}
+			for _, migration := range applied {
+				fmt.Printf("[i] %s -> %s: %s\n", versionName(migration.From), migration.To, migration.Description)
+			}
+
+			if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+				fmt.Print(string(migrated))
+				color.Cyan("Simulación: no se escribió nada.")
+				return nil
+			}
+			if err := os.WriteFile(recipePath, migrated, 0644); err != nil {
+				return fmt.Errorf("error al escribir el archivo %s: %w", recipePath, err)
+			}
+			color.Green("¡%s actualizado a %s!", recipePath, recipe.APIVersion)
+			return nil
+		},
+	}
+	migrate.Flags().Bool("dry-run", false, "Muestra la receta migrada sin escribirla.")
+
+	cmd.AddCommand(newRecipeShowCommand(), newRecipeSetCommand(), newRecipeExplainCommand(scaffolderService), validate, schemaCmd, migrate)
+	return cmd
+}
+
+// recipeFileArg returns the recipe named by the optional path argument: a
+// recipe file, or a project directory holding grei.yml.
+func recipeFileArg(args []string) string {
+	if len(args) == 0 {
+		return "grei.yml"
+	}
+	if info, err := os.Stat(args[0]); err == nil && info.IsDir() {
+		return filepath.Join(args[0], "grei.yml")
+	}
+	return args[0]
+}
+
+// loadRecipe decodes data, the contents of the recipe at name. A recipe in
+// an older format is upgraded in memory, with a warning; the document
+// returned is the upgraded one, for validation.
+func loadRecipe(name string, data []byte) (*yaml.Node, *recipe.Recipe, error) {
+	projRecipe, document, applied, err := recipe.Parse(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("no se pudo parsear el archivo de receta '%s': %w", name, err)
+	}
+	if len(applied) > 0 {
+		color.Yellow("%s usa un formato anterior (%s); ejecuta 'grei recipe migrate' para actualizarlo a %s.", name, versionName(applied[0].From), recipe.APIVersion)
+	}
+	return document, projRecipe, nil
+}
+
+func versionName(apiVersion string) string {
+	if apiVersion == "" {
+		return "sin apiVersion"
+	}
+	return apiVersion
+}
+
+// RecipeSchema returns the schema of grei.yml for the stacks in the
+// templates.
+func RecipeSchema(templatesFS fs.FS) (*schema.Schema, error) {
+	manifests, err := LoadManifests(templatesFS)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudieron leer los manifiestos de las plantillas: %w", err)
+	}
+	var stacks []schema.Stack
+	for _, manifest := range manifests {
+		stacks = append(stacks, schema.Stack{Name: manifest.Name, Options: manifest.Options})
+	}
+	return schema.ForRecipe(stacks), nil
+}
+
+// validateRecipe checks document, the recipe at name, against the recipe
+// schema and reports every problem with its location.
+func validateRecipe(templatesFS fs.FS, name string, document *yaml.Node) error {
+	recipeSchema, err := RecipeSchema(templatesFS)
+	if err != nil {
+		return err
+	}
+
+	errs := schema.ValidateNode(recipeSchema, document)
+	if len(errs) == 0 {
+		return nil
+	}
+	lines := make([]string, len(errs))
+	for i, e := range errs {
+		lines[i] = fmt.Sprintf("  %s:%s", name, e.Error())
+	}
+	return fmt.Errorf("%s no es válido:\n%s", name, strings.Join(lines, "\n"))
+}
+
+// LoadManifests returns the manifests of every stack in the templates,
+// leaving out mixins.
+func LoadManifests(templatesFS fs.FS) ([]*scaffolder.Manifest, error) {
+	var manifests []*scaffolder.Manifest
+	err := fs.WalkDir(templatesFS, "skeletons", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || d.Name() != "manifest.yml" {
+			return nil
+		}
+		manifest, err := GetManifest(templatesFS, path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if manifest.Type != scaffolder.MixinType {
+			manifests = append(manifests, manifest)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return manifests, nil
+}
@@ -0,0 +1,307 @@
+package cli
+
+import (
+	"fmt"
+	"grei-cli/internal/core/recipe"
+	"grei-cli/internal/core/schema"
+	"grei-cli/internal/core/verifier"
+	"grei-cli/internal/ports/inbound"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// addRecipeFileFlag adds the --file flag that selects the recipe edited by
+// a recipe subcommand.
+func addRecipeFileFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("file", "f", "grei.yml", "Archivo de receta, o directorio del proyecto que lo contiene.")
+}
+
+func recipeFileFlag(cmd *cobra.Command) string {
+	file, _ := cmd.Flags().GetString("file")
+	return recipeFileArg([]string{file})
+}
+
+func newRecipeShowCommand() *cobra.Command {
+	show := &cobra.Command{
+		Use:   "show [key]",
+		Short: "Muestra la receta, o uno de sus valores, p. ej. 'stack.ci'.",
+		Long: `Muestra 'grei.yml' en el formato actual, o solo el valor de key. key es una
+ruta con puntos, como 'stack.persistence' o 'verify.required[0].path'.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			recipePath := recipeFileFlag(cmd)
+			data, err := os.ReadFile(recipePath)
+			if err != nil {
+				return fmt.Errorf("no se pudo leer el archivo de receta '%s': %w", recipePath, err)
+			}
+			document, _, err := loadRecipe(recipePath, data)
+			if err != nil {
+				return err
+			}
+
+			node := document
+			if len(args) > 0 {
+				node, err = recipe.Lookup(document, args[0])
+				if err != nil {
+					return err
+				}
+				if node == nil {
+					return fmt.Errorf("'%s' no está definido en %s", args[0], recipePath)
+				}
+			}
+			return showNode(cmd.OutOrStdout(), node, recipe.Indentation(data))
+		},
+	}
+	addRecipeFileFlag(show)
+	return show
+}
+
+// showNode prints a scalar as plain text and anything else as YAML.
+func showNode(out io.Writer, node *yaml.Node, indent int) error {
+	if node.Kind == yaml.ScalarNode {
+		fmt.Fprintln(out, node.Value)
+		return nil
+	}
+	data, err := recipe.Encode(node, indent)
+	if err != nil {
+		return fmt.Errorf("error al generar el archivo YAML: %w", err)
+	}
+	fmt.Fprint(out, string(data))
+	return nil
+}
+
+func newRecipeSetCommand() *cobra.Command {
+	set := &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Cambia un valor de la receta, p. ej. 'stack.ci \"GitHub Actions\"'.",
+		Long: `Cambia el valor de key en 'grei.yml' y guarda el archivo conservando sus
+comentarios y el orden de sus claves. value se interpreta como YAML, de modo que
+'[auth, metrics]' es una lista y 'true' un booleano; si la receta solo admite
+texto en ese lugar, se guarda como texto. La receta se valida contra el esquema
+antes de escribirla y no se modifica si no es válida.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, raw := args[0], args[1]
+			recipePath := recipeFileFlag(cmd)
+			data, err := os.ReadFile(recipePath)
+			if err != nil {
+				return fmt.Errorf("no se pudo leer el archivo de receta '%s': %w", recipePath, err)
+			}
+			projRecipe, _, applied, err := recipe.Parse(data)
+			if err != nil {
+				return fmt.Errorf("no se pudo parsear el archivo de receta '%s': %w", recipePath, err)
+			}
+			if len(applied) > 0 {
+				return fmt.Errorf("%s usa un formato anterior (%s); ejecuta 'grei recipe migrate' antes de editarlo", recipePath, versionName(applied[0].From))
+			}
+
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("error getting user home directory: %w", err)
+			}
+			cacheDir := filepath.Join(homeDir, ".grei")
+
+			bundle, err := LoadTemplates(cmd, cacheDir, false, projRecipe.Templates)
+			if err != nil {
+				return err
+			}
+			recipeSchema, err := RecipeSchema(bundle.FS)
+			if err != nil {
+				return err
+			}
+
+			document, previous, err := setRecipeValue(recipeSchema, data, key, raw)
+			if err != nil {
+				return fmt.Errorf("no se pudo cambiar '%s' en %s: %w", key, recipePath, err)
+			}
+			updated, err := recipe.Encode(document, recipe.Indentation(data))
+			if err != nil {
+				return fmt.Errorf("error al generar el archivo YAML: %w", err)
+			}
+			if err := os.WriteFile(recipePath, updated, 0644); err != nil {
+				return fmt.Errorf("error al escribir el archivo %s: %w", recipePath, err)
+			}
+
+			if previous == "" {
+				color.Green("¡%s actualizado: %s = %s!", recipePath, key, raw)
+			} else {
+				color.Green("¡%s actualizado: %s = %s (antes: %s)!", recipePath, key, raw, previous)
+			}
+			if strings.HasPrefix(key, "stack.") || key == "project.type" {
+				color.Cyan("Ejecuta 'grei recipe explain %s' para ver qué activa y 'grei scaffold' para generar sus archivos.", key)
+			}
+			return nil
+		},
+	}
+	addRecipeFileFlag(set)
+	addTemplatesFlag(set)
+	set.Flags().String("templates-ref", "", "Rama, etiqueta o commit del repositorio de plantillas a usar, p. ej. v1.2.0")
+	return set
+}
+
+// setRecipeValue sets key to raw, read as YAML, in the recipe data and
+// validates the result against recipeSchema. A value the schema rejects
+// that YAML reads as a number, boolean or null is tried again as text, so
+// that 'set stack.dbVersion 16' stores "16". It returns the updated document
+// and the previous value of key, when it was a scalar.
+func setRecipeValue(recipeSchema *schema.Schema, data []byte, key, raw string) (*yaml.Node, string, error) {
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: raw}
+	var fragment yaml.Node
+	if err := yaml.Unmarshal([]byte(raw), &fragment); err == nil && len(fragment.Content) > 0 {
+		value = fragment.Content[0]
+	}
+
+	document, previous, errs, err := trySet(recipeSchema, data, key, value)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(errs) > 0 && value.Kind == yaml.ScalarNode && value.Tag != "!!str" {
+		text := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: raw}
+		if textDocument, _, textErrs, err := trySet(recipeSchema, data, key, text); err == nil && len(textErrs) == 0 {
+			document, errs = textDocument, nil
+		}
+	}
+	if len(errs) > 0 {
+		lines := make([]string, len(errs))
+		for i, e := range errs {
+			lines[i] = fmt.Sprintf("  %s: %s", e.Path, e.Message)
+		}
+		return nil, "", fmt.Errorf("la receta no sería válida:\n%s", strings.Join(lines, "\n"))
+	}
+	return document, previous, nil
+}
+
+func trySet(recipeSchema *schema.Schema, data []byte, key string, value *yaml.Node) (*yaml.Node, string, schema.Errors, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, "", nil, err
+	}
+	previous := ""
+	if old, err := recipe.Lookup(&document, key); err != nil {
+		return nil, "", nil, err
+	} else if old != nil && old.Kind == yaml.ScalarNode {
+		previous = old.Value
+	}
+	if err := recipe.SetPath(&document, key, value); err != nil {
+		return nil, "", nil, err
+	}
+	return &document, previous, schema.ValidateNode(recipeSchema, &document), nil
+}
+
+func newRecipeExplainCommand(scaffolderService inbound.ScaffolderService) *cobra.Command {
+	explain := &cobra.Command{
+		Use:   "explain [key]",
+		Short: "Explica qué plantillas y verificaciones activa cada valor de la receta.",
+		Long: `Muestra, para cada valor de la receta (o solo para key, p. ej. 'stack.ci'), los
+archivos y mixins de las plantillas que se incluyen según su valor, las preguntas
+que dependen de él, los archivos generados que lo usan y las verificaciones de
+'grei verify' que activa. [✓] marca lo que está activo con la receta actual.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			recipePath := recipeFileFlag(cmd)
+			data, err := os.ReadFile(recipePath)
+			if err != nil {
+				return fmt.Errorf("no se pudo leer el archivo de receta '%s': %w", recipePath, err)
+			}
+			document, projRecipe, err := loadRecipe(recipePath, data)
+			if err != nil {
+				return err
+			}
+
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("error getting user home directory: %w", err)
+			}
+			cacheDir := filepath.Join(homeDir, ".grei")
+
+			bundle, err := LoadTemplates(cmd, cacheDir, false, projRecipe.Templates)
+			if err != nil {
+				return err
+			}
+			activations, err := scaffolderService.Explain(bundle.FS, projRecipe)
+			if err != nil {
+				return fmt.Errorf("no se pudieron analizar las plantillas: %w", err)
+			}
+			activations = append(activations, verifier.StackChecks(projRecipe.Stack)...)
+
+			var only string
+			if len(args) > 0 {
+				only = args[0]
+			}
+			printActivations(cmd.OutOrStdout(), document, projRecipe, activations, only)
+			return nil
+		},
+	}
+	addRecipeFileFlag(explain)
+	addTemplatesFlag(explain)
+	explain.Flags().String("templates-ref", "", "Rama, etiqueta o commit del repositorio de plantillas a usar, p. ej. v1.2.0")
+	return explain
+}
+
+var activationKinds = map[string]string{
+	inbound.ActivationFiles:    "archivos",
+	inbound.ActivationMixin:    "mixin",
+	inbound.ActivationOption:   "pregunta",
+	inbound.ActivationTemplate: "plantilla",
+	inbound.ActivationCheck:    "verificación",
+}
+
+// printActivations groups activations by recipe value: first the stack
+// values in the order of the recipe, then any other value they depend on.
+func printActivations(out io.Writer, document *yaml.Node, projRecipe *recipe.Recipe, activations []inbound.Activation, only string) {
+	var order []string
+	byVar := make(map[string][]inbound.Activation)
+	addVar := func(name string) {
+		if _, ok := byVar[name]; !ok {
+			byVar[name] = nil
+			order = append(order, name)
+		}
+	}
+	if stack, _ := recipe.Lookup(document, "stack"); stack != nil && stack.Kind == yaml.MappingNode {
+		for i := 0; i < len(stack.Content); i += 2 {
+			addVar("stack." + stack.Content[i].Value)
+		}
+	}
+	for _, a := range activations {
+		addVar(a.Var)
+		byVar[a.Var] = append(byVar[a.Var], a)
+	}
+
+	vars := projRecipe.Vars()
+	green := color.New(color.FgGreen).SprintFunc()
+	for _, name := range order {
+		if only != "" && name != only {
+			continue
+		}
+		value, ok := vars[name]
+		if !ok {
+			value = "(sin valor)"
+		}
+		fmt.Fprintf(out, "%s = %s\n", name, value)
+		if len(byVar[name]) == 0 {
+			fmt.Fprintln(out, "  No afecta a las plantillas ni a la verificación.")
+		}
+		for _, a := range byVar[name] {
+			mark := "[ ]"
+			if a.Active {
+				mark = green("[✓]")
+			}
+			line := fmt.Sprintf("  %s %s %s", mark, activationKinds[a.Kind], a.Target)
+			if a.When != "" {
+				line += fmt.Sprintf(" (si %s)", a.When)
+			}
+			fmt.Fprintln(out, line)
+		}
+	}
+	if only != "" {
+		if _, ok := byVar[only]; !ok {
+			fmt.Fprintf(out, "%s no afecta a las plantillas ni a la verificación.\n", only)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"grei-cli/internal/core/recipe"
+	"grei-cli/templates"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestEmbeddedManifestOptions(t *testing.T) {
+	manifests, err := LoadManifests(templates.FS)
+	if err != nil {
+		t.Fatalf("LoadManifests returned an unexpected error: %v", err)
+	}
+	if len(manifests) == 0 {
+		t.Fatal("No stacks found in the embedded templates")
+	}
+	for _, manifest := range manifests {
+		for _, option := range manifest.Options {
+			if err := option.Check(); err != nil {
+				t.Errorf("%s: %v", manifest.Name, err)
+			}
+		}
+	}
+}
+
+func parseDocument(t *testing.T, text string) *yaml.Node {
+	t.Helper()
+	document, _, err := loadRecipe("grei.yml", []byte(text))
+	if err != nil {
+		t.Fatalf("loadRecipe returned an unexpected error: %v", err)
+	}
+	return document
+}
+
+func TestValidateRecipe(t *testing.T) {
+	valid := `apiVersion: grei/v1
+project:
+  name: web
+  customer: Acme
+  type: typescript-express-fullstack
+stack:
+  linter: ESLint
+  persistence: PostgreSQL
+  dbVersion: "16"
+  ci: GitHub Actions
+templates:
+  source: embedded
+  version: 1.0.0
+`
+	if err := validateRecipe(templates.FS, "grei.yml", parseDocument(t, valid)); err != nil {
+		t.Errorf("validateRecipe returned an unexpected error: %v", err)
+	}
+
+	invalid := strings.Replace(valid, "linter: ESLint", "linter: eslint", 1)
+	err := validateRecipe(templates.FS, "grei.yml", parseDocument(t, invalid))
+	if err == nil || !strings.Contains(err.Error(), `grei.yml:7:11: stack.linter: must be one of "ESLint"`) {
+		t.Errorf("Expected the location of the invalid linter, got %v", err)
+	}
+}
+
+func TestValidateRecipe_Unversioned(t *testing.T) {
+	// The recipe format before apiVersion, as written by early versions.
+	legacy := `project:
+  name: greicodex-cli
+  customer: Greicodex
+  type: typescript-express-fullstack
+stack:
+    linter: ESLint
+    testing: jest
+    cicd: []
+persistence:
+    type: Ninguna
+deployment:
+    type: Docker NodeJS
+    provider: ""
+`
+	document, projRecipe, err := loadRecipe("grei.yml", []byte(legacy))
+	if err != nil {
+		t.Fatalf("loadRecipe returned an unexpected error: %v", err)
+	}
+	if projRecipe.Stack.Tests != "jest" || projRecipe.Stack.Persistence != "None" || projRecipe.Stack.Deployment != "Docker NodeJS" {
+		t.Errorf("The legacy recipe was not migrated: %+v", projRecipe.Stack)
+	}
+	if err := validateRecipe(templates.FS, "grei.yml", document); err != nil {
+		t.Errorf("validateRecipe returned an unexpected error for the migrated recipe: %v", err)
+	}
+}
+
+func TestSetRecipeValue(t *testing.T) {
+	recipeSchema, err := RecipeSchema(templates.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte(`apiVersion: grei/v1
+project:
+  name: web
+  customer: Acme
+  type: typescript-express-fullstack
+stack:
+  linter: ESLint # chosen by the team
+  persistence: PostgreSQL
+  dbVersion: "16"
+`)
+
+	document, previous, err := setRecipeValue(recipeSchema, data, "stack.linter", "ESLint")
+	if err != nil || previous != "ESLint" {
+		t.Fatalf("setRecipeValue returned %q, %v", previous, err)
+	}
+	if node, _ := recipe.Lookup(document, "stack.linter"); node.LineComment != "# chosen by the team" {
+		t.Errorf("The comment of the replaced value was lost: %+v", node)
+	}
+
+	// A number where the recipe wants text is stored as text.
+	document, _, err = setRecipeValue(recipeSchema, data, "stack.dbVersion", "17")
+	if err != nil {
+		t.Fatalf("setRecipeValue returned an unexpected error: %v", err)
+	}
+	if node, _ := recipe.Lookup(document, "stack.dbVersion"); node.Tag != "!!str" || node.Value != "17" {
+		t.Errorf("stack.dbVersion = %s %q, want the text \"17\"", node.Tag, node.Value)
+	}
+
+	_, _, err = setRecipeValue(recipeSchema, data, "stack.ci", "Jenkins")
+	if err == nil || !strings.Contains(err.Error(), `stack.ci: must be one of`) {
+		t.Errorf("Expected the invalid value to be rejected, got %v", err)
+	}
+}
+
+func TestRecipeMigrate_KeepsLayout(t *testing.T) {
+	legacy := `# Proyecto principal
+project:
+  name: web
+  customer: Acme
+  type: typescript-express-fullstack
+stack:
+  linter: ESLint
+  testing: jest
+`
+	recipePath := filepath.Join(t.TempDir(), "grei.yml")
+	if err := os.WriteFile(recipePath, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := NewRecipeCommand(nil)
+	cmd.SetArgs([]string{"migrate", recipePath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("recipe migrate returned an unexpected error: %v", err)
+	}
+
+	migrated, err := os.ReadFile(recipePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# Proyecto principal\n", "\n  name: web\n", "\n  tests: jest\n"} {
+		if !strings.Contains(string(migrated), want) {
+			t.Errorf("Expected the migrated recipe to contain %q, got:\n%s", want, migrated)
+		}
+	}
+}
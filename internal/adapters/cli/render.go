@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"grei-cli/internal/adapters/filesystem"
+	"grei-cli/internal/core/initializer"
+	"grei-cli/internal/core/recipe"
+	"grei-cli/internal/core/scaffolder"
+	"grei-cli/internal/ports/outbound"
+)
+
+// renderProject renders in memory the files that init would create from
+// bundle for projRecipe. Paths in the repository are relative to the project.
+func renderProject(bundle *outbound.TemplateBundle, projRecipe *recipe.Recipe) (*filesystem.MemoryRepository, error) {
+	memRepo := filesystem.NewMemoryRepository()
+	if err := initializer.NewService(memRepo, nil).InitializeProject(".", bundle.FS, false, projRecipe); err != nil {
+		return nil, err
+	}
+	if err := scaffolder.NewService(memRepo).Scaffold(".", bundle.FS, projRecipe); err != nil {
+		return nil, err
+	}
+	return memRepo, nil
+}
+
+// renderSkeleton renders in memory the files that scaffold would create from
+// bundle for projRecipe.
+func renderSkeleton(bundle *outbound.TemplateBundle, projRecipe *recipe.Recipe) (*filesystem.MemoryRepository, error) {
+	memRepo := filesystem.NewMemoryRepository()
+	if err := scaffolder.NewService(memRepo).Scaffold(".", bundle.FS, projRecipe); err != nil {
+		return nil, err
+	}
+	return memRepo, nil
+}
+
+// renderComponent renders in memory the files that scaffold component would
+// create from bundle for the component recipe projRecipe.
+func renderComponent(bundle *outbound.TemplateBundle, projRecipe *recipe.Recipe) (*filesystem.MemoryRepository, error) {
+	memRepo := filesystem.NewMemoryRepository()
+	if err := scaffolder.NewService(memRepo).ScaffoldComponent(".", bundle.FS, projRecipe); err != nil {
+		return nil, err
+	}
+	return memRepo, nil
+}
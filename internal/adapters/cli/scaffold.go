@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"fmt"
+	"grei-cli/internal/adapters/filesystem"
+	"grei-cli/internal/adapters/git"
+	"grei-cli/internal/core/initializer"
+	"grei-cli/internal/core/preview"
+	"grei-cli/internal/core/scaffolder"
+	"grei-cli/internal/ports/inbound"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// AddScaffoldCommand adds the scaffold command to the root command.
+func AddScaffoldCommand(root *cobra.Command) {
+	fsRepo := filesystem.NewRepository()
+	conflictRepo := filesystem.NewConflictRepository(fsRepo, filesystem.ConflictPrompt, promptConflict)
+	initializerService := initializer.NewService(conflictRepo, git.NewRepository())
+	scaffolderService := scaffolder.NewService(conflictRepo)
+	previewService := preview.NewService(fsRepo)
+
+	cmd := NewScaffoldCommand(scaffolderService, previewService, conflictRepo)
+	cmd.AddCommand(NewScaffoldComponentCommand(initializerService, scaffolderService, previewService, conflictRepo))
+	cmd.Flags().Bool("dry-run", false, "Muestra los archivos que se crearían o modificarían, con sus diferencias, sin escribir nada.")
+	addConflictFlag(cmd)
+	addTemplatesFlag(cmd)
+	cmd.Flags().String("templates-ref", "", "Rama, etiqueta o commit del repositorio de plantillas a usar, p. ej. v1.2.0")
+	root.AddCommand(cmd)
+}
+
+// NewScaffoldCommand creates a new scaffold command with its dependencies.
+func NewScaffoldCommand(scaffolderService inbound.ScaffolderService, previewService inbound.PreviewService, conflictRepo *filesystem.ConflictRepository) *cobra.Command {
+	return &cobra.Command{
+		Use:   "scaffold [path]",
+		Short: "Genera las plantillas de la pila del proyecto a partir de su 'grei.yml'.",
+		Long: `Vuelve a generar los archivos de la pila de código declarada en 'grei.yml'
+sobre un proyecto existente. Los archivos existentes que cambiarían se tratan
+según --on-conflict; usa --dry-run para revisar antes qué archivos se crearían
+o modificarían.
+
+En un monorepo, 'grei scaffold component <name>' agrega un nuevo componente
+en 'apps/<name>'.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			targetPath := "."
+			if len(args) > 0 {
+				targetPath = args[0]
+			}
+			if err := applyConflictPolicy(cmd, conflictRepo); err != nil {
+				return err
+			}
+
+			recipePath := filepath.Join(targetPath, "grei.yml")
+			recipeData, err := os.ReadFile(recipePath)
+			if err != nil {
+				return fmt.Errorf("no se pudo leer el archivo 'grei.yml' en '%s'. Asegúrate de que el proyecto ha sido inicializado", targetPath)
+			}
+
+			document, projRecipe, err := loadRecipe(recipePath, recipeData)
+			if err != nil {
+				return err
+			}
+
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("error getting user home directory: %w", err)
+			}
+			cacheDir := filepath.Join(homeDir, ".grei")
+
+			bundle, err := LoadTemplates(cmd, cacheDir, true, projRecipe.Templates)
+			if err != nil {
+				return err
+			}
+			if err := validateRecipe(bundle.FS, recipePath, document); err != nil {
+				return err
+			}
+
+			if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+				rendered, err := renderSkeleton(bundle, projRecipe)
+				if err != nil {
+					return fmt.Errorf("error durante el scaffolding: %w", err)
+				}
+				if _, err := previewService.Preview(inbound.PreviewOptions{
+					Path:  targetPath,
+					Files: rendered.Files(),
+					Dirs:  rendered.Dirs(),
+				}); err != nil {
+					return fmt.Errorf("error al comparar con los archivos existentes: %w", err)
+				}
+				color.Cyan("Simulación: no se escribió nada.")
+				return nil
+			}
+
+			if err := scaffolderService.Scaffold(targetPath, bundle.FS, projRecipe); err != nil {
+				return fmt.Errorf("error durante el scaffolding: %w", err)
+			}
+			printConflictSummary(conflictRepo.Summary())
+
+			color.Green("¡Plantillas de la pila generadas!")
+			return nil
+		},
+	}
+}
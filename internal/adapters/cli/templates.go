@@ -0,0 +1,236 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"grei-cli/internal/adapters/downloader"
+	"grei-cli/internal/adapters/templatesource"
+	"grei-cli/internal/core/initializer"
+	"grei-cli/internal/core/recipe"
+	"grei-cli/internal/ports/outbound"
+	"grei-cli/templates"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultTemplatesURL = "https://github.com/GreicodexJM/greicodex-cli.git"
+	defaultTemplatesRef = "master"
+	// templatesEnvVar overrides the templates location of the user config.
+	templatesEnvVar = "GREI_TEMPLATES"
+)
+
+// Config is the user configuration stored in ~/.config/grei/config.yml.
+type Config struct {
+	// Templates is the default templates location, with the same syntax as
+	// the --templates flag.
+	Templates string `yaml:"templates"`
+}
+
+// LoadConfig reads the user configuration. A missing file yields an empty
+// configuration.
+func LoadConfig() (*Config, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return &Config{}, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(configDir, "grei", "config.yml"))
+	if os.IsNotExist(err) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("no se pudo parsear la configuración de usuario: %w", err)
+	}
+	return &config, nil
+}
+
+// addTemplatesFlag registers the --templates flag on cmd.
+func addTemplatesFlag(cmd *cobra.Command) {
+	cmd.Flags().String("templates", "", "Origen de las plantillas: 'embedded', un directorio, un archivo .tar.gz/.zip o un repositorio git (url[#ref]).")
+}
+
+// templatesSpec returns the templates location chosen by the --templates
+// flag, the GREI_TEMPLATES environment variable or the user config, in that
+// order. An empty string selects the default templates.
+func templatesSpec(cmd *cobra.Command) (string, error) {
+	if spec, _ := cmd.Flags().GetString("templates"); spec != "" {
+		return spec, nil
+	}
+	if spec := os.Getenv(templatesEnvVar); spec != "" {
+		return spec, nil
+	}
+	config, err := LoadConfig()
+	if err != nil {
+		return "", err
+	}
+	return config.Templates, nil
+}
+
+// NewTemplateSource creates the TemplateSource for a templates location:
+//
+//   - "" uses the Greicodex templates repository, falling back to the
+//     embedded bundle when it is unavailable or outdated.
+//   - "embedded" uses the bundle compiled into the binary.
+//   - A .tar.gz, .tgz or .zip file uses that archive.
+//   - An existing directory is read as is.
+//   - Anything else is a git repository URL, optionally followed by #ref.
+//
+// ref selects a branch, tag or commit of a git repository and overrides the
+// one in the URL. Pinned templates never fall back to the embedded bundle.
+// Git repositories are only fetched when update is true.
+func NewTemplateSource(spec, ref, cacheDir string, update bool) (outbound.TemplateSource, error) {
+	embedded := templatesource.NewEmbeddedSource(templates.FS)
+	switch {
+	case spec == "" && ref == "":
+		return templatesource.NewFallbackSource(
+			templatesource.NewGitSource(downloader.NewGitDownloader(), defaultTemplatesURL, defaultTemplatesRef, cacheDir, update),
+			embedded,
+		), nil
+	case spec == "":
+		spec = defaultTemplatesURL
+	case spec == "embedded" || templatesource.IsArchive(spec):
+		if ref != "" {
+			return nil, fmt.Errorf("--templates-ref solo aplica a repositorios git")
+		}
+		if spec == "embedded" {
+			return embedded, nil
+		}
+		return templatesource.NewArchiveSource(spec, cacheDir), nil
+	}
+
+	if info, err := os.Stat(spec); err == nil && info.IsDir() {
+		if ref != "" {
+			return nil, fmt.Errorf("--templates-ref solo aplica a repositorios git")
+		}
+		return templatesource.NewDirSource(spec), nil
+	}
+	if !isGitURL(spec) {
+		return nil, fmt.Errorf("origen de plantillas desconocido: '%s'", spec)
+	}
+
+	url, urlRef := spec, defaultTemplatesRef
+	if i := strings.LastIndex(spec, "#"); i >= 0 {
+		url, urlRef = spec[:i], spec[i+1:]
+	}
+	if ref == "" {
+		ref = urlRef
+	}
+	// Every repository and ref gets its own checkout, so pinned projects do
+	// not move the default cache away from the latest templates.
+	sum := sha256.Sum256([]byte(url + "#" + ref))
+	sourceCacheDir := filepath.Join(cacheDir, "sources", hex.EncodeToString(sum[:])[:16])
+	return templatesource.NewGitSource(downloader.NewGitDownloader(), url, ref, sourceCacheDir, update), nil
+}
+
+func isGitURL(spec string) bool {
+	for _, prefix := range []string{"https://", "http://", "ssh://", "git://", "file://", "git@"} {
+		if strings.HasPrefix(spec, prefix) {
+			return true
+		}
+	}
+	return strings.HasSuffix(strings.SplitN(spec, "#", 2)[0], ".git")
+}
+
+// LoadTemplates loads the templates selected for cmd and reports where they
+// came from. pinned, the templates recorded in a recipe, is used when neither
+// --templates, --templates-ref nor the environment select other templates.
+func LoadTemplates(cmd *cobra.Command, cacheDir string, update bool, pinned *recipe.Templates) (*outbound.TemplateBundle, error) {
+	ref, _ := cmd.Flags().GetString("templates-ref")
+	if pinned != nil && pinned.Source != "" && !cmd.Flags().Changed("templates") && os.Getenv(templatesEnvVar) == "" && ref == "" {
+		return LoadPinnedTemplates(cmd, cacheDir, pinned)
+	}
+
+	spec, err := templatesSpec(cmd)
+	if err != nil {
+		return nil, err
+	}
+	source, err := NewTemplateSource(spec, ref, cacheDir, update)
+	if err != nil {
+		return nil, err
+	}
+	return loadBundle(cmd, source)
+}
+
+// LoadPinnedTemplates loads the exact templates recorded in a recipe.
+func LoadPinnedTemplates(cmd *cobra.Command, cacheDir string, pinned *recipe.Templates) (*outbound.TemplateBundle, error) {
+	ref := ""
+	if isGitURL(pinned.Source) {
+		ref = pinned.Commit
+		if ref == "" {
+			ref = pinned.Ref
+		}
+	}
+
+	source, err := NewTemplateSource(pinned.Source, ref, cacheDir, true)
+	if err != nil {
+		return nil, err
+	}
+	bundle, err := loadBundle(cmd, source)
+	if err != nil {
+		return nil, err
+	}
+	if pinned.Ref != "" {
+		// The commit was checked out; keep recording the ref it came from.
+		bundle.Ref = pinned.Ref
+	}
+	return bundle, nil
+}
+
+// LoadLatestTemplates loads the newest templates from the source recorded in
+// a recipe, at the ref it tracks, so that a project created from a fork, a
+// directory or an archive is upgraded with its own templates. --templates
+// and --templates-ref select other templates like in LoadTemplates.
+func LoadLatestTemplates(cmd *cobra.Command, cacheDir string, pinned *recipe.Templates) (*outbound.TemplateBundle, error) {
+	if cmd.Flags().Changed("templates") || cmd.Flags().Changed("templates-ref") {
+		return LoadTemplates(cmd, cacheDir, true, nil)
+	}
+
+	spec, ref := pinned.Source, ""
+	if isGitURL(spec) {
+		ref = pinned.Ref
+	}
+	if spec == defaultTemplatesURL && (ref == "" || ref == defaultTemplatesRef) {
+		// The default templates keep falling back to the embedded bundle.
+		spec, ref = "", ""
+	}
+
+	source, err := NewTemplateSource(spec, ref, cacheDir, true)
+	if err != nil {
+		return nil, err
+	}
+	return loadBundle(cmd, source)
+}
+
+func loadBundle(cmd *cobra.Command, source outbound.TemplateSource) (*outbound.TemplateBundle, error) {
+	bundle, err := source.Load(cmd.Context())
+	if err != nil {
+		return nil, fmt.Errorf("no se pudieron cargar las plantillas: %w", err)
+	}
+	color.Cyan("Usando plantillas de %s", bundle.Source)
+	return bundle, nil
+}
+
+// PinTemplates returns the record of bundle to store in grei.yml.
+func PinTemplates(bundle *outbound.TemplateBundle) *recipe.Templates {
+	pinned := &recipe.Templates{
+		Source: bundle.Location,
+		Ref:    bundle.Ref,
+		Commit: bundle.Commit,
+	}
+	if manifest, err := initializer.ReadManifest(bundle.FS); err == nil {
+		pinned.Version = manifest.Version
+	}
+	return pinned
+}
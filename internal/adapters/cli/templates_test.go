@@ -0,0 +1,244 @@
+package cli
+
+import (
+	"context"
+	"grei-cli/internal/core/recipe"
+	"grei-cli/internal/ports/outbound"
+	"grei-cli/templates"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func writeCacheManifest(t *testing.T, cacheDir, manifest string) {
+	t.Helper()
+	dir := filepath.Join(cacheDir, "templates")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+}
+
+func TestDefaultTemplateSource(t *testing.T) {
+	tests := []struct {
+		name         string
+		manifest     string
+		wantEmbedded bool
+	}{
+		{"missing cache", "", true},
+		{"current cache", `{"version": "99.0.0", "minVersion": "0.1.0"}`, false},
+		{"stale cache", `{"version": "0.0.1", "minVersion": "0.1.0"}`, true},
+		{"cache requires newer CLI", `{"version": "99.0.0", "minVersion": "99.0.0"}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheDir := t.TempDir()
+			if tt.manifest != "" {
+				writeCacheManifest(t, cacheDir, tt.manifest)
+			}
+
+			source, err := NewTemplateSource("", "", cacheDir, false)
+			if err != nil {
+				t.Fatalf("NewTemplateSource() returned an unexpected error: %v", err)
+			}
+			bundle, err := source.Load(context.Background())
+			if err != nil {
+				t.Fatalf("Load() returned an unexpected error: %v", err)
+			}
+
+			isEmbedded := bundle.FS == fs.FS(templates.FS)
+			if isEmbedded != tt.wantEmbedded {
+				t.Errorf("Expected embedded=%v, got source %q", tt.wantEmbedded, bundle.Source)
+			}
+		})
+	}
+}
+
+func TestNewTemplateSource(t *testing.T) {
+	cacheDir := t.TempDir()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), []byte(`{"version": "1.0.0"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		spec       string
+		ref        string
+		wantSource string
+		wantErr    bool
+	}{
+		{"embedded", "", "embedded v", false},
+		{dir, "", "directory " + dir, false},
+		{"https://example.com/org/templates.git#v2", "", "git https://example.com/org/templates.git@v2", false},
+		{"https://example.com/org/templates.git#v2", "v3", "git https://example.com/org/templates.git@v3", false},
+		{"git@example.com:org/templates.git", "", "git git@example.com:org/templates.git@master", false},
+		{"", "v1.2.0", "git " + defaultTemplatesURL + "@v1.2.0", false},
+		{"embedded", "v1.2.0", "", true},
+		{dir, "v1.2.0", "", true},
+		{"./does-not-exist", "", "", true},
+	}
+
+	for _, tt := range tests {
+		source, err := NewTemplateSource(tt.spec, tt.ref, cacheDir, false)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("NewTemplateSource(%q) should have failed", tt.spec)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("NewTemplateSource(%q) returned an unexpected error: %v", tt.spec, err)
+			continue
+		}
+
+		// Git sources have no cached templates here, so check the source
+		// they report in their error.
+		bundle, err := source.Load(context.Background())
+		var got string
+		if err != nil {
+			got = err.Error()
+		} else {
+			got = bundle.Source
+		}
+		if !strings.Contains(got, tt.wantSource) {
+			t.Errorf("NewTemplateSource(%q): expected %q in %q", tt.spec, tt.wantSource, got)
+		}
+	}
+}
+
+func TestPinTemplates(t *testing.T) {
+	bundle := &outbound.TemplateBundle{
+		FS:       templates.FS,
+		Location: defaultTemplatesURL,
+		Ref:      "v1.2.0",
+		Commit:   "0123abcd",
+	}
+
+	pinned := PinTemplates(bundle)
+	if pinned.Source != defaultTemplatesURL || pinned.Ref != "v1.2.0" || pinned.Commit != "0123abcd" {
+		t.Errorf("Unexpected pinned templates: %+v", pinned)
+	}
+	if pinned.Version == "" {
+		t.Error("Expected the manifest version to be recorded")
+	}
+}
+
+func TestTemplatesSpec(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv(templatesEnvVar, "")
+
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		addTemplatesFlag(cmd)
+		return cmd
+	}
+
+	if spec, err := templatesSpec(newCmd()); err != nil || spec != "" {
+		t.Errorf("Expected the default templates, got %q (%v)", spec, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(configHome, "grei"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configHome, "grei", "config.yml"), []byte("templates: /from/config\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if spec, _ := templatesSpec(newCmd()); spec != "/from/config" {
+		t.Errorf("Expected the config file to select the templates, got %q", spec)
+	}
+
+	t.Setenv(templatesEnvVar, "/from/env")
+	if spec, _ := templatesSpec(newCmd()); spec != "/from/env" {
+		t.Errorf("Expected the environment to override the config file, got %q", spec)
+	}
+
+	cmd := newCmd()
+	cmd.Flags().Set("templates", "/from/flag")
+	if spec, _ := templatesSpec(cmd); spec != "/from/flag" {
+		t.Errorf("Expected the flag to override the environment, got %q", spec)
+	}
+}
+
+func TestEmbeddedTemplates(t *testing.T) {
+	stacks, _, _ := CategorizeStacks(templates.FS)
+	if len(stacks) == 0 {
+		t.Fatal("Expected the embedded bundle to contain stacks")
+	}
+	for _, stack := range stacks {
+		if stack == "helm" || stack == "postgres" {
+			t.Errorf("Mixin %q should not be offered as a stack", stack)
+		}
+	}
+
+	manifest, err := FindManifest(templates.FS, "golang-cli")
+	if err != nil || manifest == nil {
+		t.Fatalf("Expected the embedded bundle to contain the golang-cli stack, got %v", err)
+	}
+
+	packs, err := LoadPolicyPacks(templates.FS)
+	if err != nil || len(packs) == 0 {
+		t.Fatalf("Expected the embedded bundle to contain policy packs, got %v", err)
+	}
+}
+
+func TestEmbeddedPolicyPacks_PinDependencies(t *testing.T) {
+	stacks, _, _ := CategorizeStacks(templates.FS)
+	for _, stack := range stacks {
+		manifest, err := FindManifest(templates.FS, stack)
+		if err != nil {
+			t.Fatalf("FindManifest(%s) returned an unexpected error: %v", stack, err)
+		}
+		projRecipe := &recipe.Recipe{Project: recipe.Project{Name: "app", Type: stack}}
+		rules, pack, err := resolvePolicy(templates.FS, projRecipe, manifest)
+		if err != nil {
+			t.Fatalf("resolvePolicy(%s) returned an unexpected error: %v", stack, err)
+		}
+		if pack == nil || !rules.PinDependencies {
+			t.Errorf("Expected the shipped policy for %s to enforce pinned dependencies, got pack %v", stack, pack)
+		}
+	}
+}
+
+func TestLoadLatestTemplates(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(templatesEnvVar, "embedded")
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), []byte(`{"version": "1.0.0"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		addTemplatesFlag(cmd)
+		cmd.Flags().String("templates-ref", "", "")
+		return cmd
+	}
+	pinned := &recipe.Templates{Source: dir, Version: "1.0.0"}
+
+	// The recorded source wins over the default templates.
+	bundle, err := LoadLatestTemplates(newCmd(), t.TempDir(), pinned)
+	if err != nil {
+		t.Fatalf("LoadLatestTemplates returned an unexpected error: %v", err)
+	}
+	if bundle.Location != dir {
+		t.Errorf("Expected the templates recorded in the recipe, got %q", bundle.Location)
+	}
+
+	cmd := newCmd()
+	cmd.Flags().Set("templates", "embedded")
+	bundle, err = LoadLatestTemplates(cmd, t.TempDir(), pinned)
+	if err != nil {
+		t.Fatalf("LoadLatestTemplates returned an unexpected error: %v", err)
+	}
+	if bundle.Location != "embedded" {
+		t.Errorf("Expected --templates to select the templates, got %q", bundle.Location)
+	}
+}
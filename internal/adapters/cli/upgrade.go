@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"fmt"
+	"grei-cli/internal/adapters/filesystem"
+	"grei-cli/internal/core/recipe"
+	"grei-cli/internal/core/upgrader"
+	"grei-cli/internal/ports/inbound"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// AddUpgradeCommand adds the upgrade command to the root command.
+func AddUpgradeCommand(root *cobra.Command) {
+	fsRepo := filesystem.NewRepository()
+	upgraderService := upgrader.NewService(fsRepo)
+
+	cmd := NewUpgradeCommand(upgraderService)
+	cmd.Flags().Bool("dry-run", false, "Muestra los cambios como un diff sin escribirlos.")
+	cmd.Flags().Bool("reject", false, "Deja intactos los archivos en conflicto y escribe los cambios de las plantillas en archivos .rej.")
+	addTemplatesFlag(cmd)
+	cmd.Flags().String("templates-ref", "", "Rama, etiqueta o commit del repositorio de plantillas al que actualizar, p. ej. v1.3.0")
+	root.AddCommand(cmd)
+}
+
+// NewUpgradeCommand creates a new upgrade command with its dependencies.
+func NewUpgradeCommand(upgraderService inbound.UpgraderService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "upgrade [path]",
+		Short: "Actualiza un proyecto a la última versión de sus plantillas.",
+		Long: `Genera el proyecto con la versión de las plantillas registrada en 'grei.yml'
+y con la versión más reciente, usando la misma receta, y aplica las diferencias
+sobre los archivos actuales mediante un merge de tres vías. Los cambios que no
+se pueden aplicar limpiamente quedan marcados como conflictos.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			targetPath := "."
+			if len(args) > 0 {
+				targetPath = args[0]
+			}
+
+			recipePath := filepath.Join(targetPath, "grei.yml")
+			recipeData, err := os.ReadFile(recipePath)
+			if err != nil {
+				return fmt.Errorf("no se pudo leer el archivo 'grei.yml' en '%s'. Asegúrate de que el proyecto ha sido inicializado", targetPath)
+			}
+
+			document, projRecipe, err := loadRecipe(recipePath, recipeData)
+			if err != nil {
+				return err
+			}
+			if projRecipe.Templates == nil || projRecipe.Templates.Source == "" {
+				return fmt.Errorf("'grei.yml' no registra las plantillas con las que se creó el proyecto (sección 'templates'); no se puede calcular la base del merge")
+			}
+
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("error getting user home directory: %w", err)
+			}
+			cacheDir := filepath.Join(homeDir, ".grei")
+
+			baseBundle, err := LoadPinnedTemplates(cmd, cacheDir, projRecipe.Templates)
+			if err != nil {
+				return err
+			}
+			basePin := PinTemplates(baseBundle)
+			if projRecipe.Templates.Version != "" && basePin.Version != projRecipe.Templates.Version {
+				color.Yellow("Las plantillas base son la versión %s, pero el proyecto se creó con la %s; el merge puede mostrar conflictos de más.", basePin.Version, projRecipe.Templates.Version)
+			}
+
+			latestBundle, err := LoadLatestTemplates(cmd, cacheDir, projRecipe.Templates)
+			if err != nil {
+				return err
+			}
+			if latestBundle.Stale {
+				color.Yellow("No se pudieron actualizar las plantillas; se usa la copia en caché, que puede no traer cambios nuevos.")
+			}
+			latestPin := PinTemplates(latestBundle)
+
+			base, err := renderProject(baseBundle, projRecipe)
+			if err != nil {
+				return fmt.Errorf("error al generar el proyecto con las plantillas base: %w", err)
+			}
+			latest, err := renderProject(latestBundle, projRecipe)
+			if err != nil {
+				return fmt.Errorf("error al generar el proyecto con las plantillas nuevas: %w", err)
+			}
+
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			reject, _ := cmd.Flags().GetBool("reject")
+			report, err := upgraderService.Upgrade(inbound.UpgradeOptions{
+				Path:   targetPath,
+				Base:   base.Files(),
+				Latest: latest.Files(),
+				DryRun: dryRun,
+				Reject: reject,
+			})
+			if err != nil {
+				return fmt.Errorf("error durante la actualización: %w", err)
+			}
+
+			if dryRun {
+				color.Cyan("Simulación: %d archivos cambiarían. No se escribió nada.", len(report.Changes))
+				return nil
+			}
+
+			// Only the templates section changes, so the comments, order and
+			// indentation of grei.yml are kept.
+			var templatesNode yaml.Node
+			if err := templatesNode.Encode(latestPin); err != nil {
+				return fmt.Errorf("error al generar el archivo YAML: %w", err)
+			}
+			if err := recipe.SetPath(document, "templates", &templatesNode); err != nil {
+				return fmt.Errorf("error al actualizar la sección 'templates' de grei.yml: %w", err)
+			}
+			yamlData, err := recipe.Encode(document, recipe.Indentation(recipeData))
+			if err != nil {
+				return fmt.Errorf("error al generar el archivo YAML: %w", err)
+			}
+			if err := os.WriteFile(recipePath, yamlData, 0644); err != nil {
+				return fmt.Errorf("error al escribir el archivo grei.yml: %w", err)
+			}
+
+			if report.Conflicts > 0 {
+				return fmt.Errorf("quedaron %d conflictos por resolver", report.Conflicts)
+			}
+			color.Green("¡Proyecto actualizado a las plantillas v%s!", latestPin.Version)
+			return nil
+		},
+	}
+}
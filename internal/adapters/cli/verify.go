@@ -3,14 +3,23 @@ package cli
 import (
 	"fmt"
 	"grei-cli/internal/adapters/coverage"
+	"grei-cli/internal/adapters/dependencies"
+	"grei-cli/internal/adapters/imports"
 	"grei-cli/internal/adapters/linter"
 	"grei-cli/internal/adapters/scanner"
 	"grei-cli/internal/adapters/syschecker"
+	"grei-cli/internal/adapters/vulnerability"
+	"grei-cli/internal/core/detector"
+	"grei-cli/internal/core/policy"
 	"grei-cli/internal/core/recipe"
+	"grei-cli/internal/core/scaffolder"
 	"grei-cli/internal/core/verifier"
 	"grei-cli/internal/ports/inbound"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -23,22 +32,42 @@ func AddVerifyCommand(root *cobra.Command) {
 	sysChecker := syschecker.New()
 	secretScanner := scanner.NewGitleaksScanner(sysChecker)
 	linterDetector := linter.NewFsDetector()
-	verifyService := verifier.NewService(coverageParser, secretScanner, linterDetector)
+	dependencyReader := dependencies.NewFsReader()
+	importScanner := imports.NewFsScanner()
+	// The offline advisory database is synced with the templates, so the
+	// service is only built once they are loaded.
+	newVerifyService := func(advisories fs.FS) inbound.VerifierService {
+		vulnScanner := vulnerability.NewMultiScanner(
+			vulnerability.NewOSVScanner(advisories, dependencyReader),
+			vulnerability.NewGovulncheckScanner(sysChecker),
+			vulnerability.NewNpmAuditScanner(sysChecker),
+		)
+		return verifier.NewService(coverageParser, secretScanner, linterDetector, dependencyReader, vulnScanner, importScanner)
+	}
 
-	cmd := NewVerifyCommand(verifyService)
+	cmd := NewVerifyCommand(newVerifyService)
 	cmd.Flags().Int("min-cov", 80, "Cobertura de pruebas mínima requerida.")
 	cmd.Flags().Bool("json", false, "Muestra la salida en formato JSON.")
+	addTemplatesFlag(cmd)
 	root.AddCommand(cmd)
 }
 
-// NewVerifyCommand creates a new verify command with its dependencies.
-func NewVerifyCommand(verifyService inbound.VerifierService) *cobra.Command {
+// NewVerifyCommand creates a new verify command. newVerifyService builds the
+// verification service from the advisory database of the loaded templates.
+func NewVerifyCommand(newVerifyService func(advisories fs.FS) inbound.VerifierService) *cobra.Command {
 	return &cobra.Command{
 		Use:   "verify [path]",
 		Short: "Verifica que un proyecto existente cumpla con los estándares de Greicodex.",
 		Long: `Ejecuta una serie de comprobaciones en un repositorio existente, incluyendo
 escaneo de secretos, linters, pruebas, cobertura mínima y configuración de CI/CD,
-Helm y OpenTofu.`,
+Helm y OpenTofu.
+
+Si el proyecto no tiene 'grei.yml', se verifica con la pila detectada a partir
+de sus archivos (ver 'grei detect').
+
+En un monorepo, cuyo 'grei.yml' lista sus componentes en 'components', cada
+componente se verifica con su propio 'grei.yml': sus linters, su política y su
+cobertura mínima.`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			targetPath := "."
@@ -46,29 +75,41 @@ Helm y OpenTofu.`,
 				targetPath = args[0]
 			}
 
-			recipePath := filepath.Join(targetPath, "grei.yml")
-			recipeData, err := os.ReadFile(recipePath)
+			recipePath, document, projRecipe, err := readProjectRecipe(targetPath)
 			if err != nil {
-				return fmt.Errorf("no se pudo leer el archivo 'grei.yml' en '%s'. Asegúrate de que el proyecto ha sido inicializado", targetPath)
+				return err
 			}
 
-			var projRecipe recipe.Recipe
-			if err := yaml.Unmarshal(recipeData, &projRecipe); err != nil {
-				return fmt.Errorf("no se pudo parsear el archivo 'grei.yml': %w", err)
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("error getting user home directory: %w", err)
 			}
+			cacheDir := filepath.Join(homeDir, ".grei")
 
-			minCoverage, _ := cmd.Flags().GetInt("min-cov")
-			jsonOutput, _ := cmd.Flags().GetBool("json")
+			bundle, err := LoadTemplates(cmd, cacheDir, false, nil)
+			if err != nil {
+				return err
+			}
+			if document != nil {
+				if err := validateRecipe(bundle.FS, recipePath, document); err != nil {
+					return err
+				}
+			}
+			if err := projRecipe.CheckComponents(); err != nil {
+				return fmt.Errorf("%s no es válido: %w", recipePath, err)
+			}
 
-			options := inbound.VerifyOptions{
-				Path:        targetPath,
-				MinCoverage: minCoverage,
-				JSONOutput:  jsonOutput,
-				Recipe:      &projRecipe,
+			advisories, err := fs.Sub(bundle.FS, "advisories")
+			if err != nil {
+				return fmt.Errorf("no se pudo leer la base de datos de vulnerabilidades: %w", err)
 			}
+			verifyService := newVerifyService(advisories)
 
-			if err := verifyService.VerifyProject(options); err != nil {
-				return fmt.Errorf("error durante la verificación: %w", err)
+			if len(projRecipe.Components) > 0 {
+				return verifyComponents(cmd, verifyService, bundle.FS, targetPath, projRecipe)
+			}
+			if err := verifyRecipe(cmd, verifyService, bundle.FS, targetPath, projRecipe); err != nil {
+				return err
 			}
 
 			color.Green("¡Proyecto verificado exitosamente!")
@@ -76,3 +117,182 @@ Helm y OpenTofu.`,
 		},
 	}
 }
+
+// readProjectRecipe reads the grei.yml of the project at targetPath. A
+// project without one gets the recipe of its detected stack and a nil
+// document.
+func readProjectRecipe(targetPath string) (string, *yaml.Node, *recipe.Recipe, error) {
+	recipePath := filepath.Join(targetPath, "grei.yml")
+	recipeData, err := os.ReadFile(recipePath)
+	switch {
+	case err == nil:
+		document, projRecipe, err := loadRecipe(recipePath, recipeData)
+		return recipePath, document, projRecipe, err
+	case os.IsNotExist(err):
+		projRecipe, err := detectedRecipe(targetPath)
+		return recipePath, nil, projRecipe, err
+	default:
+		return recipePath, nil, nil, fmt.Errorf("no se pudo leer el archivo 'grei.yml' en '%s': %w", targetPath, err)
+	}
+}
+
+// verifyRecipe verifies the project at targetPath against projRecipe, with
+// the policy and the coverage threshold of its stack.
+func verifyRecipe(cmd *cobra.Command, verifyService inbound.VerifierService, templatesFS fs.FS, targetPath string, projRecipe *recipe.Recipe) error {
+	manifest, err := FindManifest(templatesFS, projRecipe.Project.Type)
+	if err != nil {
+		return fmt.Errorf("no se pudo leer el manifiesto de la pila '%s': %w", projRecipe.Project.Type, err)
+	}
+
+	rules, pack, err := resolvePolicy(templatesFS, projRecipe, manifest)
+	if err != nil {
+		return fmt.Errorf("no se pudo resolver la política de verificación: %w", err)
+	}
+
+	minCoverage, _ := cmd.Flags().GetInt("min-cov")
+	if rules.Coverage > 0 {
+		if cmd.Flags().Changed("min-cov") && minCoverage < rules.Coverage {
+			return fmt.Errorf("--min-cov=%d no puede ser menor que la cobertura mínima de la política (%d%%)", minCoverage, rules.Coverage)
+		}
+		if !cmd.Flags().Changed("min-cov") {
+			minCoverage = rules.Coverage
+		}
+	}
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	options := inbound.VerifyOptions{
+		Path:               targetPath,
+		MinCoverage:        minCoverage,
+		JSONOutput:         jsonOutput,
+		Recipe:             projRecipe,
+		Required:           rules.Required,
+		BannedDependencies: rules.BannedDependencies,
+		PinDependencies:    rules.PinDependencies,
+		MandatoryChecks:    rules.Checks,
+		Policy:             pack,
+	}
+	if manifest != nil {
+		options.Architecture = manifest.Architecture
+	}
+
+	if err := verifyService.VerifyProject(options); err != nil {
+		return fmt.Errorf("error durante la verificación: %w", err)
+	}
+	return nil
+}
+
+// verifyComponents verifies every component of a monorepo against its own
+// grei.yml, and the root too unless it is a recipe.MonorepoType project. A
+// failing component does not stop the others from being verified.
+func verifyComponents(cmd *cobra.Command, verifyService inbound.VerifierService, templatesFS fs.FS, targetPath string, projRecipe *recipe.Recipe) error {
+	var failed []string
+	if projRecipe.Project.Type != recipe.MonorepoType {
+		color.Cyan("\n▶ Raíz del repositorio (%s)", projRecipe.Project.Type)
+		if err := verifyRecipe(cmd, verifyService, templatesFS, targetPath, projRecipe); err != nil {
+			color.Red("✗ Raíz: %v", err)
+			failed = append(failed, ".")
+		}
+	}
+
+	for _, component := range projRecipe.Components {
+		componentPath := filepath.Join(targetPath, filepath.FromSlash(component.Path))
+		color.Cyan("\n▶ Componente '%s' (%s)", component.Name, component.Path)
+		if err := verifyComponent(cmd, verifyService, templatesFS, componentPath); err != nil {
+			color.Red("✗ %s: %v", component.Name, err)
+			failed = append(failed, component.Name)
+			continue
+		}
+		color.Green("✓ Componente '%s' verificado.", component.Name)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("la verificación falló en: %s", strings.Join(failed, ", "))
+	}
+	color.Green("\n¡Proyecto verificado exitosamente! (%d componentes)", len(projRecipe.Components))
+	return nil
+}
+
+// verifyComponent verifies one component of a monorepo against its own
+// grei.yml, or its detected stack when it has none.
+func verifyComponent(cmd *cobra.Command, verifyService inbound.VerifierService, templatesFS fs.FS, componentPath string) error {
+	if info, err := os.Stat(componentPath); err != nil || !info.IsDir() {
+		return fmt.Errorf("el directorio '%s' no existe", componentPath)
+	}
+	recipePath, document, componentRecipe, err := readProjectRecipe(componentPath)
+	if err != nil {
+		return err
+	}
+	if document != nil {
+		if err := validateRecipe(templatesFS, recipePath, document); err != nil {
+			return err
+		}
+	}
+	return verifyRecipe(cmd, verifyService, templatesFS, componentPath, componentRecipe)
+}
+
+// detectedRecipe returns the recipe of a project without grei.yml, inferred
+// from its files, so that it can be verified before it is adopted.
+func detectedRecipe(targetPath string) (*recipe.Recipe, error) {
+	detection, err := detector.Detect(os.DirFS(targetPath))
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo detectar la pila del proyecto: %w", err)
+	}
+	if detection.Stack == nil {
+		return nil, fmt.Errorf("no se encontró 'grei.yml' en '%s' ni se reconoció su pila. Asegúrate de que el proyecto ha sido inicializado o adóptalo con 'grei init --adopt'", targetPath)
+	}
+
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return nil, err
+	}
+	color.Yellow("No se encontró 'grei.yml'; se verifica con la pila detectada: %s (%.0f%%).", detection.Stack.Value, detection.Stack.Confidence*100)
+	return detection.Recipe(filepath.Base(absPath)), nil
+}
+
+// resolvePolicy combines the required paths declared by the stack manifest,
+// the organization policy pack that governs the stack and the overrides in
+// the project recipe. manifest is nil when the stack is unknown.
+func resolvePolicy(templatesFS fs.FS, projRecipe *recipe.Recipe, manifest *scaffolder.Manifest) (policy.Rules, *policy.Pack, error) {
+	base := policy.DefaultRequirements
+	stackType := ""
+	if manifest != nil {
+		stackType = manifest.Type
+		if len(manifest.Required) > 0 {
+			base = manifest.Required
+		}
+	}
+
+	packs, err := LoadPolicyPacks(templatesFS)
+	if err != nil {
+		return policy.Rules{}, nil, err
+	}
+	pack := policy.Select(packs, projRecipe.Project.Type, stackType)
+
+	rules, err := policy.Resolve(base, pack, projRecipe.Verify)
+	if err != nil {
+		return policy.Rules{}, nil, err
+	}
+	return rules, pack, nil
+}
+
+// LoadPolicyPacks reads every policy pack shipped with the templates.
+func LoadPolicyPacks(templatesFS fs.FS) ([]*policy.Pack, error) {
+	files, err := fs.Glob(templatesFS, "policies/*.yml")
+	if err != nil {
+		return nil, err
+	}
+
+	var packs []*policy.Pack
+	for _, file := range files {
+		data, err := fs.ReadFile(templatesFS, file)
+		if err != nil {
+			return nil, err
+		}
+		pack, err := policy.ParsePack(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path.Base(file), err)
+		}
+		packs = append(packs, pack)
+	}
+	return packs, nil
+}
@@ -0,0 +1,247 @@
+package dependencies
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"grei-cli/internal/ports/outbound"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type lockfileParser func(content []byte) (map[string][]string, error)
+
+var lockfileParsers = map[string]lockfileParser{
+	"package-lock.json":   parsePackageLock,
+	"npm-shrinkwrap.json": parsePackageLock,
+	"yarn.lock":           parseYarnLock,
+	"pnpm-lock.yaml":      parsePnpmLock,
+	"composer.lock":       parseComposerLock,
+	"go.sum":              parseGoSum,
+	"poetry.lock":         parsePoetryLock,
+}
+
+// readLockfile returns the first of names that exists under path, or nil if
+// none does.
+func readLockfile(path string, names []string) (*outbound.Lockfile, error) {
+	for _, name := range names {
+		content, err := os.ReadFile(filepath.Join(path, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		lockfile := &outbound.Lockfile{Path: name}
+		if parse, ok := lockfileParsers[name]; ok {
+			packages, err := parse(content)
+			if err != nil {
+				return nil, fmt.Errorf("could not parse %s: %w", name, err)
+			}
+			lockfile.Packages = packages
+		}
+		return lockfile, nil
+	}
+	return nil, nil
+}
+
+func parsePackageLock(content []byte) (map[string][]string, error) {
+	var lock struct {
+		Packages map[string]struct {
+			Version string `json:"version"`
+		} `json:"packages"`
+		Dependencies map[string]struct {
+			Version string `json:"version"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return nil, err
+	}
+
+	packages := make(map[string][]string)
+	// lockfileVersion 2 and 3 key packages by their install path.
+	for key, pkg := range lock.Packages {
+		i := strings.LastIndex(key, "node_modules/")
+		if i < 0 {
+			continue
+		}
+		name := key[i+len("node_modules/"):]
+		packages[name] = appendUnique(packages[name], pkg.Version)
+	}
+	// lockfileVersion 1 only has the dependencies tree.
+	for name, pkg := range lock.Dependencies {
+		packages[name] = appendUnique(packages[name], pkg.Version)
+	}
+	return packages, nil
+}
+
+// parseYarnLock reads both classic (v1) and Berry lockfiles. Entries start
+// with an unindented line listing the specifiers they resolve, followed by
+// an indented version field.
+func parseYarnLock(content []byte) (map[string][]string, error) {
+	packages := make(map[string][]string)
+	var names []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if !strings.HasPrefix(line, " ") && strings.HasSuffix(trimmed, ":") {
+			names = names[:0]
+			for _, spec := range strings.Split(strings.TrimSuffix(trimmed, ":"), ",") {
+				if name := yarnSpecName(strings.Trim(strings.TrimSpace(spec), `"`)); name != "" {
+					names = append(names, name)
+				}
+			}
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "version") {
+			version := strings.TrimSpace(strings.TrimPrefix(trimmed, "version"))
+			version = strings.Trim(strings.TrimPrefix(version, ":"), ` "`)
+			for _, name := range names {
+				packages[name] = appendUnique(packages[name], version)
+			}
+		}
+	}
+	return packages, scanner.Err()
+}
+
+// yarnSpecName extracts the package name from a specifier such as
+// "@babel/core@^7.0.0" or "lodash@npm:4.17.21".
+func yarnSpecName(spec string) string {
+	if spec == "__metadata" {
+		return ""
+	}
+	i := strings.LastIndex(spec, "@")
+	if i <= 0 {
+		return spec
+	}
+	return spec[:i]
+}
+
+func parsePnpmLock(content []byte) (map[string][]string, error) {
+	var lock struct {
+		Importers    map[string]pnpmImporter `yaml:"importers"`
+		pnpmImporter `yaml:",inline"`
+	}
+	if err := yaml.Unmarshal(content, &lock); err != nil {
+		return nil, err
+	}
+
+	packages := make(map[string][]string)
+	importers := []pnpmImporter{lock.pnpmImporter}
+	if root, ok := lock.Importers["."]; ok {
+		importers = append(importers, root)
+	}
+	for _, importer := range importers {
+		for _, deps := range []map[string]yaml.Node{importer.Dependencies, importer.DevDependencies} {
+			for name, node := range deps {
+				if version := pnpmVersion(&node); version != "" {
+					packages[name] = appendUnique(packages[name], version)
+				}
+			}
+		}
+	}
+	return packages, nil
+}
+
+type pnpmImporter struct {
+	Dependencies    map[string]yaml.Node `yaml:"dependencies"`
+	DevDependencies map[string]yaml.Node `yaml:"devDependencies"`
+}
+
+// pnpmVersion reads a dependency entry, which is a plain version in
+// lockfile v5 and a {specifier, version} mapping since v6. Peer dependency
+// suffixes such as "1.0.0(react@18.2.0)" are dropped.
+func pnpmVersion(node *yaml.Node) string {
+	version := node.Value
+	if node.Kind == yaml.MappingNode {
+		var entry struct {
+			Version string `yaml:"version"`
+		}
+		if err := node.Decode(&entry); err != nil {
+			return ""
+		}
+		version = entry.Version
+	}
+	if i := strings.IndexAny(version, "(_"); i >= 0 {
+		version = version[:i]
+	}
+	return version
+}
+
+func parseComposerLock(content []byte) (map[string][]string, error) {
+	type lockedPackage struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	var lock struct {
+		Packages    []lockedPackage `json:"packages"`
+		PackagesDev []lockedPackage `json:"packages-dev"`
+	}
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return nil, err
+	}
+
+	packages := make(map[string][]string)
+	for _, pkg := range append(lock.Packages, lock.PackagesDev...) {
+		packages[pkg.Name] = appendUnique(packages[pkg.Name], strings.TrimPrefix(pkg.Version, "v"))
+	}
+	return packages, nil
+}
+
+func parseGoSum(content []byte) (map[string][]string, error) {
+	packages := make(map[string][]string)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		version := strings.TrimSuffix(fields[1], "/go.mod")
+		packages[fields[0]] = appendUnique(packages[fields[0]], version)
+	}
+	return packages, scanner.Err()
+}
+
+func parsePoetryLock(content []byte) (map[string][]string, error) {
+	packages := make(map[string][]string)
+	var name string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			name = ""
+			continue
+		}
+		if m := tomlKeyString.FindStringSubmatch(line); m != nil {
+			switch m[1] {
+			case "name":
+				name = normalizePythonName(m[2])
+			case "version":
+				if name != "" {
+					packages[name] = appendUnique(packages[name], m[2])
+				}
+			}
+		}
+	}
+	return packages, scanner.Err()
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
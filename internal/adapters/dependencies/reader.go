@@ -0,0 +1,241 @@
+package dependencies
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"grei-cli/internal/ports/outbound"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Ecosystem names reported in outbound.Dependency.
+const (
+	EcosystemNpm      = "npm"
+	EcosystemComposer = "composer"
+	EcosystemGo       = "go"
+	EcosystemPyPI     = "pypi"
+)
+
+// manifestSpec describes a dependency manifest found at the project root.
+type manifestSpec struct {
+	name      string
+	ecosystem string
+	parse     func(content []byte) ([]outbound.Dependency, error)
+	lockfiles []string
+}
+
+var manifestSpecs = []manifestSpec{
+	{"composer.json", EcosystemComposer, parseComposerJSON, []string{"composer.lock"}},
+	{"go.mod", EcosystemGo, parseGoMod, []string{"go.sum"}},
+	{"package.json", EcosystemNpm, parsePackageJSON, []string{"package-lock.json", "npm-shrinkwrap.json", "yarn.lock", "pnpm-lock.yaml"}},
+	{"pyproject.toml", EcosystemPyPI, parsePyprojectToml, []string{"poetry.lock"}},
+	{"requirements.txt", EcosystemPyPI, parseRequirementsTxt, nil},
+}
+
+type fsReader struct{}
+
+// NewFsReader creates a DependencyReader that parses the dependency manifests
+// and lockfiles at the root of a project.
+func NewFsReader() outbound.DependencyReader {
+	return &fsReader{}
+}
+
+func (r *fsReader) Read(path string) ([]outbound.DependencyManifest, error) {
+	var manifests []outbound.DependencyManifest
+	for _, spec := range manifestSpecs {
+		content, err := os.ReadFile(filepath.Join(path, spec.name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		deps, err := spec.parse(content)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse %s: %w", spec.name, err)
+		}
+		for i := range deps {
+			deps[i].Ecosystem = spec.ecosystem
+			deps[i].Source = spec.name
+		}
+
+		lockfile, err := readLockfile(path, spec.lockfiles)
+		if err != nil {
+			return nil, err
+		}
+
+		manifests = append(manifests, outbound.DependencyManifest{
+			Path:          spec.name,
+			Ecosystem:     spec.ecosystem,
+			Dependencies:  deps,
+			LockfileNames: spec.lockfiles,
+			Lockfile:      lockfile,
+		})
+	}
+	return manifests, nil
+}
+
+func parsePackageJSON(content []byte) ([]outbound.Dependency, error) {
+	var pkg struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return nil, err
+	}
+	return fromMaps(pkg.Dependencies, pkg.DevDependencies), nil
+}
+
+func parseComposerJSON(content []byte) ([]outbound.Dependency, error) {
+	var pkg struct {
+		Require    map[string]string `json:"require"`
+		RequireDev map[string]string `json:"require-dev"`
+	}
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return nil, err
+	}
+
+	var deps []outbound.Dependency
+	for _, dep := range fromMaps(pkg.Require, pkg.RequireDev) {
+		if !isComposerPackage(dep.Name) {
+			continue
+		}
+		deps = append(deps, dep)
+	}
+	return deps, nil
+}
+
+// isComposerPackage filters out platform requirements such as php or
+// ext-json, which are not installable packages.
+func isComposerPackage(name string) bool {
+	return strings.Contains(name, "/")
+}
+
+func parseGoMod(content []byte) ([]outbound.Dependency, error) {
+	var deps []outbound.Dependency
+	inBlock := false
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+
+		switch {
+		case line == "require (":
+			inBlock = true
+			continue
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimSpace(strings.TrimPrefix(line, "require "))
+		case !inBlock:
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) >= 2 {
+			deps = append(deps, outbound.Dependency{Name: fields[0], Version: fields[1]})
+		}
+	}
+	return deps, scanner.Err()
+}
+
+var requirementLine = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._\-]*)(\[[^\]]*\])?\s*(.*)$`)
+
+func parseRequirementsTxt(content []byte) ([]outbound.Dependency, error) {
+	var deps []outbound.Dependency
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		// Skip blank lines and pip options such as -r or --index-url.
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue
+		}
+		if i := strings.Index(line, ";"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+
+		m := requirementLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		deps = append(deps, outbound.Dependency{
+			Name:    normalizePythonName(m[1]),
+			Version: strings.TrimSpace(m[3]),
+		})
+	}
+	return deps, scanner.Err()
+}
+
+var (
+	tomlTable     = regexp.MustCompile(`^\[([^\[\]]+)\]$`)
+	tomlKeyString = regexp.MustCompile(`^([A-Za-z0-9._\-"]+)\s*=\s*"([^"]*)"`)
+	tomlKeyTable  = regexp.MustCompile(`^([A-Za-z0-9._\-"]+)\s*=\s*\{.*version\s*=\s*"([^"]*)"`)
+)
+
+// parsePyprojectToml reads the Poetry dependency tables of a pyproject.toml.
+// It understands the subset of TOML Poetry writes: `name = "spec"` and
+// `name = { version = "spec", ... }` entries. Projects that do not use
+// Poetry yield no dependencies.
+func parsePyprojectToml(content []byte) ([]outbound.Dependency, error) {
+	var deps []outbound.Dependency
+	inDeps := false
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if m := tomlTable.FindStringSubmatch(line); m != nil {
+			table := strings.TrimSpace(m[1])
+			inDeps = table == "tool.poetry.dependencies" ||
+				table == "tool.poetry.dev-dependencies" ||
+				strings.HasPrefix(table, "tool.poetry.group.") && strings.HasSuffix(table, ".dependencies")
+			continue
+		}
+		if !inDeps {
+			continue
+		}
+
+		m := tomlKeyString.FindStringSubmatch(line)
+		if m == nil {
+			m = tomlKeyTable.FindStringSubmatch(line)
+		}
+		if m == nil {
+			continue
+		}
+		name := normalizePythonName(strings.Trim(m[1], `"`))
+		if name == "python" {
+			continue
+		}
+		deps = append(deps, outbound.Dependency{Name: name, Version: m[2]})
+	}
+	return deps, scanner.Err()
+}
+
+// normalizePythonName applies the PEP 503 normalization so that names from
+// manifests and lockfiles compare equal.
+func normalizePythonName(name string) string {
+	name = strings.ToLower(name)
+	return strings.NewReplacer("_", "-", ".", "-").Replace(name)
+}
+
+// fromMaps flattens name → version maps into a list sorted by name.
+func fromMaps(maps ...map[string]string) []outbound.Dependency {
+	var deps []outbound.Dependency
+	for _, m := range maps {
+		for name, version := range m {
+			deps = append(deps, outbound.Dependency{Name: name, Version: version})
+		}
+	}
+	sort.Slice(deps, func(i, j int) bool { return deps[i].Name < deps[j].Name })
+	return deps
+}
@@ -0,0 +1,183 @@
+package dependencies
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"package.json": `{
+  "dependencies": {"express": "4.19.2"},
+  "devDependencies": {"jest": "^29.0.0"}
+}`,
+		"composer.json": `{"require": {"php": ">=8.2", "ext-json": "*", "symfony/framework-bundle": "7.0.*"}}`,
+		"go.mod": `module example.com/app
+
+go 1.22
+
+require github.com/spf13/cobra v1.8.0
+
+require (
+	github.com/spf13/pflag v1.0.5 // indirect
+)
+`,
+		"requirements.txt": `# web
+fastapi==0.110.0
+uvicorn[standard]>=0.29 ; python_version >= "3.8"
+-r dev.txt
+`,
+		"pyproject.toml": `[tool.poetry]
+name = "app"
+
+[tool.poetry.dependencies]
+python = "^3.11"
+Django = "5.0.3"
+celery = { version = "5.3.6", extras = ["redis"] }
+
+[tool.poetry.group.dev.dependencies]
+pytest = "^8.0"
+`,
+	})
+
+	manifests, err := NewFsReader().Read(tmpDir)
+	if err != nil {
+		t.Fatalf("Read() returned an unexpected error: %v", err)
+	}
+
+	got := make(map[string]string)
+	for _, manifest := range manifests {
+		for _, dep := range manifest.Dependencies {
+			got[dep.Source+":"+dep.Name] = dep.Version
+		}
+	}
+	want := map[string]string{
+		"package.json:express":                   "4.19.2",
+		"package.json:jest":                      "^29.0.0",
+		"composer.json:symfony/framework-bundle": "7.0.*",
+		"go.mod:github.com/spf13/cobra":          "v1.8.0",
+		"go.mod:github.com/spf13/pflag":          "v1.0.5",
+		"requirements.txt:fastapi":               "==0.110.0",
+		"requirements.txt:uvicorn":               ">=0.29",
+		"pyproject.toml:django":                  "5.0.3",
+		"pyproject.toml:celery":                  "5.3.6",
+		"pyproject.toml:pytest":                  "^8.0",
+	}
+
+	if len(got) != len(want) {
+		t.Errorf("Expected %d dependencies, got %d: %v", len(want), len(got), got)
+	}
+	for key, version := range want {
+		if got[key] != version {
+			t.Errorf("Expected %s to have version %q, got %q", key, version, got[key])
+		}
+	}
+	for _, manifest := range manifests {
+		if manifest.Lockfile != nil {
+			t.Errorf("Expected no lockfile for %s, got %s", manifest.Path, manifest.Lockfile.Path)
+		}
+	}
+}
+
+func TestRead_Lockfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		lockfile string
+		pkg      string
+		version  string
+	}{
+		{
+			name: "package-lock v3",
+			files: map[string]string{
+				"package.json":      `{"dependencies": {"express": "4.19.2"}}`,
+				"package-lock.json": `{"lockfileVersion": 3, "packages": {"": {}, "node_modules/express": {"version": "4.19.2"}}}`,
+			},
+			lockfile: "package-lock.json", pkg: "express", version: "4.19.2",
+		},
+		{
+			name: "yarn classic",
+			files: map[string]string{
+				"package.json": `{"dependencies": {"@babel/core": "7.24.0"}}`,
+				"yarn.lock":    "# yarn lockfile v1\n\n\"@babel/core@7.24.0\", \"@babel/core@^7.0.0\":\n  version \"7.24.0\"\n",
+			},
+			lockfile: "yarn.lock", pkg: "@babel/core", version: "7.24.0",
+		},
+		{
+			name: "pnpm v9",
+			files: map[string]string{
+				"package.json":   `{"dependencies": {"vue": "3.4.21"}}`,
+				"pnpm-lock.yaml": "lockfileVersion: '9.0'\nimporters:\n  .:\n    dependencies:\n      vue:\n        specifier: 3.4.21\n        version: 3.4.21(typescript@5.4.2)\n",
+			},
+			lockfile: "pnpm-lock.yaml", pkg: "vue", version: "3.4.21",
+		},
+		{
+			name: "composer.lock",
+			files: map[string]string{
+				"composer.json": `{"require": {"symfony/console": "7.0.4"}}`,
+				"composer.lock": `{"packages": [{"name": "symfony/console", "version": "v7.0.4"}]}`,
+			},
+			lockfile: "composer.lock", pkg: "symfony/console", version: "7.0.4",
+		},
+		{
+			name: "go.sum",
+			files: map[string]string{
+				"go.mod": "module example.com/app\n\nrequire github.com/spf13/cobra v1.8.0\n",
+				"go.sum": "github.com/spf13/cobra v1.8.0 h1:abc=\ngithub.com/spf13/cobra v1.8.0/go.mod h1:def=\n",
+			},
+			lockfile: "go.sum", pkg: "github.com/spf13/cobra", version: "v1.8.0",
+		},
+		{
+			name: "poetry.lock",
+			files: map[string]string{
+				"pyproject.toml": "[tool.poetry.dependencies]\nDjango = \"5.0.3\"\n",
+				"poetry.lock":    "[[package]]\nname = \"Django\"\nversion = \"5.0.3\"\n\n[package.dependencies]\nasgiref = \">=3.7.0\"\n",
+			},
+			lockfile: "poetry.lock", pkg: "django", version: "5.0.3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			writeFiles(t, tmpDir, tt.files)
+
+			manifests, err := NewFsReader().Read(tmpDir)
+			if err != nil {
+				t.Fatalf("Read() returned an unexpected error: %v", err)
+			}
+			if len(manifests) != 1 || manifests[0].Lockfile == nil {
+				t.Fatalf("Expected one manifest with a lockfile, got %+v", manifests)
+			}
+
+			lockfile := manifests[0].Lockfile
+			if lockfile.Path != tt.lockfile {
+				t.Errorf("Expected lockfile %s, got %s", tt.lockfile, lockfile.Path)
+			}
+			versions := lockfile.Packages[tt.pkg]
+			if len(versions) != 1 || versions[0] != tt.version {
+				t.Errorf("Expected %s to be locked at %s, got %v", tt.pkg, tt.version, versions)
+			}
+		})
+	}
+}
+
+func TestRead_InvalidManifest(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{"package.json": "{"})
+
+	if _, err := NewFsReader().Read(tmpDir); err == nil {
+		t.Error("Read() should have returned an error, but it did not")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 )
 
 // GitDownloader is an adapter for downloading files from a Git repository.
@@ -16,17 +17,35 @@ func NewGitDownloader() *GitDownloader {
 	return &GitDownloader{}
 }
 
-// Download clones or pulls a remote repository into a local cache directory.
-func (d *GitDownloader) Download(ctx context.Context, url, branch, cacheDir string) error {
+// Download fetches ref, a branch, tag or commit, of a remote repository into
+// a local cache directory and checks it out.
+func (d *GitDownloader) Download(ctx context.Context, url, ref, cacheDir string) error {
 	gitDir := filepath.Join(cacheDir, ".git")
 	_, err := os.Stat(gitDir)
 	if os.IsNotExist(err) {
-		return d.sparseClone(ctx, url, branch, cacheDir)
+		if err := d.sparseInit(ctx, url, cacheDir); err != nil {
+			return err
+		}
 	}
-	return d.pull(ctx, branch, cacheDir)
+
+	if err := d.runGitCommand(ctx, cacheDir, "fetch", "--depth", "1", "origin", ref); err != nil {
+		return err
+	}
+	return d.runGitCommand(ctx, cacheDir, "checkout", "--force", "--detach", "FETCH_HEAD")
+}
+
+// Revision returns the commit checked out in cacheDir.
+func (d *GitDownloader) Revision(ctx context.Context, cacheDir string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
+	cmd.Dir = cacheDir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read the templates revision: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
 }
 
-func (d *GitDownloader) sparseClone(ctx context.Context, url, branch, cacheDir string) error {
+func (d *GitDownloader) sparseInit(ctx context.Context, url, cacheDir string) error {
 	if err := os.MkdirAll(cacheDir, os.ModePerm); err != nil {
 		return fmt.Errorf("failed to create cache directory: %w", err)
 	}
@@ -52,18 +71,9 @@ func (d *GitDownloader) sparseClone(ctx context.Context, url, branch, cacheDir s
 		return fmt.Errorf("failed to write sparse-checkout file: %w", err)
 	}
 
-	// Pull the files
-	if err := d.runGitCommand(ctx, cacheDir, "pull", "origin", branch); err != nil {
-		return err
-	}
-
 	return nil
 }
 
-func (d *GitDownloader) pull(ctx context.Context, branch, cacheDir string) error {
-	return d.runGitCommand(ctx, cacheDir, "pull", "origin", branch)
-}
-
 func (d *GitDownloader) runGitCommand(ctx context.Context, dir string, args ...string) error {
 	cmd := exec.CommandContext(ctx, "git", args...)
 	cmd.Dir = dir
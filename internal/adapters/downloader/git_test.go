@@ -3,7 +3,9 @@ package downloader
 import (
 	"context"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -40,3 +42,67 @@ func TestGitDownloader_Download(t *testing.T) {
 		t.Errorf("Download() failed to pull: %v", err)
 	}
 }
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func TestGitDownloader_DownloadRef(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	// A local repository with two tagged template versions.
+	origin := t.TempDir()
+	runGit(t, origin, "init", "--initial-branch=master")
+	manifest := filepath.Join(origin, "templates", "manifest.json")
+	if err := os.MkdirAll(filepath.Dir(manifest), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, version := range []string{"1.0.0", "1.1.0"} {
+		if err := os.WriteFile(manifest, []byte(`{"version": "`+version+`"}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, origin, "add", "-A")
+		runGit(t, origin, "commit", "-m", version)
+		runGit(t, origin, "tag", "v"+version)
+	}
+	firstCommit := runGit(t, origin, "rev-parse", "v1.0.0")
+
+	cacheDir := t.TempDir()
+	downloader := NewGitDownloader()
+	url := "file://" + origin
+
+	for _, tt := range []struct{ ref, version string }{
+		{"v1.0.0", "1.0.0"},
+		{"master", "1.1.0"},
+		{firstCommit, "1.0.0"},
+	} {
+		if err := downloader.Download(context.Background(), url, tt.ref, cacheDir); err != nil {
+			t.Fatalf("Download(%s) failed: %v", tt.ref, err)
+		}
+		content, err := os.ReadFile(filepath.Join(cacheDir, "templates", "manifest.json"))
+		if err != nil {
+			t.Fatalf("Download(%s) did not check out the templates: %v", tt.ref, err)
+		}
+		if !strings.Contains(string(content), tt.version) {
+			t.Errorf("Download(%s) checked out %s, want version %s", tt.ref, content, tt.version)
+		}
+	}
+
+	revision, err := downloader.Revision(context.Background(), cacheDir)
+	if err != nil {
+		t.Fatalf("Revision() failed: %v", err)
+	}
+	if revision != firstCommit {
+		t.Errorf("Revision() = %s, want %s", revision, firstCommit)
+	}
+}
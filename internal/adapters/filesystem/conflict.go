@@ -0,0 +1,154 @@
+package filesystem
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"grei-cli/internal/ports/outbound"
+	"io/fs"
+	"path/filepath"
+)
+
+// ConflictPolicy decides what happens when a file being written already
+// exists with different content.
+type ConflictPolicy string
+
+const (
+	ConflictSkip      ConflictPolicy = "skip"
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	ConflictPrompt    ConflictPolicy = "prompt"
+	ConflictBackup    ConflictPolicy = "backup"
+)
+
+// ParseConflictPolicy validates a policy given by name.
+func ParseConflictPolicy(name string) (ConflictPolicy, error) {
+	switch policy := ConflictPolicy(name); policy {
+	case ConflictSkip, ConflictOverwrite, ConflictPrompt, ConflictBackup:
+		return policy, nil
+	}
+	return "", fmt.Errorf("unknown conflict policy %q (valid: skip, overwrite, prompt, backup)", name)
+}
+
+// ConflictPrompter asks how to resolve a single conflict. It returns the
+// policy to apply and whether to apply it to every remaining conflict.
+type ConflictPrompter func(path string, current, content []byte) (ConflictPolicy, bool, error)
+
+// ConflictSummary lists the conflicting files by how they were resolved.
+type ConflictSummary struct {
+	Overwritten []string
+	Skipped     []string
+	// BackedUp maps each overwritten file to the copy of its old content.
+	BackedUp map[string]string
+}
+
+// ConflictRepository is an FSRepository that applies a ConflictPolicy
+// before overwriting existing files. Files with identical content are left
+// alone, and files it wrote itself can be overwritten freely, so later
+// skeletons still override earlier ones.
+type ConflictRepository struct {
+	outbound.FSRepository
+	Policy   ConflictPolicy
+	prompter ConflictPrompter
+	written  map[string]bool
+	skipped  map[string]bool
+	summary  ConflictSummary
+}
+
+// NewConflictRepository wraps inner with the given policy. prompter may be
+// nil when no terminal is attached; the prompt policy then skips conflicts.
+func NewConflictRepository(inner outbound.FSRepository, policy ConflictPolicy, prompter ConflictPrompter) *ConflictRepository {
+	return &ConflictRepository{
+		FSRepository: inner,
+		Policy:       policy,
+		prompter:     prompter,
+		written:      make(map[string]bool),
+		skipped:      make(map[string]bool),
+		summary:      ConflictSummary{BackedUp: make(map[string]string)},
+	}
+}
+
+func (r *ConflictRepository) CreateFile(path string, content []byte) error {
+	if r.skipped[path] {
+		return nil
+	}
+	if !r.written[path] {
+		current, err := r.FSRepository.ReadFile(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		if err == nil && !bytes.Equal(current, content) {
+			write, err := r.resolve(path, current, content)
+			if err != nil || !write {
+				return err
+			}
+		}
+	}
+
+	if err := r.FSRepository.CreateFile(path, content); err != nil {
+		return err
+	}
+	r.written[path] = true
+	return nil
+}
+
+// Summary returns the conflicts found so far.
+func (r *ConflictRepository) Summary() ConflictSummary {
+	return r.summary
+}
+
+// resolve applies the policy to an existing file, and reports whether it
+// should be overwritten.
+func (r *ConflictRepository) resolve(path string, current, content []byte) (bool, error) {
+	policy := r.Policy
+	if policy == ConflictPrompt {
+		if r.prompter == nil {
+			policy = ConflictSkip
+		} else {
+			var all bool
+			var err error
+			policy, all, err = r.prompter(path, current, content)
+			if err != nil {
+				return false, err
+			}
+			if all {
+				r.Policy = policy
+			}
+		}
+	}
+
+	switch policy {
+	case ConflictSkip:
+		r.skipped[path] = true
+		r.summary.Skipped = append(r.summary.Skipped, path)
+		return false, nil
+	case ConflictBackup:
+		backup, err := r.backup(path, current)
+		if err != nil {
+			return false, err
+		}
+		r.summary.BackedUp[path] = backup
+	default:
+		r.summary.Overwritten = append(r.summary.Overwritten, path)
+	}
+	return true, nil
+}
+
+// backup copies current next to path, to the first free name among
+// <path>.bak, <path>.bak.1, <path>.bak.2...
+func (r *ConflictRepository) backup(path string, current []byte) (string, error) {
+	backup := path + ".bak"
+	for i := 1; ; i++ {
+		_, err := r.FSRepository.ReadFile(backup)
+		if errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		backup = fmt.Sprintf("%s.bak.%d", path, i)
+	}
+	if err := r.FSRepository.CreateDir(filepath.Dir(backup)); err != nil {
+		return "", err
+	}
+	return backup, r.FSRepository.CreateFile(backup, current)
+}
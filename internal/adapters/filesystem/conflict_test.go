@@ -0,0 +1,158 @@
+package filesystem
+
+import (
+	"testing"
+)
+
+func newConflictRepo(t *testing.T, policy ConflictPolicy, prompter ConflictPrompter) (*MemoryRepository, *ConflictRepository) {
+	t.Helper()
+	mem := NewMemoryRepository()
+	for name, content := range map[string]string{
+		"project/Makefile":  "hand-edited\n",
+		"project/README.md": "# Same\n",
+	} {
+		if err := mem.CreateFile(name, []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return mem, NewConflictRepository(mem, policy, prompter)
+}
+
+func TestConflictRepository(t *testing.T) {
+	tests := []struct {
+		policy       ConflictPolicy
+		wantMakefile string
+		wantBackup   string
+		wantSkipped  int
+		wantWritten  int
+	}{
+		{policy: ConflictSkip, wantMakefile: "hand-edited\n", wantSkipped: 1},
+		{policy: ConflictOverwrite, wantMakefile: "generated\n", wantWritten: 1},
+		{policy: ConflictBackup, wantMakefile: "generated\n", wantBackup: "hand-edited\n"},
+		{policy: ConflictPrompt, wantMakefile: "hand-edited\n", wantSkipped: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			mem, repo := newConflictRepo(t, tt.policy, nil)
+			for name, content := range map[string]string{
+				"project/Makefile":  "generated\n",
+				"project/README.md": "# Same\n",
+				"project/new.txt":   "new\n",
+			} {
+				if err := repo.CreateFile(name, []byte(content)); err != nil {
+					t.Fatalf("CreateFile(%s) returned an unexpected error: %v", name, err)
+				}
+			}
+
+			files := mem.Files()
+			if got := string(files["project/Makefile"]); got != tt.wantMakefile {
+				t.Errorf("Expected Makefile %q, got %q", tt.wantMakefile, got)
+			}
+			if got := string(files["project/Makefile.bak"]); got != tt.wantBackup {
+				t.Errorf("Expected backup %q, got %q", tt.wantBackup, got)
+			}
+			if got := string(files["project/new.txt"]); got != "new\n" {
+				t.Errorf("New files should always be created, got %q", got)
+			}
+
+			summary := repo.Summary()
+			if len(summary.Skipped) != tt.wantSkipped || len(summary.Overwritten) != tt.wantWritten {
+				t.Errorf("Unexpected summary: %+v", summary)
+			}
+		})
+	}
+}
+
+func TestConflictRepository_Prompt(t *testing.T) {
+	mem, repo := newConflictRepo(t, ConflictPrompt, nil)
+	if err := mem.CreateFile("project/go.mod", []byte("module mine\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	var asked []string
+	repo.prompter = func(path string, current, content []byte) (ConflictPolicy, bool, error) {
+		asked = append(asked, path)
+		return ConflictBackup, true, nil
+	}
+
+	writes := []struct{ path, content string }{
+		{"project/Makefile", "generated\n"},
+		{"project/Makefile", "generated again\n"},
+		{"project/go.mod", "module generated\n"},
+	}
+	for _, w := range writes {
+		if err := repo.CreateFile(w.path, []byte(w.content)); err != nil {
+			t.Fatalf("CreateFile(%s) returned an unexpected error: %v", w.path, err)
+		}
+	}
+
+	if len(asked) != 1 {
+		t.Errorf("Expected a single prompt when applying the answer to all, got %v", asked)
+	}
+	files := mem.Files()
+	if got := string(files["project/Makefile.bak"]); got != "hand-edited\n" {
+		t.Errorf("Expected the original Makefile to be backed up once, got %q", got)
+	}
+	if _, ok := files["project/Makefile.bak.1"]; ok {
+		t.Error("A file written twice should only be backed up once")
+	}
+	if got := string(files["project/go.mod.bak"]); got != "module mine\n" {
+		t.Errorf("Expected go.mod to be backed up, got %q", got)
+	}
+}
+
+func TestConflictRepository_BackupName(t *testing.T) {
+	mem, repo := newConflictRepo(t, ConflictBackup, nil)
+	if err := mem.CreateFile("project/Makefile.bak", []byte("older\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.CreateFile("project/Makefile", []byte("generated\n")); err != nil {
+		t.Fatalf("CreateFile() returned an unexpected error: %v", err)
+	}
+
+	files := mem.Files()
+	if got := string(files["project/Makefile.bak"]); got != "older\n" {
+		t.Errorf("Existing backups should not be overwritten, got %q", got)
+	}
+	if got := string(files["project/Makefile.bak.1"]); got != "hand-edited\n" {
+		t.Errorf("Expected the backup in Makefile.bak.1, got %q", got)
+	}
+}
+
+func TestParseConflictPolicy(t *testing.T) {
+	if _, err := ParseConflictPolicy("backup"); err != nil {
+		t.Errorf("Expected backup to be valid, got %v", err)
+	}
+	if _, err := ParseConflictPolicy("merge"); err == nil {
+		t.Error("Expected an error for an unknown policy")
+	}
+}
+
+func TestConflictRepository_Layers(t *testing.T) {
+	mem, repo := newConflictRepo(t, ConflictSkip, nil)
+
+	writes := []struct{ path, content string }{
+		{"project/.gitignore", "generic\n"},
+		{"project/.gitignore", "stack\n"},
+		{"project/Makefile", "generic\n"},
+		{"project/Makefile", "stack\n"},
+	}
+	for _, w := range writes {
+		if err := repo.CreateFile(w.path, []byte(w.content)); err != nil {
+			t.Fatalf("CreateFile(%s) returned an unexpected error: %v", w.path, err)
+		}
+	}
+
+	files := mem.Files()
+	if got := string(files["project/.gitignore"]); got != "stack\n" {
+		t.Errorf("Later skeletons should override files written earlier, got %q", got)
+	}
+	if got := string(files["project/Makefile"]); got != "hand-edited\n" {
+		t.Errorf("A skipped file should stay skipped, got %q", got)
+	}
+	if summary := repo.Summary(); len(summary.Skipped) != 1 {
+		t.Errorf("Expected a single skipped file, got %+v", summary)
+	}
+}
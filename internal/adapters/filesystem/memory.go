@@ -0,0 +1,125 @@
+package filesystem
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MemoryRepository is an FSRepository that keeps every file and directory in
+// memory. It is used to render projects without touching the disk.
+type MemoryRepository struct {
+	files map[string][]byte
+	dirs  map[string]bool
+}
+
+// NewMemoryRepository creates an empty in-memory FSRepository.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		files: make(map[string][]byte),
+		dirs:  make(map[string]bool),
+	}
+}
+
+func (m *MemoryRepository) CreateDir(path string) error {
+	for dir := clean(path); dir != "." && !m.dirs[dir]; dir = filepath.ToSlash(filepath.Dir(dir)) {
+		m.dirs[dir] = true
+	}
+	return nil
+}
+
+func (m *MemoryRepository) CreateFile(path string, content []byte) error {
+	path = clean(path)
+	if err := m.CreateDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	m.files[path] = append([]byte(nil), content...)
+	return nil
+}
+
+func (m *MemoryRepository) GetCacheDir(path string) (string, error) {
+	return path, nil
+}
+
+func (m *MemoryRepository) ReadFile(path string) ([]byte, error) {
+	content, ok := m.files[clean(path)]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+	}
+	return append([]byte(nil), content...), nil
+}
+
+func (m *MemoryRepository) ReadDir(path string) ([]os.DirEntry, error) {
+	dir := clean(path)
+	if dir != "." && !m.dirs[dir] {
+		return nil, &fs.PathError{Op: "readdir", Path: path, Err: fs.ErrNotExist}
+	}
+
+	prefix := dir + "/"
+	if dir == "." {
+		prefix = ""
+	}
+	children := make(map[string]bool)
+	for name := range m.files {
+		if rest, ok := strings.CutPrefix(name, prefix); ok && !strings.Contains(rest, "/") {
+			children[rest] = false
+		}
+	}
+	for name := range m.dirs {
+		if rest, ok := strings.CutPrefix(name, prefix); ok && rest != "" && !strings.Contains(rest, "/") {
+			children[rest] = true
+		}
+	}
+
+	entries := make([]os.DirEntry, 0, len(children))
+	for name, isDir := range children {
+		info := memoryFileInfo{name: name, isDir: isDir, size: int64(len(m.files[prefix+name]))}
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// Files returns the content of every file, keyed by its slash-separated path.
+func (m *MemoryRepository) Files() map[string][]byte {
+	files := make(map[string][]byte, len(m.files))
+	for name, content := range m.files {
+		files[name] = content
+	}
+	return files
+}
+
+// Dirs returns every directory created, sorted.
+func (m *MemoryRepository) Dirs() []string {
+	dirs := make([]string, 0, len(m.dirs))
+	for dir := range m.dirs {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+func clean(path string) string {
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+type memoryFileInfo struct {
+	name  string
+	isDir bool
+	size  int64
+}
+
+func (i memoryFileInfo) Name() string { return i.name }
+func (i memoryFileInfo) Size() int64  { return i.size }
+func (i memoryFileInfo) Mode() fs.FileMode {
+	if i.isDir {
+		return fs.ModeDir | 0755
+	}
+	return 0644
+}
+func (i memoryFileInfo) ModTime() time.Time { return time.Time{} }
+func (i memoryFileInfo) IsDir() bool        { return i.isDir }
+func (i memoryFileInfo) Sys() interface{}   { return nil }
@@ -0,0 +1,51 @@
+package filesystem
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	repo := NewMemoryRepository()
+
+	if err := repo.CreateFile("project/docs/adr/0001.md", []byte("# ADR")); err != nil {
+		t.Fatalf("CreateFile() returned an unexpected error: %v", err)
+	}
+	if err := repo.CreateFile("project/README.md", []byte("# Project")); err != nil {
+		t.Fatalf("CreateFile() returned an unexpected error: %v", err)
+	}
+	if err := repo.CreateDir("project/deploy/helm"); err != nil {
+		t.Fatalf("CreateDir() returned an unexpected error: %v", err)
+	}
+
+	content, err := repo.ReadFile("project/./README.md")
+	if err != nil || string(content) != "# Project" {
+		t.Errorf("ReadFile() = %q, %v", content, err)
+	}
+	if _, err := repo.ReadFile("project/missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected fs.ErrNotExist for a missing file, got %v", err)
+	}
+
+	entries, err := repo.ReadDir("project")
+	if err != nil {
+		t.Fatalf("ReadDir() returned an unexpected error: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if len(names) != 3 || names[0] != "README.md" || names[1] != "deploy" || names[2] != "docs" {
+		t.Errorf("Unexpected entries: %v", names)
+	}
+	if entries[0].IsDir() || !entries[1].IsDir() {
+		t.Error("Expected README.md to be a file and deploy a directory")
+	}
+
+	if len(repo.Files()) != 2 {
+		t.Errorf("Expected 2 files, got %v", repo.Files())
+	}
+	if dirs := repo.Dirs(); len(dirs) != 5 {
+		t.Errorf("Expected parent directories to be recorded, got %v", dirs)
+	}
+}
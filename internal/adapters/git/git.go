@@ -1,8 +1,10 @@
 package git
 
 import (
+	"fmt"
 	"grei-cli/internal/ports/outbound"
 	"os/exec"
+	"strings"
 )
 
 type repository struct{}
@@ -12,19 +14,44 @@ func NewRepository() outbound.GitRepository {
 }
 
 func (r *repository) SetConfig(path, key, value string) error {
-	cmd := exec.Command("git", "config", key, value)
-	cmd.Dir = path
-	return cmd.Run()
+	return run(path, "config", key, value)
 }
 
 func (r *repository) Init(path string) error {
-	cmd := exec.Command("git", "init")
-	cmd.Dir = path
-	return cmd.Run()
+	return run(path, "init")
 }
 
 func (r *repository) CreateBranch(path, branchName string) error {
-	cmd := exec.Command("git", "checkout", "-b", branchName)
+	return run(path, "checkout", "-b", branchName)
+}
+
+func (r *repository) SetInitialBranch(path, branchName string) error {
+	return run(path, "symbolic-ref", "HEAD", "refs/heads/"+branchName)
+}
+
+func (r *repository) AddAll(path string) error {
+	return run(path, "add", "--all")
+}
+
+func (r *repository) Commit(path, message string) error {
+	return run(path, "commit", "--quiet", "--no-verify", "-m", message)
+}
+
+func (r *repository) AddRemote(path, name, url string) error {
+	return run(path, "remote", "add", name, url)
+}
+
+// run runs git with args in path. Its error carries what git printed, e.g.
+// that no identity is configured for a commit.
+func run(path string, args ...string) error {
+	cmd := exec.Command("git", args...)
 	cmd.Dir = path
-	return cmd.Run()
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if message := strings.TrimSpace(string(output)); message != "" {
+		return fmt.Errorf("git %s: %s", args[0], message)
+	}
+	return fmt.Errorf("git %s: %w", args[0], err)
 }
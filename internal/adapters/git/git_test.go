@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -109,3 +110,83 @@ func TestCreateBranch(t *testing.T) {
 		t.Errorf("Expected current branch to be 'new-branch', but got '%s'", string(out))
 	}
 }
+
+// gitOutput runs git in dir and returns its trimmed output.
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git %v failed: %v", args, err)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestInitialCommit(t *testing.T) {
+	repo := NewRepository()
+	tmpDir := t.TempDir()
+
+	if err := repo.Init(tmpDir); err != nil {
+		t.Fatalf("Init() returned an unexpected error: %v", err)
+	}
+	if err := repo.SetInitialBranch(tmpDir, "main"); err != nil {
+		t.Fatalf("SetInitialBranch() returned an unexpected error: %v", err)
+	}
+	for key, value := range map[string]string{"user.name": "Test User", "user.email": "test@example.com"} {
+		if err := repo.SetConfig(tmpDir, key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "README.md"), []byte("# test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.AddAll(tmpDir); err != nil {
+		t.Fatalf("AddAll() returned an unexpected error: %v", err)
+	}
+	if err := repo.Commit(tmpDir, "chore: initial commit"); err != nil {
+		t.Fatalf("Commit() returned an unexpected error: %v", err)
+	}
+
+	if branch := gitOutput(t, tmpDir, "rev-parse", "--abbrev-ref", "HEAD"); branch != "main" {
+		t.Errorf("Expected the first commit on 'main', but got '%s'", branch)
+	}
+	if files := gitOutput(t, tmpDir, "ls-tree", "--name-only", "HEAD"); files != "README.md" {
+		t.Errorf("Expected the commit to contain README.md, but got '%s'", files)
+	}
+	if message := gitOutput(t, tmpDir, "log", "-1", "--format=%s"); message != "chore: initial commit" {
+		t.Errorf("Expected the commit message 'chore: initial commit', but got '%s'", message)
+	}
+}
+
+func TestCommit_ReportsGitOutput(t *testing.T) {
+	repo := NewRepository()
+	tmpDir := t.TempDir()
+	if err := repo.Init(tmpDir); err != nil {
+		t.Fatal(err)
+	}
+
+	err := repo.Commit(tmpDir, "empty")
+	if err == nil || !strings.HasPrefix(err.Error(), "git commit: ") || err.Error() == "git commit: " {
+		t.Errorf("Commit() = %v, want the reason git gave", err)
+	}
+}
+
+func TestAddRemote(t *testing.T) {
+	repo := NewRepository()
+	tmpDir := t.TempDir()
+	if err := repo.Init(tmpDir); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.AddRemote(tmpDir, "origin", "git@example.com:acme/web.git"); err != nil {
+		t.Fatalf("AddRemote() returned an unexpected error: %v", err)
+	}
+	if url := gitOutput(t, tmpDir, "remote", "get-url", "origin"); url != "git@example.com:acme/web.git" {
+		t.Errorf("Expected origin to point at the URL, but got '%s'", url)
+	}
+	if err := repo.AddRemote(tmpDir, "origin", "git@example.com:acme/other.git"); err == nil {
+		t.Error("AddRemote() should report a remote that already exists")
+	}
+}
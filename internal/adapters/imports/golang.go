@@ -0,0 +1,68 @@
+package imports
+
+import (
+	"bufio"
+	"go/parser"
+	"go/token"
+	"grei-cli/internal/ports/outbound"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type goParser struct {
+	module string
+}
+
+// newGoParser reads the module path from go.mod so that imports of the
+// project's own packages can be mapped back to directories.
+func newGoParser(root string) *goParser {
+	p := &goParser{}
+	file, err := os.Open(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return p
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "module ") {
+			p.module = strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module ")), `"`)
+			break
+		}
+	}
+	return p
+}
+
+func (p *goParser) Parse(rel string, content []byte) ([]outbound.SourceImport, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, rel, content, parser.ImportsOnly)
+	if err != nil {
+		// Broken files are reported by the compiler, not by this check.
+		return nil, nil
+	}
+
+	var found []outbound.SourceImport
+	for _, spec := range file.Imports {
+		specifier, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		imp := outbound.SourceImport{
+			File:      rel,
+			Line:      fset.Position(spec.Pos()).Line,
+			Specifier: specifier,
+		}
+		if p.module != "" && (specifier == p.module || strings.HasPrefix(specifier, p.module+"/")) {
+			imp.Target = strings.TrimPrefix(strings.TrimPrefix(specifier, p.module), "/")
+			if imp.Target == "" {
+				imp.Target = "."
+			}
+		}
+		found = append(found, imp)
+	}
+	return found, nil
+}
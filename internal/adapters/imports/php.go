@@ -0,0 +1,95 @@
+package imports
+
+import (
+	"encoding/json"
+	"grei-cli/internal/ports/outbound"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// phpUse matches namespace imports at the top level of a file. Closure
+// `use (...)` clauses and trait uses inside classes are indented or followed
+// by a parenthesis, so they do not match.
+var phpUse = regexp.MustCompile(`(?m)^use\s+(?:function\s+|const\s+)?([A-Za-z_\\][A-Za-z0-9_\\]*)(?:\s+as\s+\w+)?\s*;`)
+
+type phpParser struct {
+	// prefixes maps PSR-4 namespace prefixes to directories, longest first.
+	prefixes []psr4Prefix
+}
+
+type psr4Prefix struct {
+	namespace string
+	dir       string
+}
+
+// newPHPParser reads the PSR-4 autoload rules from composer.json so that
+// project namespaces can be mapped back to directories.
+func newPHPParser(root string) *phpParser {
+	p := &phpParser{}
+	data, err := os.ReadFile(filepath.Join(root, "composer.json"))
+	if err != nil {
+		return p
+	}
+
+	var composer struct {
+		Autoload struct {
+			PSR4 map[string]interface{} `json:"psr-4"`
+		} `json:"autoload"`
+		AutoloadDev struct {
+			PSR4 map[string]interface{} `json:"psr-4"`
+		} `json:"autoload-dev"`
+	}
+	if err := json.Unmarshal(data, &composer); err != nil {
+		return p
+	}
+
+	for _, rules := range []map[string]interface{}{composer.Autoload.PSR4, composer.AutoloadDev.PSR4} {
+		for namespace, dirs := range rules {
+			// A prefix maps to a single directory or a list of them; the
+			// first one is enough to locate the layer.
+			var dir string
+			switch d := dirs.(type) {
+			case string:
+				dir = d
+			case []interface{}:
+				if len(d) > 0 {
+					dir, _ = d[0].(string)
+				}
+			}
+			p.prefixes = append(p.prefixes, psr4Prefix{namespace: namespace, dir: dir})
+		}
+	}
+	sort.Slice(p.prefixes, func(i, j int) bool { return len(p.prefixes[i].namespace) > len(p.prefixes[j].namespace) })
+	return p
+}
+
+func (p *phpParser) Parse(rel string, content []byte) ([]outbound.SourceImport, error) {
+	var found []outbound.SourceImport
+	for _, m := range phpUse.FindAllSubmatchIndex(content, -1) {
+		specifier := strings.TrimPrefix(string(content[m[2]:m[3]]), `\`)
+		found = append(found, outbound.SourceImport{
+			File:      rel,
+			Line:      lineOf(content, m[2]),
+			Specifier: specifier,
+			Target:    p.resolve(specifier),
+		})
+	}
+	return found, nil
+}
+
+// resolve maps a fully qualified class name to its file path, without the
+// .php extension, or returns an empty string for external namespaces.
+func (p *phpParser) resolve(class string) string {
+	for _, prefix := range p.prefixes {
+		if strings.HasPrefix(class+`\`, prefix.namespace) {
+			rest := strings.TrimPrefix(class, strings.TrimSuffix(prefix.namespace, `\`))
+			rest = strings.ReplaceAll(strings.TrimPrefix(rest, `\`), `\`, "/")
+			return path.Join(strings.TrimSuffix(prefix.dir, "/"), rest)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+package imports
+
+import (
+	"grei-cli/internal/ports/outbound"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// skippedDirs are never scanned: they hold dependencies, build output or VCS
+// metadata rather than project code.
+var skippedDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+	"vendor":       true,
+	"dist":         true,
+	"build":        true,
+	"coverage":     true,
+}
+
+// fileParser extracts the imports of a single file. rel is the file path relative
+// to the project root, using forward slashes.
+type fileParser interface {
+	Parse(rel string, content []byte) ([]outbound.SourceImport, error)
+}
+
+type fsScanner struct{}
+
+// NewFsScanner creates an ImportScanner for Go, TypeScript/JavaScript and PHP
+// sources.
+func NewFsScanner() outbound.ImportScanner {
+	return &fsScanner{}
+}
+
+func (s *fsScanner) Scan(root string) ([]outbound.SourceImport, error) {
+	parsers := map[string]fileParser{
+		".go":  newGoParser(root),
+		".ts":  typescriptParser{},
+		".tsx": typescriptParser{},
+		".js":  typescriptParser{},
+		".jsx": typescriptParser{},
+		".mjs": typescriptParser{},
+		".vue": typescriptParser{},
+		".php": newPHPParser(root),
+	}
+
+	var found []outbound.SourceImport
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && skippedDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		p, ok := parsers[filepath.Ext(path)]
+		if !ok || strings.HasSuffix(path, ".d.ts") {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		imports, err := p.Parse(filepath.ToSlash(rel), content)
+		if err != nil {
+			return err
+		}
+		found = append(found, imports...)
+		return nil
+	})
+	return found, err
+}
+
+// lineOf returns the 1-based line number of offset in content.
+func lineOf(content []byte, offset int) int {
+	return strings.Count(string(content[:offset]), "\n") + 1
+}
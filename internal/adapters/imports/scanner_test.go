@@ -0,0 +1,105 @@
+package imports
+
+import (
+	"grei-cli/internal/ports/outbound"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+}
+
+func findImport(imports []outbound.SourceImport, file, specifier string) *outbound.SourceImport {
+	for i, imp := range imports {
+		if imp.File == file && imp.Specifier == specifier {
+			return &imports[i]
+		}
+	}
+	return nil
+}
+
+func TestScan(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.22\n",
+		"cmd/app/domain/user.go": `package domain
+
+import (
+	"fmt"
+	"example.com/app/cmd/app/adapters/outbound"
+)
+
+var _ = fmt.Sprint
+var _ = outbound.X
+`,
+		"src/domain/user.ts": `import express from 'express';
+import { Repo } from '../infrastructure/adapters/outbound/repo';
+export { Id } from "./id";
+const lazy = await import('./lazy');
+const legacy = require("lodash");
+`,
+		"composer.json": `{"autoload": {"psr-4": {"App\\": "src/"}}}`,
+		"src/Domain/User.php": `<?php
+
+namespace App\Domain;
+
+use App\Infrastructure\Adapters\Outbound\DoctrineRepo;
+use Symfony\Component\HttpFoundation\Request;
+
+class User
+{
+    use SomeTrait;
+}
+`,
+		"node_modules/pkg/index.js": `import x from 'y';`,
+	})
+
+	scanner := NewFsScanner()
+	imports, err := scanner.Scan(tmpDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	tests := []struct {
+		file, specifier, target string
+		line                    int
+	}{
+		{"cmd/app/domain/user.go", "fmt", "", 4},
+		{"cmd/app/domain/user.go", "example.com/app/cmd/app/adapters/outbound", "cmd/app/adapters/outbound", 5},
+		{"src/domain/user.ts", "express", "", 1},
+		{"src/domain/user.ts", "../infrastructure/adapters/outbound/repo", "src/infrastructure/adapters/outbound/repo", 2},
+		{"src/domain/user.ts", "./id", "src/domain/id", 3},
+		{"src/domain/user.ts", "./lazy", "src/domain/lazy", 4},
+		{"src/domain/user.ts", "lodash", "", 5},
+		{"src/Domain/User.php", `App\Infrastructure\Adapters\Outbound\DoctrineRepo`, "src/Infrastructure/Adapters/Outbound/DoctrineRepo", 5},
+		{"src/Domain/User.php", `Symfony\Component\HttpFoundation\Request`, "", 6},
+	}
+	for _, tt := range tests {
+		imp := findImport(imports, tt.file, tt.specifier)
+		if imp == nil {
+			t.Errorf("Expected import %q in %s, got %+v", tt.specifier, tt.file, imports)
+			continue
+		}
+		if imp.Target != tt.target {
+			t.Errorf("Expected %q to resolve to %q, got %q", tt.specifier, tt.target, imp.Target)
+		}
+		if imp.Line != tt.line {
+			t.Errorf("Expected %q on line %d, got %d", tt.specifier, tt.line, imp.Line)
+		}
+	}
+
+	if len(imports) != len(tests) {
+		t.Errorf("Expected %d imports (trait uses and node_modules ignored), got %d: %+v", len(tests), len(imports), imports)
+	}
+}
@@ -0,0 +1,31 @@
+package imports
+
+import (
+	"grei-cli/internal/ports/outbound"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// tsImport matches the module specifier of ES imports and re-exports,
+// side-effect imports, dynamic imports and CommonJS requires.
+var tsImport = regexp.MustCompile(`(?m)(?:^\s*import\s+(?:type\s+)?(?:[\w*{}\s,$]+\s+from\s+)?|^\s*export\s+(?:type\s+)?[\w*{}\s,$]+\s+from\s+|\bimport\s*\(\s*|\brequire\s*\(\s*)['"]([^'"]+)['"]`)
+
+type typescriptParser struct{}
+
+func (typescriptParser) Parse(rel string, content []byte) ([]outbound.SourceImport, error) {
+	var found []outbound.SourceImport
+	for _, m := range tsImport.FindAllSubmatchIndex(content, -1) {
+		specifier := string(content[m[2]:m[3]])
+		imp := outbound.SourceImport{
+			File:      rel,
+			Line:      lineOf(content, m[2]),
+			Specifier: specifier,
+		}
+		if strings.HasPrefix(specifier, "./") || strings.HasPrefix(specifier, "../") {
+			imp.Target = path.Join(path.Dir(rel), specifier)
+		}
+		found = append(found, imp)
+	}
+	return found, nil
+}
@@ -0,0 +1,34 @@
+package shell
+
+import (
+	"context"
+	"grei-cli/internal/ports/outbound"
+	"io"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// waitDelay is how long a command whose context is done may keep its
+// output open, e.g. through a child process of the shell, before it is
+// abandoned.
+const waitDelay = 2 * time.Second
+
+type runner struct{}
+
+func NewRunner() outbound.CommandRunner {
+	return &runner{}
+}
+
+func (r *runner) Run(ctx context.Context, dir, command string, stdout, stderr io.Writer) error {
+	name, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		name, flag = "cmd", "/C"
+	}
+	cmd := exec.CommandContext(ctx, name, flag, command)
+	cmd.Dir = dir
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = waitDelay
+	return cmd.Run()
+}
@@ -0,0 +1,51 @@
+package shell
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test commands use sh syntax")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "marker"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	err := NewRunner().Run(context.Background(), dir, "ls && echo oops >&2", &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("Run() returned an unexpected error: %v", err)
+	}
+	if stdout.String() != "marker\n" || stderr.String() != "oops\n" {
+		t.Errorf("Run() wrote %q and %q, want the listing of dir and oops", stdout.String(), stderr.String())
+	}
+
+	if err := NewRunner().Run(context.Background(), dir, "exit 3", &stdout, &stderr); err == nil {
+		t.Error("Run() should report a failing command")
+	}
+}
+
+func TestRun_Timeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test commands use sh syntax")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := NewRunner().Run(ctx, t.TempDir(), "sleep 10", &bytes.Buffer{}, &bytes.Buffer{})
+	if err == nil {
+		t.Fatal("Run() should stop a command when its context is done")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Run() returned after %s, want it stopped at the timeout", elapsed)
+	}
+}
@@ -0,0 +1,212 @@
+package templatesource
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"grei-cli/internal/ports/outbound"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type archiveSource struct {
+	path     string
+	cacheDir string
+}
+
+// NewArchiveSource creates a TemplateSource for a .tar.gz, .tgz or .zip
+// archive on disk. Archives are extracted once into cacheDir, keyed by their
+// checksum.
+func NewArchiveSource(path, cacheDir string) outbound.TemplateSource {
+	return &archiveSource{path: path, cacheDir: cacheDir}
+}
+
+// IsArchive reports whether path has an archive extension supported by
+// NewArchiveSource.
+func IsArchive(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz") || strings.HasSuffix(lower, ".zip")
+}
+
+func (s *archiveSource) Load(ctx context.Context) (*outbound.TemplateBundle, error) {
+	sum, err := checksum(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	dest := filepath.Join(s.cacheDir, "archives", sum[:16])
+	if _, err := os.Stat(dest); os.IsNotExist(err) {
+		if err := s.extractInto(dest); err != nil {
+			return nil, fmt.Errorf("failed to extract %s: %w", s.path, err)
+		}
+	}
+
+	root, err := findRoot(os.DirFS(dest))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s.path, err)
+	}
+	location, err := filepath.Abs(s.path)
+	if err != nil {
+		return nil, err
+	}
+	return &outbound.TemplateBundle{FS: root, Source: "archive " + s.path, Location: location}, nil
+}
+
+// extractInto extracts the archive into a temporary sibling of dest and
+// renames it into place once complete, so an interrupted extraction never
+// leaves a partial bundle in the cache.
+func (s *archiveSource) extractInto(dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(dest), filepath.Base(dest)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := os.Chmod(tmp, 0755); err != nil {
+		return err
+	}
+	if err := s.extract(tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		// Another process may have extracted the same archive meanwhile.
+		if _, statErr := os.Stat(dest); statErr == nil {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *archiveSource) extract(dest string) error {
+	if strings.HasSuffix(strings.ToLower(s.path), ".zip") {
+		return extractZip(s.path, dest)
+	}
+	return extractTarGz(s.path, dest)
+}
+
+func checksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// safeJoin resolves an archive entry name inside dest, rejecting entries
+// that would be written outside of it.
+func safeJoin(dest, name string) (string, error) {
+	target := filepath.Join(dest, filepath.FromSlash(name))
+	if target != dest && !strings.HasPrefix(target, dest+string(os.PathSeparator)) {
+		return "", fmt.Errorf("archive entry %q escapes the destination directory", name)
+	}
+	return target, nil
+}
+
+// writeFile writes an archive entry with the permission bits recorded in the
+// archive, so that hook scripts stay executable.
+func writeFile(target string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	perm := mode.Perm()
+	if perm == 0 {
+		perm = 0644
+	}
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, r); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func extractTarGz(path, dest string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target, err := safeJoin(dest, header.Name)
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(target, reader, header.FileInfo().Mode()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func extractZip(path, dest string) error {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	for _, entry := range reader.File {
+		target, err := safeJoin(dest, entry.Name)
+		if err != nil {
+			return err
+		}
+		if entry.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+
+		content, err := entry.Open()
+		if err != nil {
+			return err
+		}
+		err = writeFile(target, content, entry.Mode())
+		content.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package templatesource
+
+import (
+	"context"
+	"fmt"
+	"grei-cli/internal/ports/outbound"
+	"os"
+	"path/filepath"
+)
+
+type dirSource struct {
+	path string
+}
+
+// NewDirSource creates a TemplateSource that reads templates straight from a
+// local directory, so template authors see their changes without publishing
+// them.
+func NewDirSource(path string) outbound.TemplateSource {
+	return &dirSource{path: path}
+}
+
+func (s *dirSource) Load(ctx context.Context) (*outbound.TemplateBundle, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", s.path)
+	}
+
+	root, err := findRoot(os.DirFS(s.path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s.path, err)
+	}
+	location, err := filepath.Abs(s.path)
+	if err != nil {
+		return nil, err
+	}
+	return &outbound.TemplateBundle{FS: root, Source: "directory " + s.path, Location: location}, nil
+}
@@ -0,0 +1,26 @@
+package templatesource
+
+import (
+	"context"
+	"grei-cli/internal/core/initializer"
+	"grei-cli/internal/ports/outbound"
+	"io/fs"
+)
+
+type embeddedSource struct {
+	fsys fs.FS
+}
+
+// NewEmbeddedSource creates a TemplateSource for the bundle compiled into the
+// binary.
+func NewEmbeddedSource(fsys fs.FS) outbound.TemplateSource {
+	return &embeddedSource{fsys: fsys}
+}
+
+func (s *embeddedSource) Load(ctx context.Context) (*outbound.TemplateBundle, error) {
+	manifest, err := initializer.ReadManifest(s.fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &outbound.TemplateBundle{FS: s.fsys, Source: "embedded v" + manifest.Version, Location: "embedded"}, nil
+}
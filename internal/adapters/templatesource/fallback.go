@@ -0,0 +1,52 @@
+package templatesource
+
+import (
+	"context"
+	"fmt"
+	"grei-cli/internal/core/initializer"
+	"grei-cli/internal/ports/outbound"
+)
+
+type fallbackSource struct {
+	primary  outbound.TemplateSource
+	fallback outbound.TemplateSource
+}
+
+// NewFallbackSource creates a TemplateSource that uses primary unless it
+// cannot be loaded, requires a newer CLI or is older than fallback.
+func NewFallbackSource(primary, fallback outbound.TemplateSource) outbound.TemplateSource {
+	return &fallbackSource{primary: primary, fallback: fallback}
+}
+
+func (s *fallbackSource) Load(ctx context.Context) (*outbound.TemplateBundle, error) {
+	backup, err := s.fallback.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	backupManifest, err := initializer.ReadManifest(backup.FS)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle, err := s.primary.Load(ctx)
+	if err != nil {
+		return withReason(backup, err.Error()), nil
+	}
+	manifest, err := initializer.ReadManifest(bundle.FS)
+	if err != nil {
+		return withReason(backup, fmt.Sprintf("%s: %v", bundle.Source, err)), nil
+	}
+	if err := manifest.CheckVersion(); err != nil {
+		return withReason(backup, fmt.Sprintf("%s: %v", bundle.Source, err)), nil
+	}
+	if manifest.OlderThan(backupManifest) {
+		return withReason(backup, fmt.Sprintf("%s is outdated (v%s)", bundle.Source, manifest.Version)), nil
+	}
+	return bundle, nil
+}
+
+func withReason(bundle *outbound.TemplateBundle, reason string) *outbound.TemplateBundle {
+	explained := *bundle
+	explained.Source = fmt.Sprintf("%s (%s)", bundle.Source, reason)
+	return &explained
+}
@@ -0,0 +1,62 @@
+package templatesource
+
+import (
+	"context"
+	"fmt"
+	"grei-cli/internal/ports/outbound"
+	"os"
+	"path/filepath"
+)
+
+type gitSource struct {
+	downloader outbound.Downloader
+	url        string
+	ref        string
+	cacheDir   string
+	update     bool
+}
+
+// NewGitSource creates a TemplateSource that syncs the templates/ directory
+// of a git repository into cacheDir. When update is false, or the repository
+// cannot be reached, the copy already in cacheDir is used.
+func NewGitSource(downloader outbound.Downloader, url, ref, cacheDir string, update bool) outbound.TemplateSource {
+	return &gitSource{
+		downloader: downloader,
+		url:        url,
+		ref:        ref,
+		cacheDir:   cacheDir,
+		update:     update,
+	}
+}
+
+func (s *gitSource) Load(ctx context.Context) (*outbound.TemplateBundle, error) {
+	source := fmt.Sprintf("git %s@%s", s.url, s.ref)
+
+	var downloadErr error
+	if s.update {
+		downloadErr = s.downloader.Download(ctx, s.url, s.ref, s.cacheDir)
+	}
+
+	root, err := findRoot(os.DirFS(filepath.Join(s.cacheDir, "templates")))
+	if err != nil {
+		if downloadErr != nil {
+			return nil, fmt.Errorf("could not download %s: %w", source, downloadErr)
+		}
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	if downloadErr != nil {
+		source += " (cached copy, update failed)"
+	}
+
+	// A cache populated by hand has no revision; the templates are still
+	// usable, just not pinned to a commit.
+	commit, _ := s.downloader.Revision(ctx, s.cacheDir)
+	return &outbound.TemplateBundle{
+		FS:       root,
+		Source:   source,
+		Location: s.url,
+		Ref:      s.ref,
+		Commit:   commit,
+		Stale:    downloadErr != nil,
+	}, nil
+}
@@ -0,0 +1,44 @@
+package templatesource
+
+import (
+	"errors"
+	"io/fs"
+)
+
+// ErrNoManifest is returned when a source holds no template bundle.
+var ErrNoManifest = errors.New("no manifest.json found")
+
+// findRoot returns the directory of fsys holding manifest.json. Besides the
+// root itself it accepts a templates/ directory, so a clone of the templates
+// repository works as is, and a single top-level directory, as produced by
+// most archive tools.
+func findRoot(fsys fs.FS) (fs.FS, error) {
+	for depth := 0; depth < 3; depth++ {
+		if _, err := fs.Stat(fsys, "manifest.json"); err == nil {
+			return fsys, nil
+		}
+
+		if info, err := fs.Stat(fsys, "templates"); err == nil && info.IsDir() {
+			sub, err := fs.Sub(fsys, "templates")
+			if err != nil {
+				return nil, err
+			}
+			fsys = sub
+			continue
+		}
+
+		entries, err := fs.ReadDir(fsys, ".")
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) != 1 || !entries[0].IsDir() {
+			break
+		}
+		sub, err := fs.Sub(fsys, entries[0].Name())
+		if err != nil {
+			return nil, err
+		}
+		fsys = sub
+	}
+	return nil, ErrNoManifest
+}
@@ -0,0 +1,213 @@
+package templatesource
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"grei-cli/internal/ports/outbound"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const testManifest = `{"version": "1.0.0", "minVersion": "0.1.0"}`
+
+func writeTarGz(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		mode := int64(0644)
+		if strings.HasSuffix(name, ".sh") {
+			mode = 0755
+		}
+		header := &tar.Header{Name: name, Mode: mode, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeZip(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	zw := zip.NewWriter(file)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertReadme(t *testing.T, bundle *outbound.TemplateBundle) {
+	t.Helper()
+	content, err := fs.ReadFile(bundle.FS, "skeletons/generic/README.md.tmpl")
+	if err != nil {
+		t.Fatalf("Expected the bundle to be rooted at manifest.json: %v", err)
+	}
+	if string(content) != "# readme" {
+		t.Errorf("Unexpected template content %q", content)
+	}
+}
+
+func TestDirSource(t *testing.T) {
+	// A clone of the templates repository holds the bundle under templates/.
+	repo := t.TempDir()
+	for name, content := range map[string]string{
+		"templates/manifest.json":                    testManifest,
+		"templates/skeletons/generic/README.md.tmpl": "# readme",
+	} {
+		path := filepath.Join(repo, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bundle, err := NewDirSource(repo).Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load() returned an unexpected error: %v", err)
+	}
+	assertReadme(t, bundle)
+
+	if _, err := NewDirSource(t.TempDir()).Load(context.Background()); !errors.Is(err, ErrNoManifest) {
+		t.Errorf("Expected ErrNoManifest for an empty directory, got %v", err)
+	}
+}
+
+func TestArchiveSource(t *testing.T) {
+	files := map[string]string{
+		"bundle-1.0.0/manifest.json":                    testManifest,
+		"bundle-1.0.0/skeletons/generic/README.md.tmpl": "# readme",
+		"bundle-1.0.0/hooks/pre-commit.sh":              "#!/bin/sh\n",
+	}
+
+	for _, name := range []string{"bundle.tar.gz", "bundle.zip"} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, name)
+			if strings.HasSuffix(name, ".zip") {
+				writeZip(t, path, files)
+			} else {
+				writeTarGz(t, path, files)
+			}
+
+			if !IsArchive(path) {
+				t.Fatalf("Expected %s to be recognized as an archive", name)
+			}
+			source := NewArchiveSource(path, filepath.Join(dir, "cache"))
+			bundle, err := source.Load(context.Background())
+			if err != nil {
+				t.Fatalf("Load() returned an unexpected error: %v", err)
+			}
+			assertReadme(t, bundle)
+
+			if strings.HasSuffix(name, ".tar.gz") {
+				info, err := fs.Stat(bundle.FS, "hooks/pre-commit.sh")
+				if err != nil {
+					t.Fatalf("Expected the hook script to be extracted: %v", err)
+				}
+				if info.Mode().Perm()&0100 == 0 {
+					t.Errorf("Expected the hook script to stay executable, got %v", info.Mode())
+				}
+			}
+
+			// The extracted copy is reused.
+			if _, err := source.Load(context.Background()); err != nil {
+				t.Errorf("Second Load() returned an unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestArchiveSource_RejectsEscapingEntries(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "evil.zip")
+	writeZip(t, path, map[string]string{"../../evil.txt": "boom", "manifest.json": testManifest})
+
+	if _, err := NewArchiveSource(path, filepath.Join(dir, "cache")).Load(context.Background()); err == nil {
+		t.Error("Expected an error for an entry outside the destination directory")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "evil.txt")); !os.IsNotExist(err) {
+		t.Error("Escaping entry was written outside the cache")
+	}
+	entries, _ := os.ReadDir(filepath.Join(dir, "cache", "archives"))
+	if len(entries) != 0 {
+		t.Errorf("Expected no partial extraction in the cache, found %d entries", len(entries))
+	}
+}
+
+type stubSource struct {
+	bundle *outbound.TemplateBundle
+	err    error
+}
+
+func (s *stubSource) Load(ctx context.Context) (*outbound.TemplateBundle, error) {
+	return s.bundle, s.err
+}
+
+func TestFallbackSource(t *testing.T) {
+	bundleWith := func(source, manifest string) *stubSource {
+		return &stubSource{bundle: &outbound.TemplateBundle{
+			FS:     fstest.MapFS{"manifest.json": {Data: []byte(manifest)}},
+			Source: source,
+		}}
+	}
+	backup := bundleWith("embedded", testManifest)
+
+	tests := []struct {
+		name    string
+		primary *stubSource
+		want    string
+	}{
+		{"primary is current", bundleWith("cache", `{"version": "1.0.0", "minVersion": "0.1.0"}`), "cache"},
+		{"primary is newer", bundleWith("cache", `{"version": "1.2.0", "minVersion": "0.1.0"}`), "cache"},
+		{"primary is outdated", bundleWith("cache", `{"version": "0.9.0", "minVersion": "0.1.0"}`), "embedded"},
+		{"primary needs a newer CLI", bundleWith("cache", `{"version": "2.0.0", "minVersion": "9.0.0"}`), "embedded"},
+		{"primary fails", &stubSource{err: errors.New("offline")}, "embedded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundle, err := NewFallbackSource(tt.primary, backup).Load(context.Background())
+			if err != nil {
+				t.Fatalf("Load() returned an unexpected error: %v", err)
+			}
+			if !strings.HasPrefix(bundle.Source, tt.want) {
+				t.Errorf("Expected templates from %s, got %q", tt.want, bundle.Source)
+			}
+		})
+	}
+}
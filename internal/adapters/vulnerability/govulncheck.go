@@ -0,0 +1,109 @@
+package vulnerability
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"grei-cli/internal/ports/outbound"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// GovulncheckScanner wraps `govulncheck -json`, which only reports advisories
+// whose vulnerable symbols are actually reachable from the project.
+type GovulncheckScanner struct {
+	sysChecker outbound.SystemChecker
+}
+
+func NewGovulncheckScanner(sysChecker outbound.SystemChecker) outbound.VulnerabilityScanner {
+	return &GovulncheckScanner{
+		sysChecker: sysChecker,
+	}
+}
+
+// govulncheckMessage is one of the JSON objects streamed by govulncheck.
+type govulncheckMessage struct {
+	OSV *struct {
+		ID      string   `json:"id"`
+		Aliases []string `json:"aliases"`
+		Summary string   `json:"summary"`
+	} `json:"osv"`
+	Finding *struct {
+		OSV          string `json:"osv"`
+		FixedVersion string `json:"fixed_version"`
+		Trace        []struct {
+			Module   string `json:"module"`
+			Version  string `json:"version"`
+			Function string `json:"function"`
+		} `json:"trace"`
+	} `json:"finding"`
+}
+
+func (s *GovulncheckScanner) Scan(path string) ([]outbound.Vulnerability, error) {
+	if _, err := os.Stat(filepath.Join(path, "go.mod")); err != nil {
+		return nil, fmt.Errorf("%w: no go.mod found", outbound.ErrScannerUnavailable)
+	}
+	if !s.sysChecker.CommandExists("govulncheck") {
+		return nil, fmt.Errorf("%w: govulncheck not found", outbound.ErrScannerUnavailable)
+	}
+
+	cmd := exec.Command("govulncheck", "-json", "./...")
+	cmd.Dir = path
+	output, err := cmd.Output()
+	if err != nil && len(output) == 0 {
+		return nil, fmt.Errorf("govulncheck failed: %w", err)
+	}
+	return parseGovulncheck(strings.NewReader(string(output)))
+}
+
+func parseGovulncheck(r io.Reader) ([]outbound.Vulnerability, error) {
+	type advisory struct {
+		aliases []string
+		summary string
+	}
+	advisories := make(map[string]advisory)
+	seen := make(map[string]bool)
+	var found []outbound.Vulnerability
+
+	decoder := json.NewDecoder(r)
+	for {
+		var msg govulncheckMessage
+		if err := decoder.Decode(&msg); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("could not parse govulncheck output: %w", err)
+		}
+
+		if msg.OSV != nil {
+			advisories[msg.OSV.ID] = advisory{msg.OSV.Aliases, msg.OSV.Summary}
+		}
+		// Only findings with a function in their trace are reachable.
+		if msg.Finding == nil || len(msg.Finding.Trace) == 0 || msg.Finding.Trace[0].Function == "" {
+			continue
+		}
+		frame := msg.Finding.Trace[0]
+		key := msg.Finding.OSV + "@" + frame.Module
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		found = append(found, outbound.Vulnerability{
+			ID:        msg.Finding.OSV,
+			Package:   frame.Module,
+			Version:   frame.Version,
+			Ecosystem: "Go",
+			FixedIn:   msg.Finding.FixedVersion,
+		})
+	}
+
+	// Advisories are usually streamed before their findings, but fill in
+	// the details at the end in case they are not.
+	for i := range found {
+		found[i].Aliases = advisories[found[i].ID].aliases
+		found[i].Summary = advisories[found[i].ID].summary
+	}
+	return found, nil
+}
@@ -0,0 +1,30 @@
+package vulnerability
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseGovulncheck(t *testing.T) {
+	output := `{"config": {"scanner_name": "govulncheck"}}
+{"osv": {"id": "GO-2024-0001", "aliases": ["CVE-2024-0001"], "summary": "Request smuggling in net/http"}}
+{"finding": {"osv": "GO-2024-0001", "fixed_version": "v0.23.0", "trace": [{"module": "golang.org/x/net", "version": "v0.20.0"}]}}
+{"finding": {"osv": "GO-2024-0001", "fixed_version": "v0.23.0", "trace": [{"module": "golang.org/x/net", "version": "v0.20.0", "function": "ServeConn"}]}}
+{"finding": {"osv": "GO-2024-0002", "trace": [{"module": "golang.org/x/text", "version": "v0.3.0"}]}}
+`
+
+	vulns, err := parseGovulncheck(strings.NewReader(output))
+	if err != nil {
+		t.Fatalf("parseGovulncheck() returned an unexpected error: %v", err)
+	}
+
+	if len(vulns) != 1 {
+		t.Fatalf("Expected only the reachable finding, got %+v", vulns)
+	}
+	if vulns[0].ID != "GO-2024-0001" || vulns[0].Package != "golang.org/x/net" || vulns[0].FixedIn != "v0.23.0" {
+		t.Errorf("Unexpected finding: %+v", vulns[0])
+	}
+	if vulns[0].Summary != "Request smuggling in net/http" {
+		t.Errorf("Expected the advisory summary, got %q", vulns[0].Summary)
+	}
+}
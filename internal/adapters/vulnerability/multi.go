@@ -0,0 +1,68 @@
+package vulnerability
+
+import (
+	"errors"
+	"grei-cli/internal/ports/outbound"
+)
+
+// MultiScanner runs several scanners and merges their findings. Scanners that
+// are unavailable are skipped; the scan itself is only unavailable when all
+// of them are.
+type MultiScanner struct {
+	scanners []outbound.VulnerabilityScanner
+}
+
+func NewMultiScanner(scanners ...outbound.VulnerabilityScanner) outbound.VulnerabilityScanner {
+	return &MultiScanner{
+		scanners: scanners,
+	}
+}
+
+func (s *MultiScanner) Scan(path string) ([]outbound.Vulnerability, error) {
+	var found []outbound.Vulnerability
+	seen := make(map[string]bool)
+	available := false
+	var lastErr error
+
+	for _, scanner := range s.scanners {
+		vulns, err := scanner.Scan(path)
+		if errors.Is(err, outbound.ErrScannerUnavailable) {
+			lastErr = err
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		available = true
+
+		for _, v := range vulns {
+			if isDuplicate(seen, v) {
+				continue
+			}
+			found = append(found, v)
+		}
+	}
+
+	if !available {
+		if lastErr == nil {
+			lastErr = outbound.ErrScannerUnavailable
+		}
+		return nil, lastErr
+	}
+	return found, nil
+}
+
+// isDuplicate reports whether another scanner already found the advisory,
+// possibly under one of its aliases, for the same package.
+func isDuplicate(seen map[string]bool, v outbound.Vulnerability) bool {
+	ids := append([]string{v.ID}, v.Aliases...)
+	for _, id := range ids {
+		if seen[id+"@"+v.Package] {
+			return true
+		}
+	}
+	for _, id := range ids {
+		seen[id+"@"+v.Package] = true
+	}
+	return false
+}
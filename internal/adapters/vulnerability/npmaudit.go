@@ -0,0 +1,133 @@
+package vulnerability
+
+import (
+	"encoding/json"
+	"fmt"
+	"grei-cli/internal/ports/outbound"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+// NpmAuditScanner wraps `npm audit --json`, which queries the npm registry
+// and therefore needs network access.
+type NpmAuditScanner struct {
+	sysChecker outbound.SystemChecker
+}
+
+func NewNpmAuditScanner(sysChecker outbound.SystemChecker) outbound.VulnerabilityScanner {
+	return &NpmAuditScanner{
+		sysChecker: sysChecker,
+	}
+}
+
+// npmAuditReport is the subset of the npm 7+ audit report format we use.
+type npmAuditReport struct {
+	Vulnerabilities map[string]struct {
+		Name  string            `json:"name"`
+		Via   []json.RawMessage `json:"via"`
+		Nodes []string          `json:"nodes"`
+	} `json:"vulnerabilities"`
+}
+
+// npmLockfile is the subset of package-lock.json that holds the installed
+// version of each node named in the audit report.
+type npmLockfile struct {
+	Packages map[string]struct {
+		Version string `json:"version"`
+	} `json:"packages"`
+}
+
+type npmAuditAdvisory struct {
+	Name     string `json:"name"`
+	Title    string `json:"title"`
+	URL      string `json:"url"`
+	Severity string `json:"severity"`
+	Range    string `json:"range"`
+}
+
+func (s *NpmAuditScanner) Scan(dir string) ([]outbound.Vulnerability, error) {
+	lock, err := os.ReadFile(filepath.Join(dir, "package-lock.json"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: no package-lock.json found", outbound.ErrScannerUnavailable)
+	}
+	if !s.sysChecker.CommandExists("npm") {
+		return nil, fmt.Errorf("%w: npm not found", outbound.ErrScannerUnavailable)
+	}
+
+	cmd := exec.Command("npm", "audit", "--json")
+	cmd.Dir = dir
+	// npm audit exits with a non-zero status when it finds vulnerabilities.
+	output, err := cmd.Output()
+	if err != nil && len(output) == 0 {
+		return nil, fmt.Errorf("npm audit failed: %w", err)
+	}
+	return parseNpmAudit(output, lock)
+}
+
+// parseNpmAudit reads the findings of an audit report. The report only
+// gives the vulnerable range of each advisory, so the installed versions
+// come from the lockfile.
+func parseNpmAudit(output, lock []byte) ([]outbound.Vulnerability, error) {
+	var report npmAuditReport
+	if err := json.Unmarshal(output, &report); err != nil {
+		return nil, fmt.Errorf("could not parse npm audit output: %w", err)
+	}
+	var lockfile npmLockfile
+	if err := json.Unmarshal(lock, &lockfile); err != nil {
+		return nil, fmt.Errorf("could not parse package-lock.json: %w", err)
+	}
+
+	names := make([]string, 0, len(report.Vulnerabilities))
+	for name := range report.Vulnerabilities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var found []outbound.Vulnerability
+	for _, name := range names {
+		entry := report.Vulnerabilities[name]
+		versions := installedVersions(lockfile, entry.Nodes)
+		for _, raw := range entry.Via {
+			// Entries that only name another vulnerable package are strings.
+			var advisory npmAuditAdvisory
+			if err := json.Unmarshal(raw, &advisory); err != nil || advisory.URL == "" {
+				continue
+			}
+			for _, version := range versions {
+				found = append(found, outbound.Vulnerability{
+					ID:            path.Base(advisory.URL),
+					Package:       advisory.Name,
+					Version:       version,
+					Ecosystem:     "npm",
+					Summary:       advisory.Title,
+					Severity:      advisory.Severity,
+					AffectedRange: advisory.Range,
+				})
+			}
+		}
+	}
+	return found, nil
+}
+
+// installedVersions returns the distinct versions the lockfile records for
+// nodes, the install paths of a package, e.g. node_modules/qs. It returns a
+// single empty version when the lockfile names none of them.
+func installedVersions(lockfile npmLockfile, nodes []string) []string {
+	var versions []string
+	seen := make(map[string]bool)
+	for _, node := range nodes {
+		version := lockfile.Packages[node].Version
+		if version != "" && !seen[version] {
+			seen[version] = true
+			versions = append(versions, version)
+		}
+	}
+	if len(versions) == 0 {
+		return []string{""}
+	}
+	sort.Strings(versions)
+	return versions
+}
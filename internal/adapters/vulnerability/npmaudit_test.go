@@ -0,0 +1,49 @@
+package vulnerability
+
+import "testing"
+
+func TestParseNpmAudit(t *testing.T) {
+	output := `{
+  "auditReportVersion": 2,
+  "vulnerabilities": {
+    "express": {"name": "express", "severity": "high", "via": ["qs"]},
+    "qs": {"name": "qs", "severity": "high", "nodes": ["node_modules/qs"], "via": [{
+      "source": 1090,
+      "name": "qs",
+      "title": "qs vulnerable to Prototype Pollution",
+      "url": "https://github.com/advisories/GHSA-hrpp-h998-j3pp",
+      "severity": "high",
+      "range": "<6.10.3"
+    }]}
+  }
+}`
+
+	lock := `{
+  "lockfileVersion": 3,
+  "packages": {
+    "": {"name": "web"},
+    "node_modules/qs": {"version": "6.5.2"}
+  }
+}`
+
+	vulns, err := parseNpmAudit([]byte(output), []byte(lock))
+	if err != nil {
+		t.Fatalf("parseNpmAudit() returned an unexpected error: %v", err)
+	}
+
+	if len(vulns) != 1 {
+		t.Fatalf("Expected 1 vulnerability, got %+v", vulns)
+	}
+	if vulns[0].ID != "GHSA-hrpp-h998-j3pp" || vulns[0].Package != "qs" || vulns[0].Severity != "high" {
+		t.Errorf("Unexpected finding: %+v", vulns[0])
+	}
+	// The report only has the vulnerable range; the lockfile has the
+	// installed version.
+	if vulns[0].Version != "6.5.2" || vulns[0].AffectedRange != "<6.10.3" {
+		t.Errorf("Expected qs@6.5.2 affected by <6.10.3, got %+v", vulns[0])
+	}
+
+	if _, err := parseNpmAudit([]byte("not json"), []byte(lock)); err == nil {
+		t.Error("parseNpmAudit() should have returned an error, but it did not")
+	}
+}
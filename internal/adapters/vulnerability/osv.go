@@ -0,0 +1,285 @@
+package vulnerability
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"grei-cli/internal/ports/outbound"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+)
+
+// osvEcosystems maps the ecosystems reported by the dependency reader to the
+// names used by the OSV schema.
+var osvEcosystems = map[string]string{
+	"npm":      "npm",
+	"composer": "Packagist",
+	"go":       "Go",
+	"pypi":     "PyPI",
+}
+
+// osvAdvisory is the subset of the OSV schema (https://ossf.github.io/osv-schema/)
+// needed to match advisories against package versions.
+type osvAdvisory struct {
+	ID       string   `json:"id"`
+	Aliases  []string `json:"aliases"`
+	Summary  string   `json:"summary"`
+	Severity []struct {
+		Type  string `json:"type"`
+		Score string `json:"score"`
+	} `json:"severity"`
+	Affected []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		Ranges []struct {
+			Type   string     `json:"type"`
+			Events []osvEvent `json:"events"`
+		} `json:"ranges"`
+		Versions         []string `json:"versions"`
+		DatabaseSpecific struct {
+			Severity string `json:"severity"`
+		} `json:"database_specific"`
+	} `json:"affected"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+}
+
+type osvEvent struct {
+	Introduced   string `json:"introduced"`
+	Fixed        string `json:"fixed"`
+	LastAffected string `json:"last_affected"`
+}
+
+// OSVScanner matches the locked dependencies of a project against an OSV
+// advisory database shipped with the templates, so it works offline.
+type OSVScanner struct {
+	db               fs.FS
+	dependencyReader outbound.DependencyReader
+}
+
+// NewOSVScanner creates a scanner that reads every *.json advisory in db,
+// usually the advisories directory of the templates.
+func NewOSVScanner(db fs.FS, dependencyReader outbound.DependencyReader) outbound.VulnerabilityScanner {
+	return &OSVScanner{
+		db:               db,
+		dependencyReader: dependencyReader,
+	}
+}
+
+func (s *OSVScanner) Scan(path string) ([]outbound.Vulnerability, error) {
+	advisories, err := s.loadAdvisories()
+	if err != nil {
+		return nil, err
+	}
+
+	manifests, err := s.dependencyReader.Read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Index advisories by ecosystem and package for the lookups below.
+	index := make(map[string][]*osvAdvisory)
+	for _, advisory := range advisories {
+		for _, affected := range advisory.Affected {
+			key := affected.Package.Ecosystem + ":" + affected.Package.Name
+			index[key] = append(index[key], advisory)
+		}
+	}
+
+	var found []outbound.Vulnerability
+	for _, pkg := range installedPackages(manifests) {
+		for _, advisory := range index[pkg.ecosystem+":"+pkg.name] {
+			if v, ok := match(advisory, pkg); ok {
+				found = append(found, v)
+			}
+		}
+	}
+	return found, nil
+}
+
+func (s *OSVScanner) loadAdvisories() ([]*osvAdvisory, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("%w: no advisory database configured", outbound.ErrScannerUnavailable)
+	}
+
+	var advisories []*osvAdvisory
+	err := fs.WalkDir(s.db, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(name) != ".json" {
+			return err
+		}
+		data, err := fs.ReadFile(s.db, name)
+		if err != nil {
+			return err
+		}
+		var advisory osvAdvisory
+		if err := json.Unmarshal(data, &advisory); err != nil {
+			return fmt.Errorf("could not parse advisory %s: %w", name, err)
+		}
+		advisories = append(advisories, &advisory)
+		return nil
+	})
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil, fmt.Errorf("%w: advisory database not found", outbound.ErrScannerUnavailable)
+	case err != nil:
+		return nil, err
+	case len(advisories) == 0:
+		return nil, fmt.Errorf("%w: advisory database is empty", outbound.ErrScannerUnavailable)
+	}
+	return advisories, nil
+}
+
+type installedPackage struct {
+	ecosystem string
+	name      string
+	version   string
+}
+
+// installedPackages returns the package versions a project actually installs:
+// everything in the lockfile when there is one, and the pinned manifest
+// versions otherwise. go.sum lists versions that are no longer selected, so
+// Go modules always use the versions required by go.mod.
+func installedPackages(manifests []outbound.DependencyManifest) []installedPackage {
+	seen := make(map[installedPackage]bool)
+	var packages []installedPackage
+	add := func(p installedPackage) {
+		if p.ecosystem != "" && p.version != "" && !seen[p] {
+			seen[p] = true
+			packages = append(packages, p)
+		}
+	}
+
+	for _, manifest := range manifests {
+		ecosystem := osvEcosystems[manifest.Ecosystem]
+		if manifest.Lockfile != nil && manifest.Lockfile.Packages != nil && manifest.Ecosystem != "go" {
+			names := make([]string, 0, len(manifest.Lockfile.Packages))
+			for name := range manifest.Lockfile.Packages {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				for _, version := range manifest.Lockfile.Packages[name] {
+					add(installedPackage{ecosystem, name, version})
+				}
+			}
+			continue
+		}
+		for _, dep := range manifest.Dependencies {
+			add(installedPackage{ecosystem, dep.Name, strings.TrimLeft(dep.Version, "=")})
+		}
+	}
+	return packages
+}
+
+// match reports whether the advisory affects pkg, following the OSV range
+// evaluation rules for SEMVER and ECOSYSTEM ranges.
+func match(advisory *osvAdvisory, pkg installedPackage) (outbound.Vulnerability, bool) {
+	for _, affected := range advisory.Affected {
+		if affected.Package.Ecosystem != pkg.ecosystem || affected.Package.Name != pkg.name {
+			continue
+		}
+
+		vulnerable := false
+		for _, v := range affected.Versions {
+			if v == pkg.version {
+				vulnerable = true
+			}
+		}
+
+		fixedIn := ""
+		version, err := semver.NewVersion(pkg.version)
+		for _, r := range affected.Ranges {
+			if vulnerable || err != nil || r.Type == "GIT" {
+				break
+			}
+			if inRange(version, r.Events) {
+				vulnerable = true
+				fixedIn = fixedVersion(version, r.Events)
+			}
+		}
+		if !vulnerable {
+			continue
+		}
+
+		severity := affected.DatabaseSpecific.Severity
+		if severity == "" {
+			severity = advisory.DatabaseSpecific.Severity
+		}
+		if severity == "" && len(advisory.Severity) > 0 {
+			severity = advisory.Severity[0].Score
+		}
+		return outbound.Vulnerability{
+			ID:        advisory.ID,
+			Aliases:   advisory.Aliases,
+			Package:   pkg.name,
+			Version:   pkg.version,
+			Ecosystem: pkg.ecosystem,
+			Summary:   advisory.Summary,
+			Severity:  severity,
+			FixedIn:   fixedIn,
+		}, true
+	}
+	return outbound.Vulnerability{}, false
+}
+
+// inRange replays the range events in version order: an "introduced" event
+// at or below the version marks it affected, a "fixed" event at or below it
+// (or a "last_affected" event below it) marks it unaffected again.
+func inRange(version *semver.Version, events []osvEvent) bool {
+	type point struct {
+		at    *semver.Version
+		event osvEvent
+	}
+	var points []point
+	for _, e := range events {
+		raw := e.Introduced + e.Fixed + e.LastAffected
+		if raw == "0" {
+			raw = "0.0.0"
+		}
+		at, err := semver.NewVersion(raw)
+		if err != nil {
+			continue
+		}
+		points = append(points, point{at, e})
+	}
+	sort.SliceStable(points, func(i, j int) bool { return points[i].at.LessThan(points[j].at) })
+
+	affected := false
+	for _, p := range points {
+		switch {
+		case p.event.Introduced != "" && !version.LessThan(p.at):
+			affected = true
+		case p.event.Fixed != "" && !version.LessThan(p.at):
+			affected = false
+		case p.event.LastAffected != "" && version.GreaterThan(p.at):
+			affected = false
+		}
+	}
+	return affected
+}
+
+// fixedVersion returns the first fix released after version, if any.
+func fixedVersion(version *semver.Version, events []osvEvent) string {
+	var best *semver.Version
+	raw := ""
+	for _, e := range events {
+		if e.Fixed == "" {
+			continue
+		}
+		fixed, err := semver.NewVersion(e.Fixed)
+		if err != nil || !fixed.GreaterThan(version) {
+			continue
+		}
+		if best == nil || fixed.LessThan(best) {
+			best, raw = fixed, e.Fixed
+		}
+	}
+	return raw
+}
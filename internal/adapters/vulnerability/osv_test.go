@@ -0,0 +1,135 @@
+package vulnerability
+
+import (
+	"errors"
+	"grei-cli/internal/ports/outbound"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+type mockDependencyReader struct {
+	manifests []outbound.DependencyManifest
+}
+
+func (m *mockDependencyReader) Read(path string) ([]outbound.DependencyManifest, error) {
+	return m.manifests, nil
+}
+
+const lodashAdvisory = `{
+  "id": "GHSA-test-lodash",
+  "aliases": ["CVE-0000-0001"],
+  "summary": "Prototype pollution in lodash",
+  "affected": [{
+    "package": {"ecosystem": "npm", "name": "lodash"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "4.17.21"}]}],
+    "database_specific": {"severity": "HIGH"}
+  }]
+}`
+
+const cobraAdvisory = `{
+  "id": "GO-test-cobra",
+  "affected": [{
+    "package": {"ecosystem": "Go", "name": "github.com/spf13/cobra"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "1.2.0"}, {"last_affected": "1.5.0"}]}]
+  }]
+}`
+
+func TestOSVScanner_Scan(t *testing.T) {
+	db := fstest.MapFS{
+		"npm/GHSA-test-lodash.json": {Data: []byte(lodashAdvisory)},
+		"go/GO-test-cobra.json":     {Data: []byte(cobraAdvisory)},
+	}
+
+	reader := &mockDependencyReader{manifests: []outbound.DependencyManifest{
+		{
+			Path:         "package.json",
+			Ecosystem:    "npm",
+			Dependencies: []outbound.Dependency{{Name: "express", Version: "4.19.2", Ecosystem: "npm"}},
+			Lockfile: &outbound.Lockfile{
+				Path: "package-lock.json",
+				Packages: map[string][]string{
+					"express": {"4.19.2"},
+					"lodash":  {"4.17.20", "4.17.21"},
+				},
+			},
+		},
+		{
+			Path:      "go.mod",
+			Ecosystem: "go",
+			Dependencies: []outbound.Dependency{
+				{Name: "github.com/spf13/cobra", Version: "v1.5.0", Ecosystem: "go"},
+			},
+			Lockfile: &outbound.Lockfile{
+				Path:     "go.sum",
+				Packages: map[string][]string{"github.com/spf13/cobra": {"v1.1.0", "v1.5.0"}},
+			},
+		},
+	}}
+
+	vulns, err := NewOSVScanner(db, reader).Scan(t.TempDir())
+	if err != nil {
+		t.Fatalf("Scan() returned an unexpected error: %v", err)
+	}
+
+	if len(vulns) != 2 {
+		t.Fatalf("Expected 2 vulnerabilities, got %d: %+v", len(vulns), vulns)
+	}
+	if vulns[0].ID != "GHSA-test-lodash" || vulns[0].Version != "4.17.20" || vulns[0].FixedIn != "4.17.21" || vulns[0].Severity != "HIGH" {
+		t.Errorf("Unexpected lodash finding: %+v", vulns[0])
+	}
+	if vulns[1].ID != "GO-test-cobra" || vulns[1].Version != "v1.5.0" {
+		t.Errorf("Unexpected cobra finding: %+v", vulns[1])
+	}
+}
+
+func TestOSVScanner_MissingDatabase(t *testing.T) {
+	db, err := fs.Sub(fstest.MapFS{}, "advisories")
+	if err != nil {
+		t.Fatalf("Failed to open advisory database: %v", err)
+	}
+	scanner := NewOSVScanner(db, &mockDependencyReader{})
+
+	_, err = scanner.Scan(t.TempDir())
+	if !errors.Is(err, outbound.ErrScannerUnavailable) {
+		t.Errorf("Expected ErrScannerUnavailable, got %v", err)
+	}
+}
+
+func TestOSVScanner_EmptyDatabase(t *testing.T) {
+	db := fstest.MapFS{"README.md": {Data: []byte("# Advisory Database")}}
+	scanner := NewOSVScanner(db, &mockDependencyReader{})
+
+	_, err := scanner.Scan(t.TempDir())
+	if !errors.Is(err, outbound.ErrScannerUnavailable) {
+		t.Errorf("Expected ErrScannerUnavailable, got %v", err)
+	}
+}
+
+type staticScanner struct {
+	vulns []outbound.Vulnerability
+	err   error
+}
+
+func (s *staticScanner) Scan(path string) ([]outbound.Vulnerability, error) {
+	return s.vulns, s.err
+}
+
+func TestMultiScanner_Scan(t *testing.T) {
+	osv := &staticScanner{vulns: []outbound.Vulnerability{{ID: "GHSA-1", Aliases: []string{"CVE-1"}, Package: "lodash"}}}
+	audit := &staticScanner{vulns: []outbound.Vulnerability{{ID: "CVE-1", Package: "lodash"}, {ID: "GHSA-2", Package: "qs"}}}
+	missing := &staticScanner{err: outbound.ErrScannerUnavailable}
+
+	vulns, err := NewMultiScanner(osv, missing, audit).Scan(".")
+	if err != nil {
+		t.Fatalf("Scan() returned an unexpected error: %v", err)
+	}
+	if len(vulns) != 2 {
+		t.Errorf("Expected duplicates to be merged into 2 findings, got %+v", vulns)
+	}
+
+	_, err = NewMultiScanner(missing).Scan(".")
+	if !errors.Is(err, outbound.ErrScannerUnavailable) {
+		t.Errorf("Expected ErrScannerUnavailable when no scanner can run, got %v", err)
+	}
+}
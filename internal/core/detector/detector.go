@@ -0,0 +1,261 @@
+// Package detector infers the stack of an existing project from its
+// manifests, lockfiles and configuration files, so that grei can work on
+// projects it did not generate.
+package detector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"grei-cli/internal/core/recipe"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// MinConfidence is the confidence a finding needs to be used as a recipe
+// value.
+const MinConfidence = 0.5
+
+// Finding is a value inferred for one aspect of a project.
+type Finding struct {
+	Value string `json:"value"`
+	// Confidence is between 0 and 1. Independent signals for the same value
+	// add up: two signals of 0.5 give 0.75.
+	Confidence float64 `json:"confidence"`
+	// Evidence lists what the value was inferred from, e.g.
+	// "package.json: express".
+	Evidence []string `json:"evidence"`
+}
+
+// Detection holds what was inferred about a project. Every aspect lists its
+// candidates, the most likely first; values use the names of the stack
+// options, e.g. "GitHub Actions" or "PostgreSQL".
+type Detection struct {
+	// Stack is the skeleton of the templates the project matches, or nil.
+	Stack          *Finding  `json:"stack"`
+	Language       []Finding `json:"language"`
+	Framework      []Finding `json:"framework"`
+	Tests          []Finding `json:"tests"`
+	Linter         []Finding `json:"linter"`
+	PackageManager []Finding `json:"packageManager"`
+	CI             []Finding `json:"ci"`
+	Persistence    []Finding `json:"persistence"`
+}
+
+// Detect inspects the project and returns what it could infer.
+func Detect(fsys fs.FS) (*Detection, error) {
+	p := &project{fsys: fsys}
+	stack, err := detectStack(p)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Detection{Stack: stack}
+	for _, aspect := range []struct {
+		findings *[]Finding
+		signals  func(*project) ([]signal, error)
+	}{
+		{&d.Language, languageSignals},
+		{&d.Framework, frameworkSignals},
+		{&d.Tests, testSignals},
+		{&d.Linter, linterSignals},
+		{&d.PackageManager, packageManagerSignals},
+		{&d.CI, ciSignals},
+		{&d.Persistence, persistenceSignals},
+	} {
+		signals, err := aspect.signals(p)
+		if err != nil {
+			return nil, err
+		}
+		*aspect.findings = combine(signals)
+	}
+	return d, nil
+}
+
+// DetectStack returns the stack of the templates the project matches, or
+// nil when no known stack is recognised.
+func DetectStack(fsys fs.FS) (*Finding, error) {
+	return detectStack(&project{fsys: fsys})
+}
+
+// Best returns the most likely value among findings, or "" when none is
+// confident enough.
+func Best(findings []Finding) string {
+	if len(findings) == 0 || findings[0].Confidence < MinConfidence {
+		return ""
+	}
+	return findings[0].Value
+}
+
+// Recipe returns the recipe of the detected project, named name, with the
+// stack values verify checks. Aspects that could not be inferred are left
+// empty.
+func (d *Detection) Recipe(name string) *recipe.Recipe {
+	r := &recipe.Recipe{APIVersion: recipe.APIVersion}
+	r.Project.Name = name
+	if d.Stack != nil {
+		r.Project.Type = d.Stack.Value
+	}
+	r.Stack.Linter = Best(d.Linter)
+	r.Stack.Tests = Best(d.Tests)
+	r.Stack.Persistence = Best(d.Persistence)
+	r.Stack.CI = Best(d.CI)
+	return r
+}
+
+// signal is one piece of evidence for a value.
+type signal struct {
+	value    string
+	weight   float64
+	evidence string
+}
+
+// combine groups signals by value, most likely first.
+func combine(signals []signal) []Finding {
+	findings := []Finding{}
+	index := make(map[string]int)
+	for _, s := range signals {
+		i, ok := index[s.value]
+		if !ok {
+			i = len(findings)
+			index[s.value] = i
+			findings = append(findings, Finding{Value: s.value})
+		}
+		f := &findings[i]
+		f.Confidence = 1 - (1-f.Confidence)*(1-s.weight)
+		f.Evidence = append(f.Evidence, s.evidence)
+	}
+	for i := range findings {
+		findings[i].Confidence = round(findings[i].Confidence)
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Confidence > findings[j].Confidence
+	})
+	return findings
+}
+
+func round(confidence float64) float64 {
+	return float64(int(confidence*100+0.5)) / 100
+}
+
+// project reads the files of the project being detected, parsing each
+// manifest once.
+type project struct {
+	fsys     fs.FS
+	node     *packageJSON
+	composer *composerJSON
+}
+
+type packageJSON struct {
+	PackageManager  string            `json:"packageManager"`
+	Dependencies    map[string]string `json:"dependencies"`
+	DevDependencies map[string]string `json:"devDependencies"`
+}
+
+type composerJSON struct {
+	Require    map[string]string `json:"require"`
+	RequireDev map[string]string `json:"require-dev"`
+}
+
+// read returns the content of name, or nil if the project has no such file.
+func (p *project) read(name string) ([]byte, error) {
+	data, err := fs.ReadFile(p.fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// text returns the content of name in lower case, or "" if the project has
+// no such file.
+func (p *project) text(name string) (string, error) {
+	data, err := p.read(name)
+	return strings.ToLower(string(data)), err
+}
+
+func (p *project) exists(name string) bool {
+	_, err := fs.Stat(p.fsys, name)
+	return err == nil
+}
+
+// first returns the first of names the project contains, or "".
+func (p *project) first(names ...string) string {
+	for _, name := range names {
+		if p.exists(name) {
+			return name
+		}
+	}
+	return ""
+}
+
+// packageJSON returns the parsed package.json, or nil if there is none.
+func (p *project) packageJSON() (*packageJSON, error) {
+	if p.node != nil {
+		return p.node, nil
+	}
+	data, err := p.read("package.json")
+	if data == nil || err != nil {
+		return nil, err
+	}
+	var manifest packageJSON
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("could not parse package.json: %w", err)
+	}
+	p.node = &manifest
+	return p.node, nil
+}
+
+// nodeDependency reports whether package.json declares name, as a
+// dependency or a development dependency.
+func (p *project) nodeDependency(name string) (bool, error) {
+	manifest, err := p.packageJSON()
+	if manifest == nil || err != nil {
+		return false, err
+	}
+	_, dependency := manifest.Dependencies[name]
+	_, devDependency := manifest.DevDependencies[name]
+	return dependency || devDependency, nil
+}
+
+// composerJSON returns the parsed composer.json, or nil if there is none.
+func (p *project) composerJSON() (*composerJSON, error) {
+	if p.composer != nil {
+		return p.composer, nil
+	}
+	data, err := p.read("composer.json")
+	if data == nil || err != nil {
+		return nil, err
+	}
+	var manifest composerJSON
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("could not parse composer.json: %w", err)
+	}
+	p.composer = &manifest
+	return p.composer, nil
+}
+
+// composerPackage reports whether composer.json requires name.
+func (p *project) composerPackage(name string) (bool, error) {
+	manifest, err := p.composerJSON()
+	if manifest == nil || err != nil {
+		return false, err
+	}
+	_, required := manifest.Require[name]
+	_, requiredDev := manifest.RequireDev[name]
+	return required || requiredDev, nil
+}
+
+// pythonPackage returns the Python manifest that names the package, or "".
+func (p *project) pythonPackage(name string) (string, error) {
+	for _, file := range []string{"pyproject.toml", "requirements.txt"} {
+		text, err := p.text(file)
+		if err != nil {
+			return "", err
+		}
+		if strings.Contains(text, name) {
+			return file, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,181 @@
+package detector
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDetectStack(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    fstest.MapFS
+		stack    string
+		evidence []string
+	}{
+		{
+			name:     "go",
+			files:    fstest.MapFS{"go.mod": {Data: []byte("module x\n\nrequire github.com/spf13/cobra v1.8.0\n")}},
+			stack:    "golang-cli",
+			evidence: []string{"go.mod", "go.mod: github.com/spf13/cobra"},
+		},
+		{
+			name:     "express",
+			files:    fstest.MapFS{"package.json": {Data: []byte(`{"dependencies": {"express": "^4.18.0"}}`)}},
+			stack:    "typescript-express-fullstack",
+			evidence: []string{"package.json: express"},
+		},
+		{
+			name: "serverless express",
+			files: fstest.MapFS{
+				"package.json":   {Data: []byte(`{"dependencies": {"express": "^4.18.0"}}`)},
+				"serverless.yml": {Data: []byte("service: api")},
+			},
+			stack:    "typescript-express",
+			evidence: []string{"package.json: express", "serverless.yml"},
+		},
+		{
+			name:     "angular",
+			files:    fstest.MapFS{"package.json": {Data: []byte(`{"dependencies": {"@angular/core": "^17.0.0", "express": "^4"}}`)}},
+			stack:    "typescript-angular",
+			evidence: []string{"package.json: @angular/core"},
+		},
+		{
+			name:     "vue",
+			files:    fstest.MapFS{"package.json": {Data: []byte(`{"devDependencies": {"vue": "^3.4.0"}}`)}},
+			stack:    "typescript-vuejs",
+			evidence: []string{"package.json: vue"},
+		},
+		{
+			name:     "symfony",
+			files:    fstest.MapFS{"composer.json": {Data: []byte(`{"require": {"php": ">=8.2", "symfony/framework-bundle": "7.0.*"}}`)}},
+			stack:    "php-symfony-fullstack",
+			evidence: []string{"composer.json: symfony/framework-bundle"},
+		},
+		{
+			name:     "fastapi",
+			files:    fstest.MapFS{"pyproject.toml": {Data: []byte("[project]\ndependencies = [\"FastAPI>=0.110\"]\n")}},
+			stack:    "python-fastapi",
+			evidence: []string{"pyproject.toml: fastapi"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := DetectStack(tt.files)
+			if err != nil {
+				t.Fatalf("DetectStack() returned an unexpected error: %v", err)
+			}
+			if match == nil || match.Value != tt.stack || !reflect.DeepEqual(match.Evidence, tt.evidence) {
+				t.Errorf("DetectStack() = %+v, want %s from %v", match, tt.stack, tt.evidence)
+			}
+		})
+	}
+}
+
+func TestDetectStack_Unknown(t *testing.T) {
+	match, err := DetectStack(fstest.MapFS{"package.json": {Data: []byte(`{"dependencies": {"react": "^18"}}`)}})
+	if err != nil || match != nil {
+		t.Errorf("DetectStack() = %+v, %v, want no match", match, err)
+	}
+
+	if _, err := DetectStack(fstest.MapFS{"package.json": {Data: []byte("{")}}); err == nil {
+		t.Error("DetectStack() should report an invalid package.json")
+	}
+}
+
+func TestDetect(t *testing.T) {
+	files := fstest.MapFS{
+		"package.json": {Data: []byte(`{
+			"dependencies": {"express": "^4.18.0", "pg": "^8.11.0"},
+			"devDependencies": {"typescript": "^5.4.0", "jest": "^29.0.0", "eslint": "^8.57.0"}
+		}`)},
+		"package-lock.json":        {Data: []byte("{}")},
+		"tsconfig.json":            {Data: []byte("{}")},
+		".eslintrc.js":             {Data: []byte("module.exports = {}")},
+		"jest.config.js":           {Data: []byte("module.exports = {}")},
+		".github/workflows/ci.yml": {Data: []byte("on: push")},
+		"docker-compose.yml": {Data: []byte(`services:
+  db:
+    image: postgres:16
+  cache:
+    image: redis:7
+`)},
+	}
+
+	detection, err := Detect(files)
+	if err != nil {
+		t.Fatalf("Detect() returned an unexpected error: %v", err)
+	}
+
+	if detection.Stack == nil || detection.Stack.Value != "typescript-express-fullstack" {
+		t.Errorf("Stack = %+v, want typescript-express-fullstack", detection.Stack)
+	}
+	for _, aspect := range []struct {
+		name     string
+		findings []Finding
+		want     string
+	}{
+		{"Language", detection.Language, "TypeScript"},
+		{"Framework", detection.Framework, "Express"},
+		{"Tests", detection.Tests, "jest"},
+		{"Linter", detection.Linter, "ESLint"},
+		{"PackageManager", detection.PackageManager, "npm"},
+		{"CI", detection.CI, "GitHub Actions"},
+		{"Persistence", detection.Persistence, "PostgreSQL"},
+	} {
+		if got := Best(aspect.findings); got != aspect.want {
+			t.Errorf("%s = %+v, want %s first", aspect.name, aspect.findings, aspect.want)
+		}
+	}
+
+	// The config file and the devDependency are independent signals.
+	if linter := detection.Linter[0]; linter.Confidence != 0.99 || len(linter.Evidence) != 2 {
+		t.Errorf("Linter = %+v, want confidence 0.99 from two signals", linter)
+	}
+
+	r := detection.Recipe("api")
+	if r.Project.Name != "api" || r.Project.Type != "typescript-express-fullstack" ||
+		r.Stack.Linter != "ESLint" || r.Stack.Tests != "jest" || r.Stack.Persistence != "PostgreSQL" || r.Stack.CI != "GitHub Actions" {
+		t.Errorf("Recipe() = %+v", r)
+	}
+}
+
+func TestCombine(t *testing.T) {
+	findings := combine([]signal{
+		{value: "yarn", weight: 0.5, evidence: "a"},
+		{value: "npm", weight: 0.8, evidence: "b"},
+		{value: "yarn", weight: 0.5, evidence: "c"},
+	})
+	want := []Finding{
+		{Value: "npm", Confidence: 0.8, Evidence: []string{"b"}},
+		{Value: "yarn", Confidence: 0.75, Evidence: []string{"a", "c"}},
+	}
+	if !reflect.DeepEqual(findings, want) {
+		t.Errorf("combine() = %+v, want %+v", findings, want)
+	}
+
+	if got := Best([]Finding{{Value: "npm", Confidence: 0.4}}); got != "" {
+		t.Errorf("Best() = %q, want no value below MinConfidence", got)
+	}
+}
+
+func TestDetect_ComposeOverride(t *testing.T) {
+	files := fstest.MapFS{
+		"docker-compose.yml": {Data: []byte(`services:
+  app:
+    build: .
+`)},
+		"docker-compose.override.yml": {Data: []byte(`services:
+  postgres:
+    image: postgres:16-alpine
+`)},
+	}
+
+	detection, err := Detect(files)
+	if err != nil {
+		t.Fatalf("Detect() returned an unexpected error: %v", err)
+	}
+	if got := Best(detection.Persistence); got != "PostgreSQL" {
+		t.Errorf("Persistence = %q, want PostgreSQL from the override file", got)
+	}
+}
@@ -0,0 +1,363 @@
+package detector
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Signal weights. A file that only exists for one tool is conclusive; a
+// dependency says the project uses the tool, not how.
+const (
+	conclusive = 0.95
+	strong     = 0.8
+	weak       = 0.5
+)
+
+func containsModule(text, module string) bool {
+	return strings.Contains(text, strings.ToLower(module))
+}
+
+// fileSignals adds a signal for value when the project contains any of
+// files.
+func fileSignals(p *project, value string, weight float64, files ...string) []signal {
+	if file := p.first(files...); file != "" {
+		return []signal{{value: value, weight: weight, evidence: file}}
+	}
+	return nil
+}
+
+// nodeSignals adds a signal for every package.json dependency among deps,
+// pairs of package name and value.
+func nodeSignals(p *project, weight float64, deps [][2]string) ([]signal, error) {
+	var signals []signal
+	for _, dep := range deps {
+		found, err := p.nodeDependency(dep[0])
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			signals = append(signals, signal{value: dep[1], weight: weight, evidence: "package.json: " + dep[0]})
+		}
+	}
+	return signals, nil
+}
+
+// composerSignals adds a signal for every composer.json package of pkgs.
+func composerSignals(p *project, weight float64, pkgs [][2]string) ([]signal, error) {
+	var signals []signal
+	for _, pkg := range pkgs {
+		found, err := p.composerPackage(pkg[0])
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			signals = append(signals, signal{value: pkg[1], weight: weight, evidence: "composer.json: " + pkg[0]})
+		}
+	}
+	return signals, nil
+}
+
+// textSignals adds a signal for every module of modules named in the file.
+func textSignals(p *project, file string, weight float64, modules [][2]string) ([]signal, error) {
+	text, err := p.text(file)
+	if text == "" || err != nil {
+		return nil, err
+	}
+	var signals []signal
+	for _, module := range modules {
+		if containsModule(text, module[0]) {
+			signals = append(signals, signal{value: module[1], weight: weight, evidence: file + ": " + module[0]})
+		}
+	}
+	return signals, nil
+}
+
+// collect runs every source of signals and joins their results.
+func collect(sources ...func() ([]signal, error)) ([]signal, error) {
+	var signals []signal
+	for _, source := range sources {
+		found, err := source()
+		if err != nil {
+			return nil, err
+		}
+		signals = append(signals, found...)
+	}
+	return signals, nil
+}
+
+func languageSignals(p *project) ([]signal, error) {
+	return collect(
+		func() ([]signal, error) { return fileSignals(p, "Go", conclusive, "go.mod"), nil },
+		func() ([]signal, error) { return fileSignals(p, "TypeScript", conclusive, "tsconfig.json"), nil },
+		func() ([]signal, error) {
+			return nodeSignals(p, strong, [][2]string{{"typescript", "TypeScript"}})
+		},
+		func() ([]signal, error) { return fileSignals(p, "JavaScript", weak, "package.json"), nil },
+		func() ([]signal, error) { return fileSignals(p, "PHP", conclusive, "composer.json"), nil },
+		func() ([]signal, error) {
+			return fileSignals(p, "Python", conclusive, "pyproject.toml", "requirements.txt", "setup.py", "Pipfile"), nil
+		},
+	)
+}
+
+func frameworkSignals(p *project) ([]signal, error) {
+	return collect(
+		func() ([]signal, error) {
+			return nodeSignals(p, conclusive, [][2]string{
+				{"@angular/core", "Angular"},
+				{"vue", "Vue"},
+				{"react", "React"},
+				{"@nestjs/core", "NestJS"},
+				{"express", "Express"},
+			})
+		},
+		func() ([]signal, error) {
+			return composerSignals(p, conclusive, [][2]string{
+				{"symfony/framework-bundle", "Symfony"},
+				{"laravel/framework", "Laravel"},
+			})
+		},
+		func() ([]signal, error) {
+			return textSignals(p, "go.mod", conclusive, [][2]string{
+				{"github.com/spf13/cobra", "Cobra"},
+				{"github.com/gin-gonic/gin", "Gin"},
+				{"github.com/labstack/echo", "Echo"},
+			})
+		},
+		func() ([]signal, error) {
+			modules := [][2]string{{"fastapi", "FastAPI"}, {"django", "Django"}, {"flask", "Flask"}}
+			signals, err := textSignals(p, "pyproject.toml", strong, modules)
+			if err != nil {
+				return nil, err
+			}
+			more, err := textSignals(p, "requirements.txt", strong, modules)
+			return append(signals, more...), err
+		},
+	)
+}
+
+func testSignals(p *project) ([]signal, error) {
+	return collect(
+		func() ([]signal, error) {
+			return fileSignals(p, "jest", conclusive, "jest.config.js", "jest.config.ts", "jest.config.cjs", "jest.config.mjs"), nil
+		},
+		func() ([]signal, error) {
+			return fileSignals(p, "vitest", conclusive, "vitest.config.ts", "vitest.config.js", "vitest.config.mts"), nil
+		},
+		func() ([]signal, error) {
+			return nodeSignals(p, strong, [][2]string{{"jest", "jest"}, {"vitest", "vitest"}, {"mocha", "mocha"}})
+		},
+		func() ([]signal, error) {
+			return fileSignals(p, "phpunit", conclusive, "phpunit.xml", "phpunit.xml.dist"), nil
+		},
+		func() ([]signal, error) {
+			return composerSignals(p, strong, [][2]string{{"phpunit/phpunit", "phpunit"}})
+		},
+		func() ([]signal, error) {
+			return fileSignals(p, "pytest", conclusive, "pytest.ini", "conftest.py"), nil
+		},
+		func() ([]signal, error) {
+			return textSignals(p, "pyproject.toml", strong, [][2]string{{"pytest", "pytest"}})
+		},
+		func() ([]signal, error) { return fileSignals(p, "go test", strong, "go.mod"), nil },
+	)
+}
+
+// linterSignals uses the linter names of the stack options and the linter
+// detector of verify.
+func linterSignals(p *project) ([]signal, error) {
+	return collect(
+		func() ([]signal, error) {
+			return fileSignals(p, "ESLint", conclusive,
+				".eslintrc.js", ".eslintrc.cjs", ".eslintrc.json", ".eslintrc.yml", ".eslintrc", "eslint.config.js", "eslint.config.mjs"), nil
+		},
+		func() ([]signal, error) { return nodeSignals(p, strong, [][2]string{{"eslint", "ESLint"}}) },
+		func() ([]signal, error) {
+			return fileSignals(p, "golangci-lint", conclusive, ".golangci.yml", ".golangci.yaml"), nil
+		},
+		func() ([]signal, error) {
+			return fileSignals(p, "phpcs", conclusive, "phpcs.xml", "phpcs.xml.dist"), nil
+		},
+		func() ([]signal, error) {
+			return composerSignals(p, strong, [][2]string{{"squizlabs/php_codesniffer", "phpcs"}, {"phpstan/phpstan", "PHPStan"}})
+		},
+		func() ([]signal, error) {
+			return fileSignals(p, "PHPStan", conclusive, "phpstan.neon", "phpstan.neon.dist"), nil
+		},
+		func() ([]signal, error) { return fileSignals(p, "Ruff", conclusive, "ruff.toml", ".ruff.toml"), nil },
+		func() ([]signal, error) {
+			return textSignals(p, "pyproject.toml", strong, [][2]string{{"[tool.ruff", "Ruff"}})
+		},
+		func() ([]signal, error) { return fileSignals(p, "Flake8", conclusive, ".flake8"), nil },
+	)
+}
+
+func packageManagerSignals(p *project) ([]signal, error) {
+	signals := []signal{}
+	signals = append(signals, fileSignals(p, "npm", conclusive, "package-lock.json")...)
+	signals = append(signals, fileSignals(p, "yarn", conclusive, "yarn.lock")...)
+	signals = append(signals, fileSignals(p, "pnpm", conclusive, "pnpm-lock.yaml")...)
+	manifest, err := p.packageJSON()
+	if err != nil {
+		return nil, err
+	}
+	if manifest != nil {
+		if name, _, ok := strings.Cut(manifest.PackageManager, "@"); ok {
+			signals = append(signals, signal{value: name, weight: conclusive, evidence: "package.json: packageManager"})
+		} else if len(signals) == 0 {
+			signals = append(signals, signal{value: "npm", weight: weak, evidence: "package.json"})
+		}
+	}
+	signals = append(signals, fileSignals(p, "go modules", conclusive, "go.mod")...)
+	signals = append(signals, fileSignals(p, "composer", conclusive, "composer.lock", "composer.json")...)
+	signals = append(signals, fileSignals(p, "poetry", conclusive, "poetry.lock")...)
+	signals = append(signals, fileSignals(p, "uv", conclusive, "uv.lock")...)
+	signals = append(signals, fileSignals(p, "pipenv", conclusive, "Pipfile.lock", "Pipfile")...)
+	signals = append(signals, fileSignals(p, "pip", strong, "requirements.txt")...)
+	return signals, nil
+}
+
+func ciSignals(p *project) ([]signal, error) {
+	var signals []signal
+	workflows, err := fs.Glob(p.fsys, ".github/workflows/*.y*ml")
+	if err != nil {
+		return nil, err
+	}
+	if len(workflows) > 0 {
+		signals = append(signals, signal{value: "GitHub Actions", weight: conclusive, evidence: workflows[0]})
+	}
+	signals = append(signals, fileSignals(p, "Bitbucket Pipelines", conclusive, "bitbucket-pipelines.yml")...)
+	signals = append(signals, fileSignals(p, "GitLab CI", conclusive, ".gitlab-ci.yml")...)
+	signals = append(signals, fileSignals(p, "Jenkins", conclusive, "Jenkinsfile")...)
+	return signals, nil
+}
+
+// databases maps what database images, drivers and ORM settings mention to
+// the persistence values of the stack options.
+var databases = [][2]string{
+	{"postgres", "PostgreSQL"},
+	{"pgsql", "PostgreSQL"},
+	{"mysql", "MySQL"},
+	{"mariadb", "MySQL"},
+	{"mongo", "MongoDB"},
+}
+
+// ormConfigs are the ORM settings that name the database of a project.
+var ormConfigs = []string{
+	"prisma/schema.prisma",
+	"ormconfig.json",
+	"ormconfig.ts",
+	"ormconfig.js",
+	"src/infrastructure/ormconfig.ts",
+	"config/packages/doctrine.yaml",
+	"alembic.ini",
+}
+
+func persistenceSignals(p *project) ([]signal, error) {
+	signals, err := composeSignals(p)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range ormConfigs {
+		found, err := textSignals(p, file, strong, databases)
+		if err != nil {
+			return nil, err
+		}
+		signals = append(signals, dedupe(found)...)
+	}
+	drivers, err := collect(
+		func() ([]signal, error) {
+			return nodeSignals(p, weak, [][2]string{
+				{"pg", "PostgreSQL"}, {"mysql2", "MySQL"}, {"mysql", "MySQL"}, {"mongoose", "MongoDB"}, {"mongodb", "MongoDB"},
+			})
+		},
+		func() ([]signal, error) {
+			return textSignals(p, "go.mod", weak, [][2]string{
+				{"github.com/lib/pq", "PostgreSQL"}, {"github.com/jackc/pgx", "PostgreSQL"}, {"github.com/go-sql-driver/mysql", "MySQL"},
+			})
+		},
+		func() ([]signal, error) {
+			return textSignals(p, "requirements.txt", weak, [][2]string{
+				{"psycopg", "PostgreSQL"}, {"asyncpg", "PostgreSQL"}, {"pymysql", "MySQL"}, {"pymongo", "MongoDB"},
+			})
+		},
+	)
+	return append(signals, drivers...), err
+}
+
+// composeSignals reads the services of the project's compose file, and of
+// its override file, where mixins add their services, and adds a signal for
+// every database image.
+func composeSignals(p *project) ([]signal, error) {
+	var signals []signal
+	for _, file := range []string{
+		p.first("docker-compose.yml", "docker-compose.yaml", "compose.yml", "compose.yaml"),
+		p.first("docker-compose.override.yml", "docker-compose.override.yaml", "compose.override.yml", "compose.override.yaml"),
+	} {
+		if file == "" {
+			continue
+		}
+		found, err := composeFileSignals(p, file)
+		if err != nil {
+			return nil, err
+		}
+		signals = append(signals, found...)
+	}
+	return signals, nil
+}
+
+func composeFileSignals(p *project, file string) ([]signal, error) {
+	data, err := p.read(file)
+	if err != nil {
+		return nil, err
+	}
+	var compose struct {
+		Services map[string]struct {
+			Image string `yaml:"image"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &compose); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", file, err)
+	}
+
+	names := make([]string, 0, len(compose.Services))
+	for name := range compose.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var signals []signal
+	for _, name := range names {
+		service := compose.Services[name]
+		image := strings.ToLower(service.Image)
+		for _, db := range databases {
+			if strings.HasPrefix(image, db[0]) || strings.Contains(image, "/"+db[0]) {
+				signals = append(signals, signal{
+					value:    db[1],
+					weight:   0.9,
+					evidence: fmt.Sprintf("%s: service %s (%s)", file, name, service.Image),
+				})
+				break
+			}
+		}
+	}
+	return signals, nil
+}
+
+// dedupe keeps one signal per value, as a file that names a database twice
+// is no more evidence than one that names it once.
+func dedupe(signals []signal) []signal {
+	seen := make(map[string]bool)
+	var unique []signal
+	for _, s := range signals {
+		if !seen[s.value] {
+			seen[s.value] = true
+			unique = append(unique, s)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,100 @@
+package detector
+
+// stackRule recognises one stack of the templates. It returns the evidence
+// found, or nil.
+type stackRule struct {
+	stack string
+	match func(p *project) ([]string, error)
+}
+
+// stackRules are tried in order; the first that matches wins. Serverless
+// and single-page stacks come before the fullstack ones they share
+// dependencies with.
+var stackRules = []stackRule{
+	{stack: "golang-cli", match: goModule},
+	{stack: "typescript-angular", match: nodeStack("@angular/core", "")},
+	{stack: "typescript-vuejs", match: nodeStack("vue", "")},
+	{stack: "typescript-express", match: nodeStack("express", "serverless.yml")},
+	{stack: "typescript-express-fullstack", match: nodeStack("express", "")},
+	{stack: "php-symfony", match: composerStack("symfony/framework-bundle", "serverless.yml")},
+	{stack: "php-symfony-fullstack", match: composerStack("symfony/framework-bundle", "")},
+	{stack: "python-fastapi", match: pythonStack("fastapi")},
+}
+
+// detectStack applies the stack rules. A stack recognised from its
+// framework is confident; one recognised only from the language is a guess.
+func detectStack(p *project) (*Finding, error) {
+	for _, r := range stackRules {
+		evidence, err := r.match(p)
+		if err != nil {
+			return nil, err
+		}
+		if evidence == nil {
+			continue
+		}
+		confidence := 0.9
+		if len(evidence) == 1 && evidence[0] == "go.mod" {
+			confidence = 0.6
+		}
+		return &Finding{Value: r.stack, Confidence: confidence, Evidence: evidence}, nil
+	}
+	return nil, nil
+}
+
+func goModule(p *project) ([]string, error) {
+	text, err := p.text("go.mod")
+	if text == "" || err != nil {
+		return nil, err
+	}
+	evidence := []string{"go.mod"}
+	if containsModule(text, "github.com/spf13/cobra") {
+		evidence = append(evidence, "go.mod: github.com/spf13/cobra")
+	}
+	return evidence, nil
+}
+
+// nodeStack matches a package.json that depends on name. When marker is
+// set, the project must also contain that file.
+func nodeStack(name, marker string) func(*project) ([]string, error) {
+	return func(p *project) ([]string, error) {
+		found, err := p.nodeDependency(name)
+		if !found || err != nil {
+			return nil, err
+		}
+		return withMarker(p, []string{"package.json: " + name}, marker), nil
+	}
+}
+
+// composerStack matches a composer.json that requires name. When marker is
+// set, the project must also contain that file.
+func composerStack(name, marker string) func(*project) ([]string, error) {
+	return func(p *project) ([]string, error) {
+		found, err := p.composerPackage(name)
+		if !found || err != nil {
+			return nil, err
+		}
+		return withMarker(p, []string{"composer.json: " + name}, marker), nil
+	}
+}
+
+// pythonStack matches a pyproject.toml or requirements.txt that names the
+// package.
+func pythonStack(name string) func(*project) ([]string, error) {
+	return func(p *project) ([]string, error) {
+		file, err := p.pythonPackage(name)
+		if file == "" || err != nil {
+			return nil, err
+		}
+		return []string{file + ": " + name}, nil
+	}
+}
+
+func withMarker(p *project, evidence []string, marker string) []string {
+	if marker == "" {
+		return evidence
+	}
+	if !p.exists(marker) {
+		return nil
+	}
+	return append(evidence, marker)
+}
@@ -0,0 +1,200 @@
+// Package diff compares and merges text files line by line.
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines around each hunk of a
+// unified diff.
+const contextLines = 3
+
+// SplitLines splits content into lines, keeping their line endings so that
+// joining them gives back content.
+func SplitLines(content []byte) []string {
+	var lines []string
+	for len(content) > 0 {
+		i := bytes.IndexByte(content, '\n')
+		if i < 0 {
+			lines = append(lines, string(content))
+			break
+		}
+		lines = append(lines, string(content[:i+1]))
+		content = content[i+1:]
+	}
+	return lines
+}
+
+// IsBinary reports whether content looks like a binary file.
+func IsBinary(content []byte) bool {
+	return bytes.IndexByte(content, 0) >= 0
+}
+
+// match is a pair of equal lines, a[A] == b[B].
+type match struct {
+	A, B int
+}
+
+// matches returns the lines shared by a and b in a longest common
+// subsequence, in increasing order. It implements Myers' O(ND) algorithm.
+func matches(a, b []string) []match {
+	n, m := len(a), len(b)
+	max := n + m
+	if max == 0 {
+		return nil
+	}
+
+	offset := max
+	v := make([]int, 2*max+2)
+	var trace [][]int
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, a, b, offset, d)
+			}
+		}
+	}
+	return nil
+}
+
+// backtrack walks the Myers trace from the end of both inputs to collect the
+// diagonal moves, which are the matching lines.
+func backtrack(trace [][]int, a, b []string, offset, d int) []match {
+	var found []match
+	x, y := len(a), len(b)
+	for ; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			found = append(found, match{x, y})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		found = append(found, match{x, y})
+	}
+
+	for i, j := 0, len(found)-1; i < j; i, j = i+1, j-1 {
+		found[i], found[j] = found[j], found[i]
+	}
+	return found
+}
+
+// Unified returns the differences from a to b in unified diff format, or an
+// empty string when they are equal.
+func Unified(fromName, toName string, a, b []byte) string {
+	if bytes.Equal(a, b) {
+		return ""
+	}
+	if IsBinary(a) || IsBinary(b) {
+		return fmt.Sprintf("Binary files %s and %s differ\n", fromName, toName)
+	}
+
+	aLines, bLines := SplitLines(a), SplitLines(b)
+	pairs := append(matches(aLines, bLines), match{len(aLines), len(bLines)})
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+
+	// Group the changes between consecutive matches into hunks, merging
+	// changes that are closer than twice the context.
+	type change struct{ a0, a1, b0, b1 int }
+	var changes []change
+	x, y := 0, 0
+	for _, p := range pairs {
+		if p.A > x || p.B > y {
+			changes = append(changes, change{x, p.A, y, p.B})
+		}
+		x, y = p.A+1, p.B+1
+	}
+
+	for i := 0; i < len(changes); {
+		j := i
+		for j+1 < len(changes) && changes[j+1].a0-changes[j].a1 <= 2*contextLines {
+			j++
+		}
+		a0 := maxInt(changes[i].a0-contextLines, 0)
+		b0 := maxInt(changes[i].b0-contextLines, 0)
+		a1 := minInt(changes[j].a1+contextLines, len(aLines))
+		b1 := minInt(changes[j].b1+contextLines, len(bLines))
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(a0, a1), hunkRange(b0, b1))
+
+		pos := a0
+		for _, c := range changes[i : j+1] {
+			for ; pos < c.a0; pos++ {
+				writeLine(&out, " ", aLines[pos])
+			}
+			for _, line := range aLines[c.a0:c.a1] {
+				writeLine(&out, "-", line)
+			}
+			for _, line := range bLines[c.b0:c.b1] {
+				writeLine(&out, "+", line)
+			}
+			pos = c.a1
+		}
+		for ; pos < a1; pos++ {
+			writeLine(&out, " ", aLines[pos])
+		}
+		i = j + 1
+	}
+	return out.String()
+}
+
+func hunkRange(start, end int) string {
+	if end-start == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	if end == start {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, end-start)
+}
+
+func writeLine(out *strings.Builder, prefix, line string) {
+	out.WriteString(prefix)
+	out.WriteString(line)
+	if !strings.HasSuffix(line, "\n") {
+		out.WriteString("\n\\ No newline at end of file\n")
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
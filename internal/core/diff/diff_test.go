@@ -0,0 +1,113 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitLines(t *testing.T) {
+	lines := SplitLines([]byte("a\nb\nc"))
+	if len(lines) != 3 || lines[0] != "a\n" || lines[2] != "c" {
+		t.Errorf("Unexpected lines: %q", lines)
+	}
+	if strings.Join(lines, "") != "a\nb\nc" {
+		t.Error("Joining the lines should give back the content")
+	}
+}
+
+func TestUnified(t *testing.T) {
+	a := []byte("one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n")
+	b := []byte("one\ntwo\nTHREE\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\n")
+
+	got := Unified("a/file", "b/file", a, b)
+	want := `--- a/file
++++ b/file
+@@ -1,6 +1,6 @@
+ one
+ two
+-three
++THREE
+ four
+ five
+ six
+@@ -8,3 +8,4 @@
+ eight
+ nine
+ ten
++eleven
+`
+	if got != want {
+		t.Errorf("Unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+
+	if Unified("a", "b", a, a) != "" {
+		t.Error("Equal contents should have an empty diff")
+	}
+	if !strings.Contains(Unified("a", "b", []byte("x"), []byte("\x00")), "Binary files") {
+		t.Error("Binary contents should not be diffed line by line")
+	}
+}
+
+func TestUnified_NewFile(t *testing.T) {
+	got := Unified("/dev/null", "b/file", nil, []byte("hello"))
+	want := "--- /dev/null\n+++ b/file\n@@ -0,0 +1 @@\n+hello\n\\ No newline at end of file\n"
+	if got != want {
+		t.Errorf("Unexpected diff:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestMerge3(t *testing.T) {
+	labels := Labels{Ours: "current", Theirs: "templates"}
+	base := "FROM node:18\nWORKDIR /app\nCOPY . .\nCMD [\"npm\", \"start\"]\n"
+
+	tests := []struct {
+		name          string
+		ours, theirs  string
+		want          string
+		wantConflicts int
+	}{
+		{
+			name:   "only templates changed",
+			ours:   base,
+			theirs: "FROM node:20\nWORKDIR /app\nCOPY . .\nCMD [\"npm\", \"start\"]\n",
+			want:   "FROM node:20\nWORKDIR /app\nCOPY . .\nCMD [\"npm\", \"start\"]\n",
+		},
+		{
+			name:   "changes in different lines",
+			ours:   "FROM node:18\nWORKDIR /app\nCOPY . .\nRUN npm ci\nCMD [\"npm\", \"start\"]\n",
+			theirs: "FROM node:20\nWORKDIR /app\nCOPY . .\nCMD [\"npm\", \"start\"]\n",
+			want:   "FROM node:20\nWORKDIR /app\nCOPY . .\nRUN npm ci\nCMD [\"npm\", \"start\"]\n",
+		},
+		{
+			name:   "same change on both sides",
+			ours:   "FROM node:20\nWORKDIR /app\nCOPY . .\nCMD [\"npm\", \"start\"]\n",
+			theirs: "FROM node:20\nWORKDIR /app\nCOPY . .\nCMD [\"npm\", \"start\"]\n",
+			want:   "FROM node:20\nWORKDIR /app\nCOPY . .\nCMD [\"npm\", \"start\"]\n",
+		},
+		{
+			name:          "conflicting changes",
+			ours:          "FROM node:19\nWORKDIR /app\nCOPY . .\nCMD [\"npm\", \"start\"]\n",
+			theirs:        "FROM node:20\nWORKDIR /app\nCOPY . .\nCMD [\"npm\", \"start\"]\n",
+			want:          "<<<<<<< current\nFROM node:19\n=======\nFROM node:20\n>>>>>>> templates\nWORKDIR /app\nCOPY . .\nCMD [\"npm\", \"start\"]\n",
+			wantConflicts: 1,
+		},
+		{
+			name:   "lines appended by the templates",
+			ours:   "FROM node:18\nWORKDIR /app\nCOPY . .\nCMD [\"npm\", \"start\"]\n",
+			theirs: base + "USER node\n",
+			want:   base + "USER node\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts := Merge3([]byte(base), []byte(tt.ours), []byte(tt.theirs), labels)
+			if string(got) != tt.want {
+				t.Errorf("Unexpected merge:\n%s\nwant:\n%s", got, tt.want)
+			}
+			if conflicts != tt.wantConflicts {
+				t.Errorf("Expected %d conflicts, got %d", tt.wantConflicts, conflicts)
+			}
+		})
+	}
+}
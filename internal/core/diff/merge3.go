@@ -0,0 +1,101 @@
+package diff
+
+import "strings"
+
+// Labels name the sides of a conflict in the markers written by Merge3.
+type Labels struct {
+	Ours   string
+	Theirs string
+}
+
+// Merge3 merges the changes from base to ours and from base to theirs. Where
+// both sides changed the same lines differently, the result holds both
+// versions between conflict markers. It returns the merged content and the
+// number of conflicts.
+func Merge3(base, ours, theirs []byte, labels Labels) ([]byte, int) {
+	baseLines := SplitLines(base)
+	oursLines := SplitLines(ours)
+	theirsLines := SplitLines(theirs)
+
+	inOurs := make([]int, len(baseLines))
+	inTheirs := make([]int, len(baseLines))
+	for i := range baseLines {
+		inOurs[i], inTheirs[i] = -1, -1
+	}
+	for _, p := range matches(baseLines, oursLines) {
+		inOurs[p.A] = p.B
+	}
+	for _, p := range matches(baseLines, theirsLines) {
+		inTheirs[p.A] = p.B
+	}
+
+	var out strings.Builder
+	conflicts := 0
+	b, o, t := 0, 0, 0
+	for {
+		// Find the next base line kept by both sides.
+		k := b
+		for k < len(baseLines) && (inOurs[k] < 0 || inTheirs[k] < 0) {
+			k++
+		}
+
+		oEnd, tEnd := len(oursLines), len(theirsLines)
+		if k < len(baseLines) {
+			oEnd, tEnd = inOurs[k], inTheirs[k]
+		}
+
+		if k > b || oEnd > o || tEnd > t {
+			baseChunk := baseLines[b:k]
+			oursChunk := oursLines[o:oEnd]
+			theirsChunk := theirsLines[t:tEnd]
+			switch {
+			case equalLines(oursChunk, baseChunk):
+				writeLines(&out, theirsChunk)
+			case equalLines(theirsChunk, baseChunk), equalLines(oursChunk, theirsChunk):
+				writeLines(&out, oursChunk)
+			default:
+				conflicts++
+				out.WriteString("<<<<<<< " + labels.Ours + "\n")
+				writeTerminated(&out, oursChunk)
+				out.WriteString("=======\n")
+				writeTerminated(&out, theirsChunk)
+				out.WriteString(">>>>>>> " + labels.Theirs + "\n")
+			}
+		}
+
+		if k >= len(baseLines) {
+			break
+		}
+		out.WriteString(baseLines[k])
+		b, o, t = k+1, oEnd+1, tEnd+1
+	}
+
+	return []byte(out.String()), conflicts
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func writeLines(out *strings.Builder, lines []string) {
+	for _, line := range lines {
+		out.WriteString(line)
+	}
+}
+
+// writeTerminated writes lines making sure the last one ends with a newline,
+// so that the following conflict marker starts on its own line.
+func writeTerminated(out *strings.Builder, lines []string) {
+	writeLines(out, lines)
+	if len(lines) > 0 && !strings.HasSuffix(lines[len(lines)-1], "\n") {
+		out.WriteString("\n")
+	}
+}
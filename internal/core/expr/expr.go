@@ -0,0 +1,313 @@
+// Package expr evaluates the small boolean expressions used in template
+// manifests, such as `stack.ci == "GitHub Actions" && stack.persistence != "None"`.
+//
+// Operands are double- or single-quoted strings, dotted variable names and
+// lists of strings. Supported operators, from lowest to highest precedence,
+// are `||`, `&&`, `!`, and the comparisons `==`, `!=` and `in [...]`.
+// Parentheses group. A variable on its own is true when it is set to a
+// non-empty value other than "false".
+package expr
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Expr is a compiled expression.
+type Expr struct {
+	source string
+	root   node
+}
+
+// Compile parses source into an expression.
+func Compile(source string) (*Expr, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", source, err)
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && !p.done() {
+		err = fmt.Errorf("unexpected %q", p.peek().text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", source, err)
+	}
+	return &Expr{source: source, root: root}, nil
+}
+
+// Eval compiles and evaluates source against vars.
+func Eval(source string, vars map[string]string) (bool, error) {
+	e, err := Compile(source)
+	if err != nil {
+		return false, err
+	}
+	return e.Eval(vars), nil
+}
+
+// Eval evaluates the expression. Variables missing from vars are empty.
+func (e *Expr) Eval(vars map[string]string) bool {
+	return truthy(e.root.eval(vars))
+}
+
+// Vars returns the variable names the expression refers to.
+func (e *Expr) Vars() []string {
+	var names []string
+	e.root.walk(func(n node) {
+		if v, ok := n.(variable); ok {
+			names = append(names, string(v))
+		}
+	})
+	return names
+}
+
+func (e *Expr) String() string {
+	return e.source
+}
+
+func truthy(value string) bool {
+	return value != "" && value != "false"
+}
+
+func boolValue(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+type node interface {
+	eval(vars map[string]string) string
+	walk(fn func(node))
+}
+
+type literal string
+
+func (n literal) eval(map[string]string) string { return string(n) }
+func (n literal) walk(fn func(node))            { fn(n) }
+
+type variable string
+
+func (n variable) eval(vars map[string]string) string { return vars[string(n)] }
+func (n variable) walk(fn func(node))                 { fn(n) }
+
+type not struct{ operand node }
+
+func (n not) eval(vars map[string]string) string {
+	return boolValue(!truthy(n.operand.eval(vars)))
+}
+func (n not) walk(fn func(node)) { fn(n); n.operand.walk(fn) }
+
+type binary struct {
+	op          string
+	left, right node
+}
+
+func (n binary) eval(vars map[string]string) string {
+	switch n.op {
+	case "||":
+		return boolValue(truthy(n.left.eval(vars)) || truthy(n.right.eval(vars)))
+	case "&&":
+		return boolValue(truthy(n.left.eval(vars)) && truthy(n.right.eval(vars)))
+	case "==":
+		return boolValue(n.left.eval(vars) == n.right.eval(vars))
+	default:
+		return boolValue(n.left.eval(vars) != n.right.eval(vars))
+	}
+}
+func (n binary) walk(fn func(node)) { fn(n); n.left.walk(fn); n.right.walk(fn) }
+
+type in struct {
+	operand node
+	values  []string
+}
+
+func (n in) eval(vars map[string]string) string {
+	value := n.operand.eval(vars)
+	for _, v := range n.values {
+		if v == value {
+			return "true"
+		}
+	}
+	return "false"
+}
+func (n in) walk(fn func(node)) { fn(n); n.operand.walk(fn) }
+
+type tokenKind int
+
+const (
+	tokenString tokenKind = iota
+	tokenIdent
+	tokenOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			end := i + 1
+			var value strings.Builder
+			for ; end < len(runes) && runes[end] != r; end++ {
+				if runes[end] == '\\' && end+1 < len(runes) {
+					end++
+				}
+				value.WriteRune(runes[end])
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			tokens = append(tokens, token{tokenString, value.String()})
+			i = end + 1
+		case unicode.IsLetter(r) || r == '_':
+			end := i
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || strings.ContainsRune("_.-", runes[end])) {
+				end++
+			}
+			tokens = append(tokens, token{tokenIdent, string(runes[i:end])})
+			i = end
+		default:
+			op := ""
+			for _, candidate := range []string{"==", "!=", "&&", "||", "!", "(", ")", "[", "]", ","} {
+				if strings.HasPrefix(string(runes[i:]), candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q", r)
+			}
+			tokens = append(tokens, token{tokenOp, op})
+			i += len([]rune(op))
+		}
+	}
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	if p.done() {
+		return token{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) accept(kind tokenKind, text string) bool {
+	if !p.done() && p.tokens[p.pos].kind == kind && p.tokens[p.pos].text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.accept(tokenOp, "||") {
+		var right node
+		right, err = p.parseAnd()
+		left = binary{op: "||", left: left, right: right}
+	}
+	return left, err
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	for err == nil && p.accept(tokenOp, "&&") {
+		var right node
+		right, err = p.parseUnary()
+		left = binary{op: "&&", left: left, right: right}
+	}
+	return left, err
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.accept(tokenOp, "!") {
+		operand, err := p.parseUnary()
+		return not{operand: operand}, err
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!="} {
+		if p.accept(tokenOp, op) {
+			right, err := p.parsePrimary()
+			return binary{op: op, left: left, right: right}, err
+		}
+	}
+	if p.accept(tokenIdent, "in") {
+		values, err := p.parseList()
+		return in{operand: left, values: values}, err
+	}
+	return left, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	if p.done() {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	t := p.tokens[p.pos]
+	switch {
+	case t.kind == tokenString:
+		p.pos++
+		return literal(t.text), nil
+	case t.kind == tokenIdent:
+		p.pos++
+		switch t.text {
+		case "true", "false":
+			return literal(t.text), nil
+		}
+		return variable(t.text), nil
+	case p.accept(tokenOp, "("):
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(tokenOp, ")") {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		return inner, nil
+	}
+	return nil, fmt.Errorf("unexpected %q", t.text)
+}
+
+func (p *parser) parseList() ([]string, error) {
+	if !p.accept(tokenOp, "[") {
+		return nil, fmt.Errorf("expected a list after 'in'")
+	}
+	var values []string
+	for !p.accept(tokenOp, "]") {
+		if len(values) > 0 && !p.accept(tokenOp, ",") {
+			return nil, fmt.Errorf("expected ',' or ']' in list")
+		}
+		t := p.peek()
+		if t.kind != tokenString || p.done() {
+			return nil, fmt.Errorf("lists may only contain strings")
+		}
+		p.pos++
+		values = append(values, t.text)
+	}
+	return values, nil
+}
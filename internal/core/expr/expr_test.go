@@ -0,0 +1,76 @@
+package expr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEval(t *testing.T) {
+	vars := map[string]string{
+		"stack.ci":          "GitHub Actions",
+		"stack.persistence": "None",
+		"project.type":      "typescript-express-fullstack",
+		"stack.enabled":     "true",
+		"stack.disabled":    "false",
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`stack.ci == "GitHub Actions"`, true},
+		{`stack.ci == 'Bitbucket Pipelines'`, false},
+		{`stack.persistence != "None"`, false},
+		{`stack.ci == "GitHub Actions" && stack.persistence != "None"`, false},
+		{`stack.ci == "GitHub Actions" || stack.persistence != "None"`, true},
+		{`!(stack.persistence == "None")`, false},
+		{`stack.persistence in ["PostgreSQL", "MySQL"]`, false},
+		{`stack.ci in ["GitHub Actions", "Bitbucket Pipelines"]`, true},
+		{`stack.enabled`, true},
+		{`stack.disabled`, false},
+		{`stack.missing`, false},
+		{`!stack.missing`, true},
+		{`stack.missing == ""`, true},
+		{`true && !false`, true},
+		{`a == "x" || b == "y" && c == "z"`, false},
+	}
+
+	for _, tt := range tests {
+		got, err := Eval(tt.expr, vars)
+		if err != nil {
+			t.Errorf("Eval(%q) returned an unexpected error: %v", tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Eval(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	for _, source := range []string{
+		``,
+		`stack.ci ==`,
+		`stack.ci == "unterminated`,
+		`(stack.ci == "x"`,
+		`stack.ci in "x"`,
+		`stack.ci in ["x" "y"]`,
+		`stack.ci = "x"`,
+		`stack.ci == "x" stack.cd`,
+	} {
+		if _, err := Compile(source); err == nil {
+			t.Errorf("Compile(%q) should fail", source)
+		}
+	}
+}
+
+func TestExpr_Vars(t *testing.T) {
+	e, err := Compile(`stack.ci == "x" && (project.type in ["a"] || !stack.db)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"stack.ci", "project.type", "stack.db"}
+	if got := e.Vars(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Vars() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,52 @@
+package glob
+
+import (
+	"path"
+	"strings"
+)
+
+// Match reports whether name matches the slash-separated pattern. In addition
+// to the syntax supported by path.Match, a "**" segment matches zero or more
+// path segments, so "src/**/*.ts" matches both "src/main.ts" and
+// "src/app/user.ts".
+func Match(pattern, name string) bool {
+	pattern = strings.Trim(toSlash(pattern), "/")
+	name = strings.Trim(toSlash(name), "/")
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// HasMeta reports whether pattern contains any glob metacharacters.
+func HasMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			if len(rest) == 0 {
+				return true
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(rest, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		ok, err := path.Match(pattern[0], name[0])
+		if err != nil || !ok {
+			return false
+		}
+		pattern = pattern[1:]
+		name = name[1:]
+	}
+	return len(name) == 0
+}
+
+func toSlash(p string) string {
+	return strings.ReplaceAll(p, "\\", "/")
+}
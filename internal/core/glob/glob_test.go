@@ -0,0 +1,28 @@
+package glob
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"LICENSE", "LICENSE", true},
+		{"*.md", "README.md", true},
+		{"*.md", "docs/README.md", false},
+		{".github/**", ".github/workflows/ci.yml", true},
+		{".github/**", ".github", true},
+		{"src/**/*.ts", "src/main.ts", true},
+		{"src/**/*.ts", "src/app/user/user.ts", true},
+		{"src/**/*.ts", "test/main.ts", false},
+		{"deploy/helm", "deploy/helm/Chart.yaml", false},
+		{"**/Chart.yaml", "deploy/helm/Chart.yaml", true},
+	}
+
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
@@ -1,10 +1,18 @@
 package hooks
 
 import (
+	"errors"
+	"fmt"
 	"grei-cli/internal/ports/inbound"
 	"grei-cli/internal/ports/outbound"
+	"io/fs"
+	"os"
+	"path/filepath"
 )
 
+// hooksDir is the directory of the repository that holds its Git hooks.
+const hooksDir = ".githooks"
+
 type service struct {
 	gitRepo outbound.GitRepository
 }
@@ -16,5 +24,34 @@ func NewService(gitRepo outbound.GitRepository) inbound.HooksService {
 }
 
 func (s *service) InstallHooks(path string) error {
-	return s.gitRepo.SetConfig(path, "core.hooksPath", ".githooks")
+	if err := s.gitRepo.SetConfig(path, "core.hooksPath", hooksDir); err != nil {
+		return err
+	}
+	return makeExecutable(filepath.Join(path, hooksDir))
+}
+
+// makeExecutable sets the executable bits of every hook in dir. Hooks
+// rendered from templates are written as plain files, and Git ignores hooks
+// that are not executable.
+func makeExecutable(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not read %s: %w", dir, err)
+	}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if err := os.Chmod(filepath.Join(dir, entry.Name()), info.Mode().Perm()|0111); err != nil {
+			return fmt.Errorf("could not make hook %s executable: %w", entry.Name(), err)
+		}
+	}
+	return nil
 }
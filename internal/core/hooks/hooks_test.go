@@ -3,6 +3,8 @@ package hooks
 import (
 	"errors"
 	"grei-cli/internal/ports/outbound"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -42,3 +44,25 @@ func TestInstallHooks_Error(t *testing.T) {
 		t.Error("InstallHooks() should have returned an error, but it did not")
 	}
 }
+
+func TestInstallHooks_MakesHooksExecutable(t *testing.T) {
+	path := t.TempDir()
+	hook := filepath.Join(path, ".githooks", "pre-commit")
+	if err := os.MkdirAll(filepath.Dir(hook), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewService(&mockGitRepo{}).InstallHooks(path); err != nil {
+		t.Fatalf("InstallHooks() returned an unexpected error: %v", err)
+	}
+	info, err := os.Stat(hook)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("pre-commit mode = %v, want -rwxr-xr-x", info.Mode().Perm())
+	}
+}
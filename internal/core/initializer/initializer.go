@@ -1,29 +1,39 @@
 package initializer
 
 import (
-	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"grei-cli/internal/core/recipe"
 	"grei-cli/internal/ports/inbound"
 	"grei-cli/internal/ports/outbound"
-	"grei-cli/internal/templates"
-	"path/filepath"
-	"strings"
-	"text/template"
-	"time"
+	"io/fs"
 
 	"github.com/Masterminds/semver"
 )
 
 const (
-	templatesURL    = "https://github.com/GreicodexJM/greicodex-cli.git"
-	templatesBranch = "master"
-	templatesDir    = ".grei-cli/templates"
-	cliVersion      = "0.1.0" // This should be replaced with a dynamic version
+	cliVersion = "0.1.0" // This should be replaced with a dynamic version
 )
 
+// The branch model of new projects, as in the Greicodex gitflow: releases
+// on main, day-to-day work on develop.
+const (
+	MainBranch    = "main"
+	DevelopBranch = "develop"
+	// InitialCommitMessage is the message of the commit holding the
+	// generated project.
+	InitialCommitMessage = "chore: initial project generated by grei"
+)
+
+// ErrInitialCommit is returned by SetupRepository when git cannot commit,
+// most often because no identity is configured. The generated files are
+// staged and the repository is otherwise usable.
+var ErrInitialCommit = errors.New("could not create the initial commit")
+
+// Manifest describes a template bundle.
 type Manifest struct {
+	Version    string `json:"version"`
 	MinVersion string `json:"minVersion"`
 }
 
@@ -39,9 +49,12 @@ func NewService(fsRepo outbound.FSRepository, gitRepo outbound.GitRepository) in
 	}
 }
 
-func (s *service) InitializeProject(path, cacheDir string, gitInit bool, recipe *recipe.Recipe) error {
-	templatesCacheDir := filepath.Join(cacheDir, "templates")
-	if err := s.checkVersion(templatesCacheDir); err != nil {
+func (s *service) InitializeProject(path string, templatesFS fs.FS, gitInit bool, recipe *recipe.Recipe) error {
+	manifest, err := ReadManifest(templatesFS)
+	if err != nil {
+		return err
+	}
+	if err := manifest.CheckVersion(); err != nil {
 		return err
 	}
 
@@ -49,83 +62,81 @@ func (s *service) InitializeProject(path, cacheDir string, gitInit bool, recipe
 		return err
 	}
 
-	data := templates.Data{
-		Recipe: *recipe,
-		Year:   time.Now().Year(),
+	if gitInit {
+		return s.initRepository(path)
 	}
+	return nil
+}
 
-	genericSkeletonPath := filepath.Join(cacheDir, "templates", "skeletons", "generic")
-	files, err := s.fsRepo.ReadDir(genericSkeletonPath)
+func (s *service) AdoptProject(path string, templatesFS fs.FS, gitInit bool) error {
+	manifest, err := ReadManifest(templatesFS)
 	if err != nil {
-		return fmt.Errorf("failed to read generic skeleton directory: %w", err)
+		return err
 	}
-
-	for _, file := range files {
-		if file.IsDir() {
-			continue
-		}
-
-		templatePath := filepath.Join(genericSkeletonPath, file.Name())
-		content, err := s.fsRepo.ReadFile(templatePath)
-		if err != nil {
-			return fmt.Errorf("failed to read template file: %w", err)
-		}
-
-		t, err := template.New(file.Name()).Parse(string(content))
-		if err != nil {
-			return fmt.Errorf("failed to parse template: %w", err)
-		}
-
-		var processedContent bytes.Buffer
-		if err := t.Execute(&processedContent, data); err != nil {
-			return fmt.Errorf("failed to execute template: %w", err)
-		}
-
-		destFileName := strings.TrimSuffix(file.Name(), ".tmpl")
-		err = s.fsRepo.CreateFile(filepath.Join(path, destFileName), processedContent.Bytes())
-		if err != nil {
-			return err
-		}
+	if err := manifest.CheckVersion(); err != nil {
+		return err
 	}
+	if gitInit {
+		return s.initRepository(path)
+	}
+	return nil
+}
 
-	dirsToCreate := []string{
-		"docs",
-		"deploy/helm",
-		"docs/adr",
+// initRepository creates the git repository at path with main as its
+// unborn branch; the branch is created by the first commit.
+func (s *service) initRepository(path string) error {
+	if err := s.gitRepo.Init(path); err != nil {
+		return err
 	}
+	return s.gitRepo.SetInitialBranch(path, MainBranch)
+}
 
-	for _, dir := range dirsToCreate {
-		err := s.fsRepo.CreateDir(filepath.Join(path, dir))
-		if err != nil {
+func (s *service) SetupRepository(path, remote string) error {
+	if remote != "" {
+		if err := s.AddRemote(path, remote); err != nil {
 			return err
 		}
 	}
 
-	if gitInit {
-		if err := s.gitRepo.Init(path); err != nil {
-			return err
-		}
-		if err := s.gitRepo.CreateBranch(path, "develop"); err != nil {
-			return err
-		}
+	fmt.Printf("[i] Creating the initial commit on '%s'...\n", MainBranch)
+	if err := s.gitRepo.AddAll(path); err != nil {
+		return fmt.Errorf("could not stage the generated files: %w", err)
+	}
+	if err := s.gitRepo.Commit(path, InitialCommitMessage); err != nil {
+		return fmt.Errorf("%w: %v", ErrInitialCommit, err)
 	}
+	if err := s.gitRepo.CreateBranch(path, DevelopBranch); err != nil {
+		return fmt.Errorf("could not create branch %s: %w", DevelopBranch, err)
+	}
+	fmt.Printf("[✓] Created '%s' with the generated files and '%s' from it.\n", MainBranch, DevelopBranch)
+	return nil
+}
 
+func (s *service) AddRemote(path, remote string) error {
+	fmt.Printf("[i] Adding remote 'origin' (%s)...\n", remote)
+	if err := s.gitRepo.AddRemote(path, "origin", remote); err != nil {
+		return fmt.Errorf("could not add remote origin: %w", err)
+	}
 	return nil
 }
 
-func (s *service) checkVersion(cacheDir string) error {
-	manifestPath := filepath.Join(cacheDir, "manifest.json")
-	manifestFile, err := s.fsRepo.ReadFile(manifestPath)
+// ReadManifest reads the manifest.json at the root of a template bundle.
+func ReadManifest(templatesFS fs.FS) (*Manifest, error) {
+	manifestFile, err := fs.ReadFile(templatesFS, "manifest.json")
 	if err != nil {
-		return fmt.Errorf("failed to read manifest file: %w", err)
+		return nil, fmt.Errorf("failed to read manifest file: %w", err)
 	}
 
 	var manifest Manifest
 	if err := json.Unmarshal(manifestFile, &manifest); err != nil {
-		return fmt.Errorf("failed to unmarshal manifest file: %w", err)
+		return nil, fmt.Errorf("failed to unmarshal manifest file: %w", err)
 	}
+	return &manifest, nil
+}
 
-	minVersion, err := semver.NewVersion(manifest.MinVersion)
+// CheckVersion fails when the templates require a newer CLI.
+func (m *Manifest) CheckVersion() error {
+	minVersion, err := semver.NewVersion(m.MinVersion)
 	if err != nil {
 		return fmt.Errorf("failed to parse minVersion: %w", err)
 	}
@@ -136,8 +147,22 @@ func (s *service) checkVersion(cacheDir string) error {
 	}
 
 	if currentVersion.LessThan(minVersion) {
-		return fmt.Errorf("cli version %s is less than the required minimum version %s", cliVersion, manifest.MinVersion)
+		return fmt.Errorf("cli version %s is less than the required minimum version %s", cliVersion, m.MinVersion)
 	}
 
 	return nil
 }
+
+// OlderThan reports whether the templates are an older release than other.
+// Bundles without a valid version are considered older than any release.
+func (m *Manifest) OlderThan(other *Manifest) bool {
+	otherVersion, err := semver.NewVersion(other.Version)
+	if err != nil {
+		return false
+	}
+	version, err := semver.NewVersion(m.Version)
+	if err != nil {
+		return true
+	}
+	return version.LessThan(otherVersion)
+}
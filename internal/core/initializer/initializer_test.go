@@ -2,12 +2,23 @@ package initializer
 
 import (
 	"errors"
+	"grei-cli/internal/adapters/filesystem"
 	"grei-cli/internal/core/recipe"
 	"grei-cli/internal/ports/outbound"
 	"os"
+	"strings"
 	"testing"
+	"testing/fstest"
 )
 
+// testTemplates returns a minimal template bundle with a generic skeleton.
+func testTemplates() fstest.MapFS {
+	return fstest.MapFS{
+		"manifest.json":                    {Data: []byte(`{"version": "1.0.0", "minVersion": "0.1.0"}`)},
+		"skeletons/generic/README.md.tmpl": {Data: []byte("# {{ .Project.Name }}")},
+	}
+}
+
 type mockFSRepo struct {
 	outbound.FSRepository
 	createDirErr    error
@@ -40,18 +51,44 @@ func (m *mockFSRepo) ReadDir(path string) ([]os.DirEntry, error) {
 
 type mockGitRepo struct {
 	outbound.GitRepository
-	initErr         error
-	createBranchErr error
+	initErr             error
+	createBranchErr     error
+	setInitialBranchErr error
+	commitErr           error
+	// calls records the operations run, in order.
+	calls []string
 }
 
 func (m *mockGitRepo) Init(path string) error {
+	m.calls = append(m.calls, "init")
 	return m.initErr
 }
 
 func (m *mockGitRepo) CreateBranch(path, branchName string) error {
+	m.calls = append(m.calls, "branch "+branchName)
 	return m.createBranchErr
 }
 
+func (m *mockGitRepo) SetInitialBranch(path, branchName string) error {
+	m.calls = append(m.calls, "initial branch "+branchName)
+	return m.setInitialBranchErr
+}
+
+func (m *mockGitRepo) AddAll(path string) error {
+	m.calls = append(m.calls, "add")
+	return nil
+}
+
+func (m *mockGitRepo) Commit(path, message string) error {
+	m.calls = append(m.calls, "commit "+message)
+	return m.commitErr
+}
+
+func (m *mockGitRepo) AddRemote(path, name, url string) error {
+	m.calls = append(m.calls, "remote "+name+" "+url)
+	return nil
+}
+
 func TestNewService(t *testing.T) {
 	fsRepo := &mockFSRepo{}
 	gitRepo := &mockGitRepo{}
@@ -62,37 +99,33 @@ func TestNewService(t *testing.T) {
 }
 
 func TestInitializeProject(t *testing.T) {
-	fsRepo := &mockFSRepo{
-		readFileContent: []byte(`{"minVersion": "0.1.0"}`),
-	}
+	fsRepo := &mockFSRepo{}
 	gitRepo := &mockGitRepo{}
 	service := NewService(fsRepo, gitRepo)
 
-	err := service.InitializeProject("/tmp/test-project", "/tmp/cache", true, &recipe.Recipe{})
+	err := service.InitializeProject("/tmp/test-project", testTemplates(), true, &recipe.Recipe{})
 	if err != nil {
 		t.Errorf("InitializeProject() returned an unexpected error: %v", err)
 	}
 }
 
-func TestInitializeProject_GetCacheDirError(t *testing.T) {
-	fsRepo := &mockFSRepo{cacheDirErr: errors.New("cache dir error")}
+func TestInitializeProject_MissingManifest(t *testing.T) {
+	fsRepo := &mockFSRepo{}
 	gitRepo := &mockGitRepo{}
 	service := NewService(fsRepo, gitRepo)
 
-	err := service.InitializeProject("/tmp/test-project", "/tmp/cache", true, &recipe.Recipe{})
+	err := service.InitializeProject("/tmp/test-project", fstest.MapFS{}, true, &recipe.Recipe{})
 	if err == nil {
 		t.Error("InitializeProject() should have returned an error, but it did not")
 	}
 }
 
 func TestInitializeProject_DownloadError(t *testing.T) {
-	fsRepo := &mockFSRepo{
-		readFileContent: []byte(`{"minVersion": "0.1.0"}`),
-	}
+	fsRepo := &mockFSRepo{}
 	gitRepo := &mockGitRepo{}
 	service := NewService(fsRepo, gitRepo)
 
-	err := service.InitializeProject("/tmp/test-project", "/tmp/cache", true, &recipe.Recipe{})
+	err := service.InitializeProject("/tmp/test-project", testTemplates(), true, &recipe.Recipe{})
 	if err != nil {
 		t.Errorf("InitializeProject() returned an unexpected error: %v", err)
 	}
@@ -103,54 +136,155 @@ func TestInitializeProject_CreateDirError(t *testing.T) {
 	gitRepo := &mockGitRepo{}
 	service := NewService(fsRepo, gitRepo)
 
-	err := service.InitializeProject("/tmp/test-project", "/tmp/cache", true, &recipe.Recipe{})
+	err := service.InitializeProject("/tmp/test-project", testTemplates(), true, &recipe.Recipe{})
 	if err == nil {
 		t.Error("InitializeProject() should have returned an error, but it did not")
 	}
 }
 
-func TestInitializeProject_CreateFileError(t *testing.T) {
-	fsRepo := &mockFSRepo{createFileErr: errors.New("create file error")}
-	gitRepo := &mockGitRepo{}
+func TestInitializeProject_GitInitError(t *testing.T) {
+	fsRepo := &mockFSRepo{}
+	gitRepo := &mockGitRepo{initErr: errors.New("git init error")}
 	service := NewService(fsRepo, gitRepo)
 
-	err := service.InitializeProject("/tmp/test-project", "/tmp/cache", true, &recipe.Recipe{})
+	err := service.InitializeProject("/tmp/test-project", testTemplates(), true, &recipe.Recipe{})
 	if err == nil {
 		t.Error("InitializeProject() should have returned an error, but it did not")
 	}
 }
 
-func TestInitializeProject_GitInitError(t *testing.T) {
+func TestInitializeProject_SetInitialBranchError(t *testing.T) {
 	fsRepo := &mockFSRepo{}
-	gitRepo := &mockGitRepo{initErr: errors.New("git init error")}
+	gitRepo := &mockGitRepo{setInitialBranchErr: errors.New("set initial branch error")}
 	service := NewService(fsRepo, gitRepo)
 
-	err := service.InitializeProject("/tmp/test-project", "/tmp/cache", true, &recipe.Recipe{})
+	err := service.InitializeProject("/tmp/test-project", testTemplates(), true, &recipe.Recipe{})
 	if err == nil {
 		t.Error("InitializeProject() should have returned an error, but it did not")
 	}
 }
 
-func TestInitializeProject_CreateBranchError(t *testing.T) {
+func TestInitializeProject_UnbornMain(t *testing.T) {
+	gitRepo := &mockGitRepo{}
+	service := NewService(&mockFSRepo{}, gitRepo)
+
+	if err := service.InitializeProject("/tmp/test-project", testTemplates(), true, &recipe.Recipe{}); err != nil {
+		t.Fatalf("InitializeProject() returned an unexpected error: %v", err)
+	}
+	want := "init,initial branch main"
+	if calls := strings.Join(gitRepo.calls, ","); calls != want {
+		t.Errorf("InitializeProject() ran %s, want %s", calls, want)
+	}
+}
+
+func TestSetupRepository(t *testing.T) {
+	gitRepo := &mockGitRepo{}
+	service := NewService(&mockFSRepo{}, gitRepo)
+
+	if err := service.SetupRepository("/tmp/test-project", "git@example.com:acme/web.git"); err != nil {
+		t.Fatalf("SetupRepository() returned an unexpected error: %v", err)
+	}
+	want := "remote origin git@example.com:acme/web.git,add,commit " + InitialCommitMessage + ",branch develop"
+	if calls := strings.Join(gitRepo.calls, ","); calls != want {
+		t.Errorf("SetupRepository() ran %s, want %s", calls, want)
+	}
+}
+
+func TestSetupRepository_CommitError(t *testing.T) {
+	gitRepo := &mockGitRepo{commitErr: errors.New("git commit: Please tell me who you are.")}
+	service := NewService(&mockFSRepo{}, gitRepo)
+
+	err := service.SetupRepository("/tmp/test-project", "")
+	if !errors.Is(err, ErrInitialCommit) || !strings.Contains(err.Error(), "Please tell me who you are.") {
+		t.Errorf("SetupRepository() = %v, want ErrInitialCommit with the reason", err)
+	}
+	if calls := strings.Join(gitRepo.calls, ","); strings.Contains(calls, "branch develop") {
+		t.Errorf("SetupRepository() ran %s, want develop left out without a commit", calls)
+	}
+}
+
+func TestInitializeProject_NoGitInit(t *testing.T) {
 	fsRepo := &mockFSRepo{}
-	gitRepo := &mockGitRepo{createBranchErr: errors.New("create branch error")}
+	gitRepo := &mockGitRepo{}
 	service := NewService(fsRepo, gitRepo)
 
-	err := service.InitializeProject("/tmp/test-project", "/tmp/cache", true, &recipe.Recipe{})
+	err := service.InitializeProject("/tmp/test-project", testTemplates(), false, &recipe.Recipe{})
+	if err != nil {
+		t.Errorf("InitializeProject() returned an unexpected error: %v", err)
+	}
+}
+
+func TestInitializeProject_IncompatibleTemplates(t *testing.T) {
+	templates := testTemplates()
+	templates["manifest.json"] = &fstest.MapFile{Data: []byte(`{"version": "9.0.0", "minVersion": "9.0.0"}`)}
+	service := NewService(&mockFSRepo{}, &mockGitRepo{})
+
+	err := service.InitializeProject("/tmp/test-project", templates, false, &recipe.Recipe{})
 	if err == nil {
-		t.Error("InitializeProject() should have returned an error, but it did not")
+		t.Error("InitializeProject() should have rejected templates requiring a newer CLI")
 	}
 }
 
-func TestInitializeProject_NoGitInit(t *testing.T) {
-	fsRepo := &mockFSRepo{
-		readFileContent: []byte(`{"minVersion": "0.1.0"}`),
+func TestManifest_OlderThan(t *testing.T) {
+	tests := []struct {
+		version, other string
+		want           bool
+	}{
+		{"1.0.0", "1.1.0", true},
+		{"1.1.0", "1.0.0", false},
+		{"1.0.0", "1.0.0", false},
+		{"", "1.0.0", true},
+		{"1.0.0", "", false},
+	}
+	for _, tt := range tests {
+		m := &Manifest{Version: tt.version}
+		if got := m.OlderThan(&Manifest{Version: tt.other}); got != tt.want {
+			t.Errorf("Manifest{%q}.OlderThan(%q) = %v, want %v", tt.version, tt.other, got, tt.want)
+		}
+	}
+}
+
+func TestInitializeProject_RendersNothing(t *testing.T) {
+	fsRepo := filesystem.NewMemoryRepository()
+	service := NewService(fsRepo, &mockGitRepo{})
+
+	err := service.InitializeProject("project", testTemplates(), false, &recipe.Recipe{})
+	if err != nil {
+		t.Fatalf("InitializeProject() returned an unexpected error: %v", err)
+	}
+	if files := fsRepo.Files(); len(files) != 0 {
+		t.Errorf("Templates are rendered by the scaffolder, but the initializer wrote %v", files)
 	}
+	// The layers create docs/ and deploy/ when they ship files for them.
+	if dirs := fsRepo.Dirs(); len(dirs) != 1 || dirs[0] != "project" {
+		t.Errorf("Expected only the project directory to be created, got %v", dirs)
+	}
+}
+
+func TestAddRemote(t *testing.T) {
+	gitRepo := &mockGitRepo{}
+	service := NewService(&mockFSRepo{}, gitRepo)
+
+	if err := service.AddRemote("/tmp/test-project", "git@example.com:acme/web.git"); err != nil {
+		t.Fatalf("AddRemote() returned an unexpected error: %v", err)
+	}
+	want := "remote origin git@example.com:acme/web.git"
+	if calls := strings.Join(gitRepo.calls, ","); calls != want {
+		t.Errorf("AddRemote() ran %s, want %s", calls, want)
+	}
+}
+
+func TestAdoptProject_CreatesNoDirectories(t *testing.T) {
+	// Any directory creation would fail.
+	fsRepo := &mockFSRepo{createDirErr: errors.New("unexpected directory")}
 	gitRepo := &mockGitRepo{}
 	service := NewService(fsRepo, gitRepo)
 
-	err := service.InitializeProject("/tmp/test-project", "/tmp/cache", false, &recipe.Recipe{})
-	if err != nil {
-		t.Errorf("InitializeProject() returned an unexpected error: %v", err)
+	if err := service.AdoptProject("/tmp/legacy", testTemplates(), true); err != nil {
+		t.Fatalf("AdoptProject() returned an unexpected error: %v", err)
+	}
+	want := "init,initial branch main"
+	if calls := strings.Join(gitRepo.calls, ","); calls != want {
+		t.Errorf("AdoptProject() ran %s, want %s", calls, want)
 	}
 }
@@ -0,0 +1,45 @@
+package policy
+
+import "fmt"
+
+// Architecture declares the layers of a stack and the imports allowed
+// between them.
+type Architecture struct {
+	Layers []Layer `yaml:"layers,omitempty" json:"layers,omitempty"`
+}
+
+// Layer is a set of source paths with the same dependency rules. A file
+// belongs to the first layer whose paths match it, so more specific layers
+// (e.g. ports inside application) must be declared first.
+type Layer struct {
+	Name string `yaml:"name" json:"name"`
+	// Paths are glob patterns relative to the project root.
+	Paths []string `yaml:"paths" json:"paths"`
+	// Allow lists the other layers this layer may import.
+	Allow []string `yaml:"allow,omitempty" json:"allow,omitempty"`
+	// Forbid lists external packages this layer must not import, e.g.
+	// "express", "@nestjs/*", "github.com/gin-gonic/gin" or "Symfony\**".
+	Forbid []string `yaml:"forbid,omitempty" json:"forbid,omitempty"`
+}
+
+// Validate checks that layer names are unique and that allowed layers exist.
+func (a Architecture) Validate() error {
+	names := make(map[string]bool, len(a.Layers))
+	for _, layer := range a.Layers {
+		if layer.Name == "" || len(layer.Paths) == 0 {
+			return fmt.Errorf("architecture layers need a name and at least one path")
+		}
+		if names[layer.Name] {
+			return fmt.Errorf("duplicate architecture layer %q", layer.Name)
+		}
+		names[layer.Name] = true
+	}
+	for _, layer := range a.Layers {
+		for _, allowed := range layer.Allow {
+			if !names[allowed] {
+				return fmt.Errorf("layer %q allows unknown layer %q", layer.Name, allowed)
+			}
+		}
+	}
+	return nil
+}
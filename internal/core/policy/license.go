@@ -0,0 +1,82 @@
+package policy
+
+import (
+	"regexp"
+	"strings"
+)
+
+// headLength is how much of the normalized text is searched for a license
+// title. Long licenses mention their siblings in the body (GPL-3.0 refers to
+// the AGPL and the LGPL), so titles are only trusted near the top.
+const headLength = 400
+
+// licenseSignature identifies a license by phrases found in its canonical
+// text. Every head phrase must appear near the top and every body phrase
+// anywhere in the file.
+type licenseSignature struct {
+	id   string
+	head []string
+	body []string
+}
+
+// Signatures are tried in order, so more specific texts come first (BSD-3
+// contains all of BSD-2).
+var licenseSignatures = []licenseSignature{
+	{id: "Apache-2.0", head: []string{"apache license", "version 2.0"}},
+	{id: "AGPL-3.0", head: []string{"gnu affero general public license", "version 3"}},
+	{id: "LGPL-3.0", head: []string{"gnu lesser general public license", "version 3"}},
+	{id: "LGPL-2.1", head: []string{"gnu lesser general public license", "version 2.1"}},
+	{id: "GPL-3.0", head: []string{"gnu general public license", "version 3"}},
+	{id: "GPL-2.0", head: []string{"gnu general public license", "version 2"}},
+	{id: "MPL-2.0", head: []string{"mozilla public license", "2.0"}},
+	{id: "EPL-2.0", head: []string{"eclipse public license", "2.0"}},
+	{id: "MIT", body: []string{"permission is hereby granted, free of charge"}},
+	{id: "BSD-3-Clause", body: []string{"redistribution and use in source and binary forms", "neither the name"}},
+	{id: "BSD-2-Clause", body: []string{"redistribution and use in source and binary forms"}},
+	{id: "ISC", body: []string{"permission to use, copy, modify, and/or distribute this software"}},
+	{id: "Unlicense", body: []string{"this is free and unencumbered software released into the public domain"}},
+}
+
+var spdxIdentifier = regexp.MustCompile(`SPDX-License-Identifier:\s*([A-Za-z0-9.\-+]+)`)
+
+// DetectLicense returns the SPDX identifier of the license in content, or an
+// empty string when it cannot be recognized. An explicit
+// "SPDX-License-Identifier" tag takes precedence over the text heuristics.
+func DetectLicense(content []byte) string {
+	if m := spdxIdentifier.FindSubmatch(content); m != nil {
+		return string(m[1])
+	}
+
+	text := strings.Join(strings.Fields(strings.ToLower(string(content))), " ")
+	head := text
+	if len(head) > headLength {
+		head = head[:headLength]
+	}
+
+	for _, sig := range licenseSignatures {
+		if containsAll(head, sig.head) && containsAll(text, sig.body) {
+			return sig.id
+		}
+	}
+	return ""
+}
+
+// IsKnownLicense reports whether id is one of the SPDX identifiers that
+// DetectLicense can recognize from the license text.
+func IsKnownLicense(id string) bool {
+	for _, sig := range licenseSignatures {
+		if strings.EqualFold(sig.id, id) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAll(text string, phrases []string) bool {
+	for _, phrase := range phrases {
+		if !strings.Contains(text, phrase) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,215 @@
+package policy
+
+import (
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Names of the checks a policy can make mandatory. A mandatory check fails
+// verification instead of being skipped when it cannot run.
+const (
+	CheckLinter       = "linter"
+	CheckSecrets      = "secrets"
+	CheckCoverage     = "coverage"
+	CheckPersistence  = "persistence"
+	CheckDeployment   = "deployment"
+	CheckRequired     = "required"
+	CheckDependencies = "dependencies"
+	// CheckVulnerabilities fails when no vulnerability scanner can run.
+	CheckVulnerabilities = "vulnerabilities"
+	// CheckArchitecture fails when the stack declares no layer rules.
+	CheckArchitecture = "architecture"
+)
+
+var knownChecks = map[string]bool{
+	CheckLinter:          true,
+	CheckSecrets:         true,
+	CheckCoverage:        true,
+	CheckPersistence:     true,
+	CheckDeployment:      true,
+	CheckRequired:        true,
+	CheckDependencies:    true,
+	CheckVulnerabilities: true,
+	CheckArchitecture:    true,
+}
+
+// Rules are the verification settings shared by organization policy packs
+// and the `verify` section of a project recipe.
+type Rules struct {
+	// Coverage is the minimum test coverage percentage.
+	Coverage           int                `yaml:"coverage,omitempty" json:"coverage,omitempty"`
+	Required           []Requirement      `yaml:"required,omitempty" json:"required,omitempty"`
+	BannedDependencies []BannedDependency `yaml:"bannedDependencies,omitempty" json:"bannedDependencies,omitempty"`
+	// PinDependencies requires exact dependency versions and a lockfile in
+	// sync with every manifest.
+	PinDependencies bool     `yaml:"pinDependencies,omitempty" json:"pinDependencies,omitempty"`
+	Checks          []string `yaml:"checks,omitempty" json:"checks,omitempty"`
+}
+
+// BannedDependency is a dependency that must not appear in a project.
+type BannedDependency struct {
+	// Name is the package name; glob patterns such as "@angular/*" are allowed.
+	Name string `yaml:"name" json:"name"`
+	// Ecosystem restricts the ban to one package manager (npm, composer,
+	// go, pypi). Empty means every ecosystem.
+	Ecosystem string `yaml:"ecosystem,omitempty" json:"ecosystem,omitempty"`
+	Reason    string `yaml:"reason,omitempty" json:"reason,omitempty"`
+}
+
+// Pack is an organization-wide policy file, usually synced with the templates
+// under templates/policies.
+type Pack struct {
+	Name        string `yaml:"name"`
+	Version     string `yaml:"version"`
+	Description string `yaml:"description"`
+	// AppliesTo lists the stack names or stack types the pack governs. "*"
+	// makes it the fallback for every project.
+	AppliesTo []string `yaml:"appliesTo"`
+	Rules     `yaml:",inline"`
+}
+
+// ParsePack decodes and validates a policy pack.
+func ParsePack(data []byte) (*Pack, error) {
+	var pack Pack
+	if err := yaml.Unmarshal(data, &pack); err != nil {
+		return nil, err
+	}
+	if pack.Name == "" {
+		return nil, fmt.Errorf("policy pack has no name")
+	}
+	if err := pack.Rules.Validate(); err != nil {
+		return nil, fmt.Errorf("policy pack %q: %w", pack.Name, err)
+	}
+	return &pack, nil
+}
+
+// Validate checks the requirements and check names of the rules.
+func (r Rules) Validate() error {
+	if r.Coverage < 0 || r.Coverage > 100 {
+		return fmt.Errorf("coverage must be between 0 and 100, got %d", r.Coverage)
+	}
+	for _, req := range r.Required {
+		if err := req.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, check := range r.Checks {
+		if !knownChecks[check] {
+			return fmt.Errorf("unknown check %q", check)
+		}
+	}
+	return nil
+}
+
+// Select returns the pack that governs a stack, preferring a pack that names
+// the stack over one that names its type, and either over a "*" fallback.
+func Select(packs []*Pack, stackName, stackType string) *Pack {
+	var best *Pack
+	bestScore := 0
+	for _, pack := range packs {
+		score := 0
+		for _, target := range pack.AppliesTo {
+			switch {
+			case target == stackName && stackName != "":
+				score = max(score, 3)
+			case target == stackType && stackType != "":
+				score = max(score, 2)
+			case target == "*":
+				score = max(score, 1)
+			}
+		}
+		if score > bestScore {
+			best, bestScore = pack, score
+		}
+	}
+	return best
+}
+
+// Resolve layers the stack's required paths, the organization pack and the
+// project's own rules. Projects may tighten the pack but never loosen it: a
+// lower coverage, or ignoring or weakening a pack requirement, is an error.
+// pack may be nil when no policy applies.
+func Resolve(base []Requirement, pack *Pack, project Rules) (Rules, error) {
+	if err := project.Validate(); err != nil {
+		return Rules{}, err
+	}
+
+	resolved := Rules{Required: base}
+	if pack != nil {
+		resolved.Coverage = pack.Coverage
+		resolved.Required = Merge(resolved.Required, pack.Required)
+		resolved.BannedDependencies = append(resolved.BannedDependencies, pack.BannedDependencies...)
+		resolved.PinDependencies = pack.PinDependencies
+		resolved.Checks = append(resolved.Checks, pack.Checks...)
+
+		if project.Coverage != 0 && project.Coverage < pack.Coverage {
+			return Rules{}, fmt.Errorf("coverage %d%% is below the %d%% required by policy %q", project.Coverage, pack.Coverage, pack.Name)
+		}
+		if err := checkTightens(pack, project.Required); err != nil {
+			return Rules{}, err
+		}
+	}
+
+	resolved.Coverage = max(resolved.Coverage, project.Coverage)
+	resolved.Required = Merge(resolved.Required, project.Required)
+	resolved.BannedDependencies = append(resolved.BannedDependencies, project.BannedDependencies...)
+	resolved.PinDependencies = resolved.PinDependencies || project.PinDependencies
+	resolved.Checks = union(resolved.Checks, project.Checks)
+	return resolved, nil
+}
+
+// IsMandatory reports whether check is listed in the rules.
+func (r Rules) IsMandatory(check string) bool {
+	for _, c := range r.Checks {
+		if c == check {
+			return true
+		}
+	}
+	return false
+}
+
+func checkTightens(pack *Pack, overrides []Requirement) error {
+	inherited := make(map[string]Requirement, len(pack.Required))
+	for _, r := range pack.Required {
+		inherited[r.Key()] = r
+	}
+
+	for _, o := range overrides {
+		r, ok := inherited[o.Key()]
+		if !ok {
+			continue
+		}
+		if o.Ignore || r.NotEmpty && !o.NotEmpty || o.MinBytes < r.MinBytes || !licenseTightens(r.License, o.License) {
+			return fmt.Errorf("requirement %q cannot loosen policy %q", o.Key(), pack.Name)
+		}
+	}
+	return nil
+}
+
+// licenseTightens reports whether the override license rule is at least as
+// strict as the inherited one: "" < "any" < a specific SPDX identifier.
+func licenseTightens(inherited, override string) bool {
+	switch inherited {
+	case "":
+		return true
+	case "any":
+		return override != ""
+	default:
+		return override == inherited
+	}
+}
+
+func union(a, b []string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	var result []string
+	for _, s := range append(append([]string{}, a...), b...) {
+		if !seen[s] {
+			seen[s] = true
+			result = append(result, s)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
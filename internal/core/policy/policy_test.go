@@ -0,0 +1,143 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectLicense(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"MIT", "MIT License\n\nCopyright (c) 2025 Greicodex\n\nPermission is hereby granted, free of charge, to any person", "MIT"},
+		{"Apache", "                                 Apache License\n                           Version 2.0, January 2004", "Apache-2.0"},
+		{"GPL-3 mentions AGPL", "GNU GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007\n\n" + strings.Repeat("terms and conditions ", 30) + "the GNU Affero General Public License", "GPL-3.0"},
+		{"SPDX tag", "SPDX-License-Identifier: MPL-2.0\n", "MPL-2.0"},
+		{"unknown", "All rights reserved.", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectLicense([]byte(tt.content)); got != tt.want {
+				t.Errorf("DetectLicense() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	base := []Requirement{
+		{Path: "LICENSE"},
+		{Path: "CONTRIBUTING.md"},
+		{Path: "deploy/helm"},
+	}
+	overrides := []Requirement{
+		{Path: "LICENSE", License: "any"},
+		{Path: "deploy/helm", Ignore: true},
+		{OneOf: []string{"netlify.toml", "Dockerfile"}},
+	}
+
+	merged := Merge(base, overrides)
+
+	if len(merged) != 3 {
+		t.Fatalf("Expected 3 requirements, got %d: %+v", len(merged), merged)
+	}
+	if merged[0].License != "any" {
+		t.Errorf("Expected LICENSE requirement to be replaced, got %+v", merged[0])
+	}
+	if merged[1].Path != "CONTRIBUTING.md" {
+		t.Errorf("Expected CONTRIBUTING.md to keep its position, got %+v", merged[1])
+	}
+	if merged[2].Key() != "netlify.toml|Dockerfile" {
+		t.Errorf("Expected new requirement to be appended, got %+v", merged[2])
+	}
+}
+
+func TestRequirementValidate(t *testing.T) {
+	if err := (Requirement{Path: "LICENSE", Glob: "*.md"}).Validate(); err == nil {
+		t.Error("Expected an error when both path and glob are set")
+	}
+	if err := (Requirement{Path: "LICENSE", License: "WTFPL-9"}).Validate(); err == nil {
+		t.Error("Expected an error for an unknown license")
+	}
+	if err := (Requirement{Path: "LICENSE", License: "any"}).Validate(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	fallback := &Pack{Name: "default", AppliesTo: []string{"*"}}
+	spa := &Pack{Name: "spa", AppliesTo: []string{"spa"}}
+	angular := &Pack{Name: "angular", AppliesTo: []string{"typescript-angular"}}
+	packs := []*Pack{fallback, spa, angular}
+
+	if got := Select(packs, "typescript-angular", "spa"); got != angular {
+		t.Errorf("Expected the stack-specific pack, got %v", got)
+	}
+	if got := Select(packs, "typescript-vuejs", "spa"); got != spa {
+		t.Errorf("Expected the type pack, got %v", got)
+	}
+	if got := Select(packs, "golang-cli", "code"); got != fallback {
+		t.Errorf("Expected the fallback pack, got %v", got)
+	}
+	if got := Select([]*Pack{spa}, "golang-cli", "code"); got != nil {
+		t.Errorf("Expected no pack, got %v", got)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	pack := &Pack{
+		Name: "web",
+		Rules: Rules{
+			Coverage:           80,
+			Required:           []Requirement{{Path: "LICENSE", License: "any"}},
+			BannedDependencies: []BannedDependency{{Name: "moment"}},
+			PinDependencies:    true,
+			Checks:             []string{CheckSecrets},
+		},
+	}
+
+	t.Run("project tightens the pack", func(t *testing.T) {
+		rules, err := Resolve([]Requirement{{Path: "README.md"}}, pack, Rules{
+			Coverage: 90,
+			Required: []Requirement{{Path: "LICENSE", License: "MIT"}},
+			Checks:   []string{CheckLinter},
+		})
+		if err != nil {
+			t.Fatalf("Resolve() returned an unexpected error: %v", err)
+		}
+		if rules.Coverage != 90 {
+			t.Errorf("Expected coverage 90, got %d", rules.Coverage)
+		}
+		if len(rules.Required) != 2 || rules.Required[1].License != "MIT" {
+			t.Errorf("Unexpected required paths: %+v", rules.Required)
+		}
+		if !rules.IsMandatory(CheckSecrets) || !rules.IsMandatory(CheckLinter) {
+			t.Errorf("Expected both checks to be mandatory, got %v", rules.Checks)
+		}
+		if len(rules.BannedDependencies) != 1 {
+			t.Errorf("Expected the pack's banned dependencies, got %v", rules.BannedDependencies)
+		}
+		if !rules.PinDependencies {
+			t.Error("Expected the pack's pinDependencies rule to apply")
+		}
+	})
+
+	loosening := []struct {
+		name    string
+		project Rules
+	}{
+		{"lower coverage", Rules{Coverage: 70}},
+		{"ignored requirement", Rules{Required: []Requirement{{Path: "LICENSE", Ignore: true}}}},
+		{"weaker requirement", Rules{Required: []Requirement{{Path: "LICENSE"}}}},
+	}
+	for _, tt := range loosening {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Resolve(nil, pack, tt.project); err == nil {
+				t.Error("Expected an error when loosening the policy, but got none")
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Requirement describes a file or directory a project must contain. Exactly
+// one of Path, OneOf or Glob identifies the candidates; the remaining fields
+// add content checks that apply to every matching file.
+type Requirement struct {
+	// Path is a single file or directory, relative to the project root.
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
+	// OneOf is satisfied when any of the listed alternatives exists.
+	OneOf []string `yaml:"oneOf,omitempty" json:"oneOf,omitempty"`
+	// Glob is satisfied when at least one path matches the pattern.
+	Glob string `yaml:"glob,omitempty" json:"glob,omitempty"`
+	// NotEmpty requires the file to contain something besides whitespace.
+	NotEmpty bool `yaml:"notEmpty,omitempty" json:"notEmpty,omitempty"`
+	// MinBytes requires the file to be at least this many bytes long.
+	MinBytes int `yaml:"minBytes,omitempty" json:"minBytes,omitempty"`
+	// License requires the file to contain a known license. Use "any" to
+	// accept every SPDX identifier known to DetectLicense.
+	License string `yaml:"license,omitempty" json:"license,omitempty"`
+	// Ignore drops an inherited requirement with the same key.
+	Ignore bool `yaml:"ignore,omitempty" json:"ignore,omitempty"`
+}
+
+// DefaultRequirements is the base list when the stack manifest declares no
+// list of its own. Project overrides are merged on top of it.
+var DefaultRequirements = []Requirement{
+	{Path: "LICENSE"},
+	{Path: "CONTRIBUTING.md"},
+}
+
+// Key identifies a requirement so that later layers can replace it.
+func (r Requirement) Key() string {
+	switch {
+	case r.Path != "":
+		return r.Path
+	case len(r.OneOf) > 0:
+		return strings.Join(r.OneOf, "|")
+	default:
+		return r.Glob
+	}
+}
+
+// Candidates returns the paths or patterns that can satisfy the requirement.
+func (r Requirement) Candidates() []string {
+	switch {
+	case r.Path != "":
+		return []string{r.Path}
+	case len(r.OneOf) > 0:
+		return r.OneOf
+	case r.Glob != "":
+		return []string{r.Glob}
+	}
+	return nil
+}
+
+// Validate checks that the requirement identifies its candidates unambiguously.
+func (r Requirement) Validate() error {
+	set := 0
+	if r.Path != "" {
+		set++
+	}
+	if len(r.OneOf) > 0 {
+		set++
+	}
+	if r.Glob != "" {
+		set++
+	}
+	if set != 1 {
+		return fmt.Errorf("requirement must set exactly one of path, oneOf or glob")
+	}
+	if r.License != "" && r.License != "any" && !IsKnownLicense(r.License) {
+		return fmt.Errorf("requirement %q: unknown SPDX license %q", r.Key(), r.License)
+	}
+	return nil
+}
+
+func (r Requirement) String() string {
+	switch {
+	case len(r.OneOf) > 0:
+		return "one of " + strings.Join(r.OneOf, ", ")
+	case r.Glob != "":
+		return r.Glob
+	}
+	return r.Path
+}
+
+// Merge applies overrides on top of base. An override replaces the base
+// requirement with the same key, or removes it when Ignore is set; overrides
+// with new keys are appended. The order of base is preserved.
+func Merge(base, overrides []Requirement) []Requirement {
+	index := make(map[string]int, len(base))
+	merged := make([]Requirement, 0, len(base)+len(overrides))
+	for _, r := range base {
+		index[r.Key()] = len(merged)
+		merged = append(merged, r)
+	}
+	for _, r := range overrides {
+		if i, ok := index[r.Key()]; ok {
+			merged[i] = r
+			continue
+		}
+		index[r.Key()] = len(merged)
+		merged = append(merged, r)
+	}
+
+	result := merged[:0]
+	for _, r := range merged {
+		if !r.Ignore {
+			result = append(result, r)
+		}
+	}
+	return result
+}
@@ -0,0 +1,97 @@
+// Package posthooks runs the post-generate steps of a new project, such as
+// installing its dependencies, once its files are rendered.
+package posthooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"grei-cli/internal/ports/inbound"
+	"grei-cli/internal/ports/outbound"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type service struct {
+	runner outbound.CommandRunner
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// NewService creates the service. The output of the steps is streamed to
+// stdout and stderr.
+func NewService(runner outbound.CommandRunner, stdout, stderr io.Writer) inbound.PostHooksService {
+	return &service{
+		runner: runner,
+		stdout: stdout,
+		stderr: stderr,
+	}
+}
+
+func (s *service) RunPostHooks(path string, steps []inbound.PostStep, timeout time.Duration) error {
+	if len(steps) == 0 {
+		return nil
+	}
+	fmt.Fprintf(s.stdout, "\n[i] Running %d post-generate step(s)...\n", len(steps))
+
+	for i, step := range steps {
+		dir := filepath.Join(path, filepath.FromSlash(step.Dir))
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			fmt.Fprintf(s.stdout, "[✗] %s: directory %s does not exist\n", step.Name, step.Dir)
+			return &StepError{Step: step, Index: i, Err: fmt.Errorf("directory %s does not exist", step.Dir)}
+		}
+
+		limit := step.Timeout
+		if limit == 0 {
+			limit = timeout
+		}
+		fmt.Fprintf(s.stdout, "[i] [%d/%d] %s (in %s)\n", i+1, len(steps), step.Name, step.Dir)
+		if step.Name != step.Run {
+			fmt.Fprintf(s.stdout, "    $ %s\n", step.Run)
+		}
+
+		start := time.Now()
+		err := s.run(dir, step.Run, limit)
+		elapsed := time.Since(start).Round(100 * time.Millisecond)
+		if err != nil {
+			fmt.Fprintf(s.stdout, "[✗] %s failed after %s: %v\n", step.Name, elapsed, err)
+			return &StepError{Step: step, Index: i, Err: err}
+		}
+		fmt.Fprintf(s.stdout, "[✓] %s (%s)\n", step.Name, elapsed)
+	}
+	return nil
+}
+
+// run runs command in dir, stopping it after limit when limit is set.
+func (s *service) run(dir, command string, limit time.Duration) error {
+	ctx := context.Background()
+	if limit > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, limit)
+		defer cancel()
+	}
+	err := s.runner.Run(ctx, dir, command, s.stdout, s.stderr)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", limit)
+	}
+	return err
+}
+
+// StepError reports the post-generate step that failed. The steps before it
+// succeeded; the steps after it were not run.
+type StepError struct {
+	Step inbound.PostStep
+	// Index is the position of the step, from 0.
+	Index int
+	Err   error
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("post-generate step %q failed in %s: %v", e.Step.Name, e.Step.Dir, e.Err)
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
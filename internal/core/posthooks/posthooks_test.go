@@ -0,0 +1,120 @@
+package posthooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"grei-cli/internal/ports/inbound"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type call struct {
+	dir      string
+	command  string
+	deadline bool
+}
+
+type fakeRunner struct {
+	calls []call
+	// fail makes the command with that text fail.
+	fail string
+	// hang makes the command with that text run until its context is done.
+	hang string
+}
+
+func (r *fakeRunner) Run(ctx context.Context, dir, command string, stdout, stderr io.Writer) error {
+	_, deadline := ctx.Deadline()
+	r.calls = append(r.calls, call{dir: dir, command: command, deadline: deadline})
+	io.WriteString(stdout, "output of "+command+"\n")
+	switch command {
+	case r.fail:
+		return errors.New("exit status 1")
+	case r.hang:
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func TestRunPostHooks(t *testing.T) {
+	path := t.TempDir()
+	if err := os.Mkdir(filepath.Join(path, "web"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	runner := &fakeRunner{}
+	var out bytes.Buffer
+
+	err := NewService(runner, &out, &out).RunPostHooks(path, []inbound.PostStep{
+		{Name: "go mod tidy", Run: "go mod tidy", Dir: "."},
+		{Name: "Install the SPA", Run: "npm install", Dir: "web", Timeout: time.Minute},
+	}, 0)
+	if err != nil {
+		t.Fatalf("RunPostHooks() returned an unexpected error: %v", err)
+	}
+
+	want := []call{
+		{dir: path, command: "go mod tidy", deadline: false},
+		{dir: filepath.Join(path, "web"), command: "npm install", deadline: true},
+	}
+	if len(runner.calls) != len(want) {
+		t.Fatalf("ran %+v, want %+v", runner.calls, want)
+	}
+	for i := range want {
+		if runner.calls[i] != want[i] {
+			t.Errorf("call %d = %+v, want %+v", i, runner.calls[i], want[i])
+		}
+	}
+	for _, line := range []string{"[1/2] go mod tidy (in .)", "output of go mod tidy", "[2/2] Install the SPA (in web)", "$ npm install", "[✓] Install the SPA"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("output does not contain %q:\n%s", line, out.String())
+		}
+	}
+}
+
+func TestRunPostHooks_StopsAtFailure(t *testing.T) {
+	runner := &fakeRunner{fail: "npm install"}
+	var out bytes.Buffer
+
+	err := NewService(runner, &out, &out).RunPostHooks(t.TempDir(), []inbound.PostStep{
+		{Name: "npm install", Run: "npm install", Dir: "."},
+		{Name: "git add --all", Run: "git add --all", Dir: "."},
+	}, time.Minute)
+
+	var stepErr *StepError
+	if !errors.As(err, &stepErr) || stepErr.Index != 0 || stepErr.Step.Run != "npm install" {
+		t.Fatalf("RunPostHooks() = %v, want a StepError for npm install", err)
+	}
+	if len(runner.calls) != 1 {
+		t.Errorf("ran %+v, want the steps after the failure skipped", runner.calls)
+	}
+	if !strings.Contains(out.String(), "[✗] npm install failed after") {
+		t.Errorf("output does not report the failure:\n%s", out.String())
+	}
+}
+
+func TestRunPostHooks_Timeout(t *testing.T) {
+	runner := &fakeRunner{hang: "npm install"}
+
+	err := NewService(runner, io.Discard, io.Discard).RunPostHooks(t.TempDir(), []inbound.PostStep{
+		{Name: "npm install", Run: "npm install", Dir: ".", Timeout: 10 * time.Millisecond},
+	}, time.Hour)
+	if err == nil || !strings.Contains(err.Error(), "timed out after 10ms") {
+		t.Errorf("RunPostHooks() = %v, want a timeout", err)
+	}
+}
+
+func TestRunPostHooks_MissingDir(t *testing.T) {
+	runner := &fakeRunner{}
+
+	err := NewService(runner, io.Discard, io.Discard).RunPostHooks(t.TempDir(), []inbound.PostStep{
+		{Name: "npm install", Run: "npm install", Dir: "web"},
+	}, time.Minute)
+	if err == nil || !strings.Contains(err.Error(), "directory web does not exist") || len(runner.calls) != 0 {
+		t.Errorf("RunPostHooks() = %v after %+v, want a missing directory error", err, runner.calls)
+	}
+}
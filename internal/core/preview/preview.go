@@ -0,0 +1,132 @@
+package preview
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"grei-cli/internal/core/diff"
+	"grei-cli/internal/ports/inbound"
+	"grei-cli/internal/ports/outbound"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var markers = map[string]string{
+	inbound.FileNew:       "+",
+	inbound.FileModified:  "~",
+	inbound.FileUnchanged: "=",
+}
+
+type service struct {
+	fsRepo outbound.FSRepository
+}
+
+func NewService(fsRepo outbound.FSRepository) inbound.PreviewService {
+	return &service{
+		fsRepo: fsRepo,
+	}
+}
+
+func (s *service) Preview(options inbound.PreviewOptions) (*inbound.PreviewReport, error) {
+	report := &inbound.PreviewReport{States: make(map[string]string)}
+	current := make(map[string][]byte)
+
+	paths := make([]string, 0, len(options.Files))
+	for path := range options.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		existing, err := s.fsRepo.ReadFile(filepath.Join(options.Path, filepath.FromSlash(path)))
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			report.States[path] = inbound.FileNew
+			report.New++
+		case err != nil:
+			return nil, fmt.Errorf("could not read %s: %w", path, err)
+		case bytes.Equal(existing, options.Files[path]):
+			report.States[path] = inbound.FileUnchanged
+			report.Unchanged++
+		default:
+			report.States[path] = inbound.FileModified
+			report.Modified++
+			current[path] = existing
+		}
+	}
+
+	fmt.Printf("\n[i] Dry run: nothing was written to '%s'.\n", options.Path)
+	fmt.Println("    + new   ~ modified   = unchanged")
+	printTree(buildTree(paths, options.Dirs), report.States, "")
+	fmt.Printf("\n[i] %d files would be created, %d modified and %d left unchanged.\n", report.New, report.Modified, report.Unchanged)
+
+	for _, path := range paths {
+		if report.States[path] == inbound.FileModified {
+			fmt.Println()
+			fmt.Print(diff.Unified("a/"+path, "b/"+path, current[path], options.Files[path]))
+		}
+	}
+	return report, nil
+}
+
+// node is a directory of the preview tree. Files have no children.
+type node struct {
+	name     string
+	path     string
+	isDir    bool
+	children map[string]*node
+}
+
+func buildTree(files, dirs []string) *node {
+	root := &node{isDir: true, children: make(map[string]*node)}
+	add := func(path string, isDir bool) {
+		current := root
+		parts := strings.Split(path, "/")
+		for i, part := range parts {
+			child, ok := current.children[part]
+			if !ok {
+				child = &node{
+					name:     part,
+					path:     strings.Join(parts[:i+1], "/"),
+					isDir:    isDir || i < len(parts)-1,
+					children: make(map[string]*node),
+				}
+				current.children[part] = child
+			}
+			current = child
+		}
+	}
+	for _, dir := range dirs {
+		if dir != "." && dir != "" {
+			add(dir, true)
+		}
+	}
+	for _, file := range files {
+		add(file, false)
+	}
+	return root
+}
+
+func printTree(dir *node, states map[string]string, indent string) {
+	names := make([]string, 0, len(dir.children))
+	for name := range dir.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		child := dir.children[name]
+		branch, nextIndent := "├── ", indent+"│   "
+		if i == len(names)-1 {
+			branch, nextIndent = "└── ", indent+"    "
+		}
+		if child.isDir {
+			fmt.Printf("  %s%s%s/\n", indent, branch, name)
+			printTree(child, states, nextIndent)
+			continue
+		}
+		fmt.Printf("  %s%s%s %s\n", indent, branch, markers[states[child.path]], name)
+	}
+}
@@ -0,0 +1,76 @@
+package preview
+
+import (
+	"grei-cli/internal/adapters/filesystem"
+	"grei-cli/internal/ports/inbound"
+	"testing"
+)
+
+func TestPreview(t *testing.T) {
+	repo := filesystem.NewMemoryRepository()
+	for name, content := range map[string]string{
+		"project/README.md": "# Old\n",
+		"project/Makefile":  "test:\n",
+	} {
+		if err := repo.CreateFile(name, []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	service := NewService(repo)
+	report, err := service.Preview(inbound.PreviewOptions{
+		Path: "project",
+		Files: map[string][]byte{
+			"README.md":         []byte("# New\n"),
+			"Makefile":          []byte("test:\n"),
+			"docs/adr/0001.md":  []byte("# ADR\n"),
+			"deploy/helm/.keep": nil,
+		},
+		Dirs: []string{"docs", "docs/adr", "deploy", "deploy/helm", "empty"},
+	})
+	if err != nil {
+		t.Fatalf("Preview() returned an unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"README.md":         inbound.FileModified,
+		"Makefile":          inbound.FileUnchanged,
+		"docs/adr/0001.md":  inbound.FileNew,
+		"deploy/helm/.keep": inbound.FileNew,
+	}
+	for path, state := range expected {
+		if report.States[path] != state {
+			t.Errorf("Expected %s to be %s, got %q", path, state, report.States[path])
+		}
+	}
+	if report.New != 2 || report.Modified != 1 || report.Unchanged != 1 {
+		t.Errorf("Unexpected counts: %+v", report)
+	}
+
+	files := repo.Files()
+	if got := string(files["project/README.md"]); got != "# Old\n" {
+		t.Errorf("Preview should not write files, got %q", got)
+	}
+	if _, ok := files["project/docs/adr/0001.md"]; ok {
+		t.Error("Preview should not create files")
+	}
+}
+
+func TestBuildTree(t *testing.T) {
+	tree := buildTree([]string{"a/b/c.txt", "d.txt"}, []string{"a", "a/b", "e"})
+
+	a := tree.children["a"]
+	if a == nil || !a.isDir {
+		t.Fatal("Expected directory a")
+	}
+	c := a.children["b"].children["c.txt"]
+	if c == nil || c.isDir || c.path != "a/b/c.txt" {
+		t.Errorf("Unexpected node for c.txt: %+v", c)
+	}
+	if d := tree.children["d.txt"]; d == nil || d.isDir {
+		t.Error("Expected file d.txt")
+	}
+	if e := tree.children["e"]; e == nil || !e.isDir {
+		t.Error("Expected empty directory e")
+	}
+}
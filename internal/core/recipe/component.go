@@ -0,0 +1,99 @@
+package recipe
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MonorepoType is the project type of the root recipe of a monorepo whose
+// code lives only in its components. The root of such a project is not
+// verified itself; its components are.
+const MonorepoType = "monorepo"
+
+// ComponentsDir is where new components of a monorepo are created.
+const ComponentsDir = "apps"
+
+// Component is one project of a monorepo, such as an SPA, an API or the
+// Helm charts.
+type Component struct {
+	Name string `yaml:"name"`
+	// Path is the directory of the component, relative to the root of the
+	// repository, e.g. "apps/api". It holds the component's own grei.yml.
+	Path string `yaml:"path"`
+}
+
+var componentName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// CheckComponentName reports whether name can name a new component.
+func CheckComponentName(name string) error {
+	if !componentName.MatchString(name) {
+		return fmt.Errorf("component name %q must use lower case letters, digits and dashes", name)
+	}
+	return nil
+}
+
+// CheckComponents reports components with an invalid name or path, and
+// names or paths used twice.
+func (r *Recipe) CheckComponents() error {
+	names := make(map[string]bool)
+	paths := make(map[string]string)
+	for _, c := range r.Components {
+		if err := CheckComponentName(c.Name); err != nil {
+			return err
+		}
+		if names[c.Name] {
+			return fmt.Errorf("component %q is declared twice", c.Name)
+		}
+		names[c.Name] = true
+
+		clean := path.Clean(c.Path)
+		if c.Path == "" || path.IsAbs(clean) || clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+			return fmt.Errorf("component %q: path %q must be a directory inside the repository", c.Name, c.Path)
+		}
+		if other, ok := paths[clean]; ok {
+			return fmt.Errorf("components %q and %q share the path %s", other, c.Name, clean)
+		}
+		paths[clean] = c.Name
+	}
+	return nil
+}
+
+// Component returns the component named name, or nil.
+func (r *Recipe) Component(name string) *Component {
+	for i := range r.Components {
+		if r.Components[i].Name == name {
+			return &r.Components[i]
+		}
+	}
+	return nil
+}
+
+// AddComponent appends c to the components of the recipe document, creating
+// the list if needed. The rest of the document is left as it was.
+func AddComponent(document *yaml.Node, c Component) error {
+	var item yaml.Node
+	if err := item.Encode(c); err != nil {
+		return err
+	}
+
+	components, err := Lookup(document, "components")
+	if err != nil {
+		return err
+	}
+	if components == nil || components.Tag == "!!null" {
+		return SetPath(document, "components", &yaml.Node{
+			Kind:    yaml.SequenceNode,
+			Tag:     "!!seq",
+			Content: []*yaml.Node{&item},
+		})
+	}
+	if components.Kind != yaml.SequenceNode {
+		return fmt.Errorf("components is not a list")
+	}
+	components.Content = append(components.Content, &item)
+	return nil
+}
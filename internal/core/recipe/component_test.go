@@ -0,0 +1,68 @@
+package recipe
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckComponents(t *testing.T) {
+	tests := []struct {
+		name       string
+		components []Component
+		wantErr    string
+	}{
+		{name: "valid", components: []Component{{Name: "web", Path: "apps/web"}, {Name: "api", Path: "apps/api/"}}},
+		{name: "bad name", components: []Component{{Name: "Web App", Path: "apps/web"}}, wantErr: "lower case"},
+		{name: "duplicate name", components: []Component{{Name: "web", Path: "a"}, {Name: "web", Path: "b"}}, wantErr: "declared twice"},
+		{name: "outside", components: []Component{{Name: "web", Path: "../web"}}, wantErr: "inside the repository"},
+		{name: "root", components: []Component{{Name: "web", Path: "."}}, wantErr: "inside the repository"},
+		{name: "absolute", components: []Component{{Name: "web", Path: "/srv/web"}}, wantErr: "inside the repository"},
+		{name: "shared path", components: []Component{{Name: "web", Path: "apps/web"}, {Name: "spa", Path: "apps/./web"}}, wantErr: "share the path apps/web"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Recipe{Components: tt.components}
+			err := r.CheckComponents()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("CheckComponents() returned an unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CheckComponents() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAddComponent(t *testing.T) {
+	document := parseNode(t, commented)
+	if err := AddComponent(document, Component{Name: "web", Path: "apps/web"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddComponent(document, Component{Name: "api", Path: "apps/api"}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := Encode(document, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(data)
+	want := "components:\n  - name: web\n    path: apps/web\n  - name: api\n    path: apps/api\n"
+	if !strings.HasSuffix(text, want) {
+		t.Errorf("components were not appended at the end:\n%s", text)
+	}
+	if !strings.Contains(text, "name: web # the service name") {
+		t.Errorf("comments were not kept:\n%s", text)
+	}
+
+	r, _, _, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := r.Component("api"); c == nil || c.Path != "apps/api" {
+		t.Errorf("Component(\"api\") = %+v, want apps/api", c)
+	}
+}
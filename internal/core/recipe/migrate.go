@@ -0,0 +1,160 @@
+package recipe
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// APIVersion is the version of the recipe format written by this CLI.
+const APIVersion = "grei/v1"
+
+// Migration upgrades a recipe document from one apiVersion to the next.
+type Migration struct {
+	From        string
+	To          string
+	Description string
+	Apply       func(root *yaml.Node) error
+}
+
+// Migrations are every known upgrade, in order. Recipes without an
+// apiVersion were written before the format was versioned.
+var Migrations = []Migration{
+	{
+		From:        "",
+		To:          "grei/v1",
+		Description: "move the top-level persistence and deployment sections into stack, rename stack.testing and stack.cicd to tests and ci, and drop empty answers",
+		Apply:       migrateUnversioned,
+	},
+}
+
+// Parse decodes the recipe in data, upgrading it first when it was written
+// in an older format. It also returns the upgraded document, for validation
+// or to save it, and the migrations applied.
+func Parse(data []byte) (*Recipe, *yaml.Node, []Migration, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, nil, nil, err
+	}
+	if len(document.Content) == 0 {
+		return nil, nil, nil, fmt.Errorf("the recipe is empty")
+	}
+	applied, err := Migrate(&document)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r Recipe
+	if err := document.Decode(&r); err != nil {
+		return nil, nil, nil, err
+	}
+	return &r, &document, applied, nil
+}
+
+// Version returns the apiVersion of the recipe document root, or "" for
+// recipes written before the format was versioned.
+func Version(root *yaml.Node) string {
+	if value := mappingValue(documentRoot(root), "apiVersion"); value != nil {
+		return value.Value
+	}
+	return ""
+}
+
+// Migrate upgrades the recipe document root, parsed with yaml.v3, in place
+// to APIVersion and returns the migrations it applied. Nodes that are kept
+// or moved keep their line and column, so problems found afterwards can
+// still be located in the original file.
+func Migrate(root *yaml.Node) ([]Migration, error) {
+	mapping := documentRoot(root)
+	if mapping.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("the recipe must be a mapping")
+	}
+
+	var applied []Migration
+	for version := Version(mapping); version != APIVersion; version = Version(mapping) {
+		migration := findMigration(version)
+		if migration == nil {
+			return applied, fmt.Errorf("unknown apiVersion %q; this version of grei supports up to %q", version, APIVersion)
+		}
+		if err := migration.Apply(mapping); err != nil {
+			return applied, fmt.Errorf("migrating from %q to %q: %w", migration.From, migration.To, err)
+		}
+		setValue(mapping, "apiVersion", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: migration.To})
+		applied = append(applied, *migration)
+	}
+	return applied, nil
+}
+
+func findMigration(from string) *Migration {
+	for i := range Migrations {
+		if Migrations[i].From == from {
+			return &Migrations[i]
+		}
+	}
+	return nil
+}
+
+// legacyNone are the values unversioned recipes used for "no persistence"
+// or "no deployment".
+var legacyNone = map[string]bool{"": true, "Ninguna": true, "Ninguno": true}
+
+func migrateUnversioned(root *yaml.Node) error {
+	stack := mappingValue(root, "stack")
+	if stack == nil || empty(stack) {
+		stack = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		defer func() {
+			if len(stack.Content) > 0 {
+				setValue(root, "stack", stack)
+			}
+		}()
+	}
+	if stack.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: stack must be a mapping", stack.Line)
+	}
+
+	// persistence: {type: PostgreSQL} and deployment: {type: ..., provider: ...}
+	// used to sit next to stack.
+	for _, section := range []string{"persistence", "deployment"} {
+		value := removeKey(root, section)
+		if value == nil {
+			continue
+		}
+		if value.Kind == yaml.MappingNode {
+			value = mappingValue(value, "type")
+		}
+		if value == nil || value.Kind != yaml.ScalarNode {
+			continue
+		}
+		if legacyNone[value.Value] {
+			value = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "None", Line: value.Line, Column: value.Column}
+		}
+		if mappingValue(stack, section) == nil {
+			setValue(stack, section, value)
+		}
+	}
+
+	for _, rename := range [][2]string{{"testing", "tests"}, {"cicd", "ci"}} {
+		old, name := rename[0], rename[1]
+		value := removeKey(stack, old)
+		if value == nil || mappingValue(stack, name) != nil {
+			continue
+		}
+		// cicd was a list; ci is a single pipeline.
+		if value.Kind == yaml.SequenceNode {
+			if len(value.Content) == 0 {
+				continue
+			}
+			value = value.Content[0]
+		}
+		setValue(stack, name, value)
+	}
+
+	for i := 0; i+1 < len(stack.Content); {
+		if empty(stack.Content[i+1]) {
+			stack.Content = append(stack.Content[:i], stack.Content[i+2:]...)
+			continue
+		}
+		i += 2
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package recipe
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const unversioned = `project:
+  name: greicodex-cli
+  customer: Greicodex
+  type: golang-cli
+stack:
+    language: Go
+    linter: ""
+    testing: go test
+    cicd: [GitHub Actions, Bitbucket Pipelines]
+persistence:
+    type: Ninguna
+deployment:
+    type: Kubernetes
+    provider: ""
+`
+
+func TestParse_Unversioned(t *testing.T) {
+	r, document, applied, err := Parse([]byte(unversioned))
+	if err != nil {
+		t.Fatalf("Parse returned an unexpected error: %v", err)
+	}
+	if len(applied) != 1 || applied[0].From != "" || applied[0].To != APIVersion {
+		t.Errorf("Unexpected migrations applied: %+v", applied)
+	}
+
+	if r.APIVersion != APIVersion {
+		t.Errorf("APIVersion = %q, want %q", r.APIVersion, APIVersion)
+	}
+	want := Stack{
+		Tests:       "go test",
+		Persistence: "None",
+		Deployment:  "Kubernetes",
+		CI:          "GitHub Actions",
+		Options:     map[string]interface{}{"language": "Go"},
+	}
+	if r.Stack.Tests != want.Tests || r.Stack.Persistence != want.Persistence || r.Stack.Deployment != want.Deployment ||
+		r.Stack.CI != want.CI || r.Stack.Linter != "" || len(r.Stack.Options) != 1 || r.Stack.Options["language"] != "Go" {
+		t.Errorf("Stack = %+v, want %+v", r.Stack, want)
+	}
+
+	// Moved values keep their position in the original file.
+	stack := mappingValue(documentRoot(document), "stack")
+	if deployment := mappingValue(stack, "deployment"); deployment.Line != 13 {
+		t.Errorf("stack.deployment is at line %d, want 13", deployment.Line)
+	}
+	if mappingValue(documentRoot(document), "persistence") != nil {
+		t.Error("The top-level persistence section should be removed")
+	}
+}
+
+func TestMigrate_Output(t *testing.T) {
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(unversioned), &document); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Migrate(&document); err != nil {
+		t.Fatalf("Migrate returned an unexpected error: %v", err)
+	}
+	out, err := yaml.Marshal(&document)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `apiVersion: grei/v1
+project:
+    name: greicodex-cli
+    customer: Greicodex
+    type: golang-cli
+stack:
+    language: Go
+    persistence: None
+    deployment: Kubernetes
+    tests: go test
+    ci: GitHub Actions
+`
+	if string(out) != want {
+		t.Errorf("Migrated recipe:\n%s\nwant:\n%s", out, want)
+	}
+
+	// Migrating again changes nothing.
+	applied, err := Migrate(&document)
+	if err != nil || len(applied) != 0 {
+		t.Errorf("A current recipe should not be migrated, got %v, %v", applied, err)
+	}
+}
+
+func TestMigrate_UnknownVersion(t *testing.T) {
+	_, _, _, err := Parse([]byte("apiVersion: grei/v9\nproject: {name: a}\n"))
+	if err == nil || !strings.Contains(err.Error(), `unknown apiVersion "grei/v9"`) {
+		t.Errorf("Expected an unknown apiVersion error, got %v", err)
+	}
+
+	_, _, _, err = Parse([]byte("- a list\n"))
+	if err == nil {
+		t.Error("Parse should reject a recipe that is not a mapping")
+	}
+}
+
+func TestStack_GetSetValues(t *testing.T) {
+	var s Stack
+	if err := s.Set("persistence", "PostgreSQL"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("features", []string{"auth"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("ci", true); err == nil {
+		t.Error("Set should reject a non-text value for a typed option")
+	}
+
+	if s.Persistence != "PostgreSQL" {
+		t.Errorf("Persistence = %q", s.Persistence)
+	}
+	if _, ok := s.Get("linter"); ok {
+		t.Error("An empty typed option should not count as answered")
+	}
+	if value, ok := s.Get("features"); !ok || value.([]string)[0] != "auth" {
+		t.Errorf("Get(features) = %v, %v", value, ok)
+	}
+
+	values := s.Values()
+	if len(values) != 2 || values["persistence"] != "PostgreSQL" {
+		t.Errorf("Values() = %v", values)
+	}
+}
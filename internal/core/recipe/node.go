@@ -0,0 +1,261 @@
+package recipe
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// pathStep is one step of a dotted recipe path: a mapping key, or a list
+// index when index >= 0.
+type pathStep struct {
+	key   string
+	index int
+}
+
+// parsePath splits a path such as "stack.ci" or "verify.required[0].path"
+// into its steps.
+func parsePath(path string) ([]pathStep, error) {
+	if path == "" {
+		return nil, fmt.Errorf("empty path")
+	}
+	var steps []pathStep
+	for _, part := range strings.Split(path, ".") {
+		key := part
+		var indexes []int
+		if open := strings.Index(part, "["); open >= 0 {
+			key = part[:open]
+			for rest := part[open:]; rest != ""; {
+				end := strings.Index(rest, "]")
+				if rest[0] != '[' || end < 0 {
+					return nil, fmt.Errorf("invalid path %q", path)
+				}
+				index, err := strconv.Atoi(rest[1:end])
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("invalid index in path %q", path)
+				}
+				indexes = append(indexes, index)
+				rest = rest[end+1:]
+			}
+		}
+		if key == "" && (len(steps) > 0 || len(indexes) == 0) {
+			return nil, fmt.Errorf("invalid path %q", path)
+		}
+		if key != "" {
+			steps = append(steps, pathStep{key: key, index: -1})
+		}
+		for _, index := range indexes {
+			steps = append(steps, pathStep{index: index})
+		}
+	}
+	return steps, nil
+}
+
+// Lookup returns the node at path in the recipe document, or nil if there is
+// none.
+func Lookup(document *yaml.Node, path string) (*yaml.Node, error) {
+	steps, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	node := documentRoot(document)
+	for _, step := range steps {
+		node = child(node, step)
+		if node == nil {
+			return nil, nil
+		}
+	}
+	return node, nil
+}
+
+// SetPath replaces the node at path in the recipe document with value,
+// creating the mappings leading to it. The rest of the document, including
+// its comments and the order of its keys, is left as it was, and value takes
+// over the comments of the node it replaces.
+func SetPath(document *yaml.Node, path string, value *yaml.Node) error {
+	steps, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	node := documentRoot(document)
+	for i, step := range steps {
+		last := i == len(steps)-1
+		if step.index >= 0 {
+			if node.Kind != yaml.SequenceNode {
+				return fmt.Errorf("%s: %s is not a list", path, stepsString(steps[:i]))
+			}
+			if step.index >= len(node.Content) {
+				return fmt.Errorf("%s: %s has no item %d", path, stepsString(steps[:i]), step.index)
+			}
+			if last {
+				keepComments(value, node.Content[step.index])
+				node.Content[step.index] = value
+				return nil
+			}
+			node = node.Content[step.index]
+			continue
+		}
+
+		if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+			*node = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", LineComment: node.LineComment}
+		}
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s: %s is not a mapping", path, stepsString(steps[:i]))
+		}
+		next := mappingValue(node, step.key)
+		if last {
+			if next != nil {
+				keepComments(value, next)
+			}
+			setValue(node, step.key, value)
+			return nil
+		}
+		if next == nil {
+			next = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			setValue(node, step.key, next)
+		}
+		node = next
+	}
+	return nil
+}
+
+// Encode writes the recipe document back as YAML with the given indentation.
+func Encode(document *yaml.Node, indent int) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(indent)
+	if err := encoder.Encode(document); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Indentation returns the indentation of the nested mappings in a YAML file,
+// so that a rewritten file keeps it. It is 4, the yaml.v3 default, when the
+// file has none.
+func Indentation(data []byte) int {
+	lines := strings.Split(string(data), "\n")
+	for i := 0; i+1 < len(lines); i++ {
+		parent := strings.TrimRight(lines[i], " \t")
+		if !strings.HasSuffix(parent, ":") || strings.HasPrefix(strings.TrimSpace(parent), "#") {
+			continue
+		}
+		line := lines[i+1]
+		content := strings.TrimLeft(line, " ")
+		if content == "" || strings.HasPrefix(content, "- ") || strings.HasPrefix(content, "#") {
+			continue
+		}
+		if indent := len(line) - len(content) - (len(parent) - len(strings.TrimLeft(parent, " "))); indent > 0 {
+			return indent
+		}
+	}
+	return 4
+}
+
+func child(node *yaml.Node, step pathStep) *yaml.Node {
+	if step.index >= 0 {
+		if node.Kind != yaml.SequenceNode || step.index >= len(node.Content) {
+			return nil
+		}
+		return node.Content[step.index]
+	}
+	return mappingValue(node, step.key)
+}
+
+func stepsString(steps []pathStep) string {
+	var b strings.Builder
+	for _, step := range steps {
+		if step.index >= 0 {
+			fmt.Fprintf(&b, "[%d]", step.index)
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString(".")
+		}
+		b.WriteString(step.key)
+	}
+	if b.Len() == 0 {
+		return "the recipe"
+	}
+	return b.String()
+}
+
+// keepComments moves the comments of a replaced node to its replacement,
+// unless the replacement brings its own.
+func keepComments(value, old *yaml.Node) {
+	if value.HeadComment == "" {
+		value.HeadComment = old.HeadComment
+	}
+	if value.LineComment == "" {
+		value.LineComment = old.LineComment
+	}
+	if value.FootComment == "" {
+		value.FootComment = old.FootComment
+	}
+}
+
+func documentRoot(node *yaml.Node) *yaml.Node {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		return node.Content[0]
+	}
+	return node
+}
+
+func empty(node *yaml.Node) bool {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Tag == "!!null" || (node.Tag == "!!str" && node.Value == "")
+	case yaml.SequenceNode, yaml.MappingNode:
+		return len(node.Content) == 0
+	}
+	return false
+}
+
+// mappingValue returns the value of key in the mapping node, or nil.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setValue replaces the value of key in the mapping node. A new apiVersion
+// goes first; any other new key goes last.
+func setValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key, Line: value.Line, Column: value.Column}
+	if key == "apiVersion" {
+		mapping.Content = append([]*yaml.Node{keyNode, value}, mapping.Content...)
+		return
+	}
+	mapping.Content = append(mapping.Content, keyNode, value)
+}
+
+// removeKey deletes key from the mapping node and returns its value, or nil
+// if the key was not there.
+func removeKey(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			value := mapping.Content[i+1]
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package recipe
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const commented = `# Project recipe
+apiVersion: grei/v1
+project:
+  name: web # the service name
+  customer: Acme
+  type: typescript-express-fullstack
+stack:
+  persistence: None # no database yet
+verify:
+  required:
+    - path: LICENSE
+`
+
+func parseNode(t *testing.T, text string) *yaml.Node {
+	t.Helper()
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(text), &document); err != nil {
+		t.Fatal(err)
+	}
+	return &document
+}
+
+func TestLookup(t *testing.T) {
+	document := parseNode(t, commented)
+	tests := map[string]string{
+		"project.name":            "web",
+		"stack.persistence":       "None",
+		"verify.required[0].path": "LICENSE",
+		"stack.ci":                "",
+		"verify.required[3].path": "",
+		"project.name.nested":     "",
+	}
+	for path, want := range tests {
+		node, err := Lookup(document, path)
+		if err != nil {
+			t.Errorf("Lookup(%q) returned an unexpected error: %v", path, err)
+			continue
+		}
+		got := ""
+		if node != nil {
+			got = node.Value
+		}
+		if got != want {
+			t.Errorf("Lookup(%q) = %q, want %q", path, got, want)
+		}
+	}
+
+	for _, path := range []string{"", "stack..ci", "verify.required[x]", "verify.required[0"} {
+		if _, err := Lookup(document, path); err == nil {
+			t.Errorf("Lookup(%q) should reject the path", path)
+		}
+	}
+}
+
+func TestSetPath_KeepsCommentsAndOrder(t *testing.T) {
+	document := parseNode(t, commented)
+	set := func(path, value string) {
+		t.Helper()
+		if err := SetPath(document, path, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}); err != nil {
+			t.Fatalf("SetPath(%q) returned an unexpected error: %v", path, err)
+		}
+	}
+	set("stack.persistence", "PostgreSQL")
+	set("stack.ci", "GitHub Actions")
+	set("verify.required[0].path", "LICENSE.md")
+	set("templates.source", "embedded")
+
+	out, err := Encode(document, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# Project recipe
+apiVersion: grei/v1
+project:
+  name: web # the service name
+  customer: Acme
+  type: typescript-express-fullstack
+stack:
+  persistence: PostgreSQL # no database yet
+  ci: GitHub Actions
+verify:
+  required:
+    - path: LICENSE.md
+templates:
+  source: embedded
+`
+	if string(out) != want {
+		t.Errorf("Edited recipe:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestSetPath_Errors(t *testing.T) {
+	document := parseNode(t, commented)
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "x"}
+	for path, want := range map[string]string{
+		"project.name.first":      "project.name is not a mapping",
+		"verify.required[2].path": "verify.required has no item 2",
+		"stack[0]":                "stack is not a list",
+	} {
+		err := SetPath(document, path, value)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("SetPath(%q) = %v, want an error containing %q", path, err, want)
+		}
+	}
+}
+
+func TestIndentation(t *testing.T) {
+	if got := Indentation([]byte(commented)); got != 2 {
+		t.Errorf("Indentation() = %d, want 2", got)
+	}
+	if got := Indentation([]byte(unversioned)); got != 2 {
+		t.Errorf("Indentation() = %d, want the first nested mapping's 2", got)
+	}
+	if got := Indentation([]byte("apiVersion: grei/v1\n")); got != 4 {
+		t.Errorf("Indentation() = %d, want the default 4", got)
+	}
+}
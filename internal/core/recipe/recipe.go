@@ -1,9 +1,33 @@
 package recipe
 
+import (
+	"fmt"
+	"grei-cli/internal/core/policy"
+)
+
 // Recipe represents the structure of the grei.yml file.
 type Recipe struct {
-	Project Project                `yaml:"project" survey:"project"`
-	Stack   map[string]interface{} `yaml:"stack,omitempty" survey:"stack"`
+	// APIVersion is the version of the recipe format, APIVersion for
+	// recipes written by this CLI. Older recipes are upgraded by Migrate.
+	APIVersion string       `yaml:"apiVersion" survey:"-"`
+	Project    Project      `yaml:"project" survey:"project"`
+	Stack      Stack        `yaml:"stack,omitempty" survey:"-"`
+	Verify     policy.Rules `yaml:"verify,omitempty" survey:"-"`
+	// Components are the projects of a monorepo, each with a grei.yml of
+	// its own in its directory.
+	Components []Component `yaml:"components,omitempty" survey:"-"`
+	// Templates pins the templates the project was generated from.
+	Templates *Templates `yaml:"templates,omitempty" survey:"-"`
+}
+
+// Templates records the template bundle a project was generated from, so it
+// can be scaffolded again with the same templates.
+type Templates struct {
+	// Source is a git URL, a path or "embedded", as accepted by --templates.
+	Source  string `yaml:"source"`
+	Ref     string `yaml:"ref,omitempty"`
+	Commit  string `yaml:"commit,omitempty"`
+	Version string `yaml:"version,omitempty"`
 }
 
 // Project contains basic information about the project.
@@ -12,3 +36,17 @@ type Project struct {
 	Customer string `yaml:"customer" survey:"customer"`
 	Type     string `yaml:"type" survey:"type"`
 }
+
+// Vars returns the recipe values that template conditions can refer to,
+// such as "project.type" or "stack.ci".
+func (r *Recipe) Vars() map[string]string {
+	vars := map[string]string{
+		"project.name":     r.Project.Name,
+		"project.customer": r.Project.Customer,
+		"project.type":     r.Project.Type,
+	}
+	for key, value := range r.Stack.Values() {
+		vars["stack."+key] = fmt.Sprint(value)
+	}
+	return vars
+}
@@ -0,0 +1,70 @@
+package recipe
+
+import "fmt"
+
+// Stack holds the answers to the options of the project's stack. The
+// options most stacks share have their own fields; the answers to any other
+// option declared by the stack manifest are kept in Options, by name.
+type Stack struct {
+	Linter      string `yaml:"linter,omitempty"`
+	Tests       string `yaml:"tests,omitempty"`
+	Persistence string `yaml:"persistence,omitempty"`
+	Deployment  string `yaml:"deployment,omitempty"`
+	CI          string `yaml:"ci,omitempty"`
+	// Options are the answers to the other options, such as "backend" or
+	// "dbVersion".
+	Options map[string]interface{} `yaml:",inline"`
+}
+
+// fields returns the typed fields of s by option name.
+func (s *Stack) fields() map[string]*string {
+	return map[string]*string{
+		"linter":      &s.Linter,
+		"tests":       &s.Tests,
+		"persistence": &s.Persistence,
+		"deployment":  &s.Deployment,
+		"ci":          &s.CI,
+	}
+}
+
+// Get returns the answer to the option name, and whether it was answered.
+func (s *Stack) Get(name string) (interface{}, bool) {
+	if field, ok := s.fields()[name]; ok {
+		return *field, *field != ""
+	}
+	value, ok := s.Options[name]
+	return value, ok
+}
+
+// Set stores the answer to the option name. The options with their own
+// field only take text.
+func (s *Stack) Set(name string, value interface{}) error {
+	if field, ok := s.fields()[name]; ok {
+		text, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("stack option %q must be text, got %v", name, value)
+		}
+		*field = text
+		return nil
+	}
+	if s.Options == nil {
+		s.Options = make(map[string]interface{})
+	}
+	s.Options[name] = value
+	return nil
+}
+
+// Values returns every answer by option name, as templates see them in
+// .Stack.
+func (s *Stack) Values() map[string]interface{} {
+	values := make(map[string]interface{}, len(s.Options)+5)
+	for name, value := range s.Options {
+		values[name] = value
+	}
+	for name, field := range s.fields() {
+		if *field != "" {
+			values[name] = *field
+		}
+	}
+	return values
+}
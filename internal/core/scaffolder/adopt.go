@@ -0,0 +1,80 @@
+package scaffolder
+
+import (
+	"errors"
+	"fmt"
+	"grei-cli/internal/core/recipe"
+	"grei-cli/internal/templates"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// defaultStandard are the standard files of a generic skeleton whose
+// manifest does not list them.
+var defaultStandard = []string{"LICENSE", "CONTRIBUTING.md", ".githooks", "docs/adr"}
+
+// Adopt renders the standard files of the generic skeleton into path, so an
+// existing project gets those it lacks. Files that already exist are never
+// changed, and the stack's own skeleton is left out, as the project already
+// has its code.
+func (s *service) Adopt(path string, templatesFS fs.FS, recipe *recipe.Recipe) ([]string, error) {
+	fmt.Printf("\n[i] Adding the standard files missing from the project...\n")
+
+	skeletons, err := loadSkeletons(templatesFS)
+	if err != nil {
+		return nil, err
+	}
+	generic := genericLayer(skeletons)
+	standard := defaultStandard
+	if generic.manifest != nil && len(generic.manifest.Standard) > 0 {
+		standard = generic.manifest.Standard
+	}
+	data := templates.NewData(*recipe)
+	project, err := plan(templatesFS, []layer{generic}, data)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dir := range project.dirs() {
+		if !isStandard(standard, dir) {
+			continue
+		}
+		if err := s.fsRepo.CreateDir(filepath.Join(path, filepath.FromSlash(dir))); err != nil {
+			return nil, err
+		}
+	}
+	var created []string
+	for _, file := range project.paths() {
+		if !isStandard(standard, file) {
+			continue
+		}
+		target := filepath.Join(path, filepath.FromSlash(file))
+		if _, err := s.fsRepo.ReadFile(target); err == nil {
+			continue
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+
+		content, err := renderFile(templatesFS, project.files[file], data)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.fsRepo.CreateFile(target, content); err != nil {
+			return nil, err
+		}
+		created = append(created, file)
+	}
+	return created, nil
+}
+
+// isStandard reports whether file is one of the standard paths or lies
+// inside one of them.
+func isStandard(standard []string, file string) bool {
+	for _, p := range standard {
+		if file == p || strings.HasPrefix(file, p+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package scaffolder
+
+import (
+	"grei-cli/internal/adapters/filesystem"
+	"grei-cli/internal/core/recipe"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAdopt_OnlyAddsMissingStandardFiles(t *testing.T) {
+	templatesFS := fstest.MapFS{
+		"skeletons/generic/manifest.yml":                                   {Data: []byte("name: generic")},
+		"skeletons/generic/README.md.tmpl":                                 {Data: []byte("# {{ .Project.Name }}")},
+		"skeletons/generic/.gitignore.tmpl":                                {Data: []byte("node_modules/")},
+		"skeletons/generic/deploy/helm/values.yaml":                        {Data: []byte("replicas: 1")},
+		"skeletons/generic/LICENSE.tmpl":                                   {Data: []byte("Copyright {{ .Project.Customer }}")},
+		"skeletons/generic/.githooks/pre-commit":                           {Data: []byte("#!/bin/sh")},
+		"skeletons/generic/docs/adr/0001-record-architecture-decisions.md": {Data: []byte("# 1.")},
+
+		"skeletons/app/manifest.yml": {Data: []byte("name: app")},
+		"skeletons/app/src/main.ts":  {Data: []byte("app")},
+	}
+	projRecipe := &recipe.Recipe{Project: recipe.Project{Name: "legacy", Customer: "Acme", Type: "app"}}
+
+	repo := filesystem.NewMemoryRepository()
+	repo.CreateFile("project/README.md", []byte("# Legacy"))
+	repo.CreateFile("project/src/main.ts", []byte("legacy code"))
+
+	created, err := NewService(repo).Adopt("project", templatesFS, projRecipe)
+	if err != nil {
+		t.Fatalf("Adopt() returned an unexpected error: %v", err)
+	}
+
+	want := []string{".githooks/pre-commit", "LICENSE", "docs/adr/0001-record-architecture-decisions.md"}
+	if !reflect.DeepEqual(created, want) {
+		t.Errorf("Adopt() created %v, want %v", created, want)
+	}
+	files := repo.Files()
+	if string(files["project/README.md"]) != "# Legacy" || string(files["project/src/main.ts"]) != "legacy code" {
+		t.Error("Adopt() changed existing files")
+	}
+	if _, ok := files["project/.gitignore"]; ok {
+		t.Error("Adopt() added .gitignore, which is not a standard file")
+	}
+	if _, ok := files["project/deploy/helm/values.yaml"]; ok {
+		t.Error("Adopt() added deploy/helm, which is not a standard file")
+	}
+	if string(files["project/LICENSE"]) != "Copyright Acme" {
+		t.Errorf("LICENSE = %q", files["project/LICENSE"])
+	}
+}
+
+func TestAdopt_ManifestStandardFiles(t *testing.T) {
+	templatesFS := fstest.MapFS{
+		"skeletons/generic/manifest.yml":   {Data: []byte("name: generic\nstandard: [README.md]")},
+		"skeletons/generic/README.md.tmpl": {Data: []byte("# {{ .Project.Name }}")},
+		"skeletons/generic/LICENSE.tmpl":   {Data: []byte("Copyright {{ .Project.Customer }}")},
+	}
+	projRecipe := &recipe.Recipe{Project: recipe.Project{Name: "legacy", Customer: "Acme"}}
+
+	created, err := NewService(filesystem.NewMemoryRepository()).Adopt("project", templatesFS, projRecipe)
+	if err != nil {
+		t.Fatalf("Adopt() returned an unexpected error: %v", err)
+	}
+	if want := []string{"README.md"}; !reflect.DeepEqual(created, want) {
+		t.Errorf("Adopt() created %v, want %v", created, want)
+	}
+}
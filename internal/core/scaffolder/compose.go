@@ -0,0 +1,144 @@
+package scaffolder
+
+import (
+	"fmt"
+	"grei-cli/internal/core/expr"
+	"grei-cli/internal/core/recipe"
+	"io/fs"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MixinType is the manifest type of skeletons meant to be included by other
+// skeletons rather than chosen as a project stack.
+const MixinType = "mixin"
+
+// loadSkeletons reads every skeleton manifest, keyed by skeleton name.
+func loadSkeletons(templatesFS fs.FS) (map[string]layer, error) {
+	skeletons := make(map[string]layer)
+	err := fs.WalkDir(templatesFS, "skeletons", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || d.Name() != "manifest.yml" {
+			return nil
+		}
+
+		manifestFile, err := fs.ReadFile(templatesFS, path)
+		if err != nil {
+			return err
+		}
+		var manifest Manifest
+		if err := yaml.Unmarshal(manifestFile, &manifest); err != nil {
+			return fmt.Errorf("could not parse %s: %w", path, err)
+		}
+
+		dir := strings.TrimSuffix(path, "/manifest.yml")
+		if other, ok := skeletons[manifest.Name]; ok {
+			return fmt.Errorf("skeleton %q is declared by both %s and %s", manifest.Name, other.dir, dir)
+		}
+		skeletons[manifest.Name] = layer{dir: dir, manifest: &manifest}
+		return nil
+	})
+	return skeletons, err
+}
+
+// resolveLayers returns the layers of the recipe's stack in the order they
+// are applied: the generic skeleton, then for every skeleton its Extends
+// chain, its mixins and finally the skeleton itself. A skeleton reached
+// twice is applied once, at its first position.
+func resolveLayers(templatesFS fs.FS, recipe *recipe.Recipe) ([]layer, error) {
+	skeletons, err := loadSkeletons(templatesFS)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &resolver{
+		skeletons: skeletons,
+		vars:      recipe.Vars(),
+		state:     map[string]int{"generic": resolved},
+		layers:    []layer{genericLayer(skeletons)},
+	}
+	if _, ok := skeletons[recipe.Project.Type]; ok {
+		if err := r.visit(recipe.Project.Type); err != nil {
+			return nil, err
+		}
+	}
+	return r.layers, nil
+}
+
+// genericLayer returns the generic skeleton, which every project starts
+// from, with its manifest when it has one.
+func genericLayer(skeletons map[string]layer) layer {
+	if l, ok := skeletons["generic"]; ok && l.dir == genericSkeleton {
+		return l
+	}
+	return layer{dir: genericSkeleton}
+}
+
+const (
+	unvisited = iota
+	visiting
+	resolved
+)
+
+type resolver struct {
+	skeletons map[string]layer
+	vars      map[string]string
+	state     map[string]int
+	path      []string
+	layers    []layer
+}
+
+func (r *resolver) visit(name string) error {
+	switch r.state[name] {
+	case resolved:
+		return nil
+	case visiting:
+		cycle := append(r.path[r.indexOf(name):], name)
+		return fmt.Errorf("skeletons form a cycle: %s", strings.Join(cycle, " -> "))
+	}
+
+	l, ok := r.skeletons[name]
+	if !ok {
+		return fmt.Errorf("skeleton %q, used by %q, does not exist", name, r.path[len(r.path)-1])
+	}
+
+	r.state[name] = visiting
+	r.path = append(r.path, name)
+
+	if l.manifest.Extends != "" {
+		if err := r.visit(l.manifest.Extends); err != nil {
+			return err
+		}
+	}
+	for _, mixin := range l.manifest.Mixins {
+		if mixin.When != "" {
+			include, err := expr.Eval(mixin.When, r.vars)
+			if err != nil {
+				return fmt.Errorf("invalid condition for mixin %q in %s/manifest.yml: %w", mixin.Name, l.dir, err)
+			}
+			if !include {
+				continue
+			}
+		}
+		if err := r.visit(mixin.Name); err != nil {
+			return err
+		}
+	}
+
+	r.path = r.path[:len(r.path)-1]
+	r.state[name] = resolved
+	r.layers = append(r.layers, l)
+	return nil
+}
+
+func (r *resolver) indexOf(name string) int {
+	for i, n := range r.path {
+		if n == name {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,158 @@
+package scaffolder
+
+import (
+	"grei-cli/internal/adapters/filesystem"
+	"grei-cli/internal/core/recipe"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func composedTemplates() fstest.MapFS {
+	return fstest.MapFS{
+		"skeletons/generic/manifest.yml": {Data: []byte("name: generic")},
+		"skeletons/generic/README.md":    {Data: []byte("generic")},
+
+		"skeletons/base/manifest.yml": {Data: []byte("name: base\nmixins: [helm]")},
+		"skeletons/base/README.md":    {Data: []byte("base")},
+		"skeletons/base/Makefile":     {Data: []byte("base")},
+
+		"skeletons/mixins/helm/manifest.yml":           {Data: []byte("name: helm\ntype: mixin")},
+		"skeletons/mixins/helm/deploy/helm/Chart.yaml": {Data: []byte("name: {{ ToKebab .Project.Name }}")},
+		"skeletons/mixins/helm/Makefile":               {Data: []byte("helm")},
+
+		"skeletons/mixins/postgres/manifest.yml":                {Data: []byte("name: postgres\ntype: mixin")},
+		"skeletons/mixins/postgres/db/migrations/0001_init.sql": {Data: []byte("-- init")},
+		"skeletons/mixins/postgres/Makefile":                    {Data: []byte("postgres")},
+
+		"skeletons/app/manifest.yml": {Data: []byte(`
+name: app
+extends: base
+mixins:
+  - helm
+  - name: postgres
+    when: stack.persistence == "PostgreSQL"
+`)},
+		"skeletons/app/src/main.ts": {Data: []byte("app")},
+	}
+}
+
+func layerDirs(layers []layer) []string {
+	var dirs []string
+	for _, l := range layers {
+		dirs = append(dirs, l.dir)
+	}
+	return dirs
+}
+
+func TestResolveLayers(t *testing.T) {
+	tests := []struct {
+		persistence string
+		want        []string
+	}{
+		{
+			persistence: "PostgreSQL",
+			want:        []string{"skeletons/generic", "skeletons/mixins/helm", "skeletons/base", "skeletons/mixins/postgres", "skeletons/app"},
+		},
+		{
+			persistence: "None",
+			want:        []string{"skeletons/generic", "skeletons/mixins/helm", "skeletons/base", "skeletons/app"},
+		},
+	}
+
+	for _, tt := range tests {
+		projRecipe := &recipe.Recipe{
+			Project: recipe.Project{Name: "app", Type: "app"},
+			Stack:   recipe.Stack{Persistence: tt.persistence},
+		}
+		layers, err := resolveLayers(composedTemplates(), projRecipe)
+		if err != nil {
+			t.Fatalf("resolveLayers() returned an unexpected error: %v", err)
+		}
+		if got := layerDirs(layers); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("persistence %s: layers = %v, want %v", tt.persistence, got, tt.want)
+		}
+	}
+}
+
+func TestScaffold_Composition(t *testing.T) {
+	projRecipe := &recipe.Recipe{
+		Project: recipe.Project{Name: "My App", Type: "app"},
+		Stack:   recipe.Stack{Persistence: "PostgreSQL"},
+	}
+
+	repo := filesystem.NewMemoryRepository()
+	if err := NewService(repo).Scaffold("project", composedTemplates(), projRecipe); err != nil {
+		t.Fatalf("Scaffold() returned an unexpected error: %v", err)
+	}
+
+	files := repo.Files()
+	expected := map[string]string{
+		"project/README.md":                   "base",
+		"project/Makefile":                    "postgres",
+		"project/deploy/helm/Chart.yaml":      "name: my-app",
+		"project/db/migrations/0001_init.sql": "-- init",
+		"project/src/main.ts":                 "app",
+	}
+	for path, want := range expected {
+		if got := string(files[path]); got != want {
+			t.Errorf("Expected %s to be %q, got %q", path, want, got)
+		}
+	}
+}
+
+func TestResolveLayers_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		manifests map[string]string
+		want      string
+	}{
+		{
+			name: "cycle",
+			manifests: map[string]string{
+				"a": "name: a\nextends: b",
+				"b": "name: b\nmixins: [c]",
+				"c": "name: c\nextends: a",
+			},
+			want: "a -> b -> c -> a",
+		},
+		{
+			name:      "self",
+			manifests: map[string]string{"a": "name: a\nmixins: [a]"},
+			want:      "a -> a",
+		},
+		{
+			name:      "missing",
+			manifests: map[string]string{"a": "name: a\nextends: nowhere"},
+			want:      `skeleton "nowhere", used by "a", does not exist`,
+		},
+		{
+			name: "duplicate",
+			manifests: map[string]string{
+				"a":     "name: a",
+				"other": "name: a",
+			},
+			want: "declared by both",
+		},
+		{
+			name:      "condition",
+			manifests: map[string]string{"a": "name: a\nmixins:\n  - name: b\n    when: stack.x = 1"},
+			want:      `invalid condition for mixin "b"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templatesFS := fstest.MapFS{"skeletons/generic/README.md": {Data: []byte("")}}
+			for dir, manifest := range tt.manifests {
+				templatesFS["skeletons/"+dir+"/manifest.yml"] = &fstest.MapFile{Data: []byte(manifest)}
+			}
+
+			_, err := resolveLayers(templatesFS, &recipe.Recipe{Project: recipe.Project{Type: "a"}})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
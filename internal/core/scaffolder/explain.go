@@ -0,0 +1,147 @@
+package scaffolder
+
+import (
+	"fmt"
+	"grei-cli/internal/core/expr"
+	"grei-cli/internal/core/recipe"
+	"grei-cli/internal/ports/inbound"
+	"grei-cli/internal/templates"
+	"io/fs"
+	"regexp"
+	"strings"
+)
+
+// stackReference matches the stack values a template reads, such as
+// {{ .Stack.persistence }}.
+var stackReference = regexp.MustCompile(`\.Stack\.([A-Za-z_][A-Za-z0-9_]*)`)
+
+// Explain lists, for the recipe's stack, the mixins and files included
+// under a condition, the options asked under a condition and the generated
+// files whose template reads a stack value. Mixins and file rules of
+// skeletons the recipe does not include are listed too, as inactive.
+func (s *service) Explain(templatesFS fs.FS, recipe *recipe.Recipe) ([]inbound.Activation, error) {
+	skeletons, err := loadSkeletons(templatesFS)
+	if err != nil {
+		return nil, err
+	}
+	layers, err := resolveLayers(templatesFS, recipe)
+	if err != nil {
+		return nil, err
+	}
+	included := make(map[string]bool)
+	for _, l := range layers {
+		included[l.dir] = true
+	}
+
+	e := &explainer{skeletons: skeletons, vars: recipe.Vars(), included: included, seen: make(map[string]bool)}
+	if generic, ok := skeletons["generic"]; ok {
+		if err := e.visit(generic); err != nil {
+			return nil, err
+		}
+	}
+	if l, ok := skeletons[recipe.Project.Type]; ok {
+		for _, option := range l.manifest.Options {
+			if err := e.add(inbound.ActivationOption, option.Name, option.When, true); err != nil {
+				return nil, fmt.Errorf("invalid condition for option %q in %s/manifest.yml: %w", option.Name, l.dir, err)
+			}
+		}
+		if err := e.visit(l); err != nil {
+			return nil, err
+		}
+	}
+
+	data := templates.NewData(*recipe)
+	project, err := plan(templatesFS, layers, data)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range project.paths() {
+		content, err := fs.ReadFile(templatesFS, project.files[file])
+		if err != nil {
+			return nil, err
+		}
+		read := make(map[string]bool)
+		for _, match := range stackReference.FindAllStringSubmatch(string(content), -1) {
+			if read[match[1]] {
+				continue
+			}
+			read[match[1]] = true
+			e.activations = append(e.activations, inbound.Activation{
+				Var:    "stack." + match[1],
+				Kind:   inbound.ActivationTemplate,
+				Target: file,
+				Active: true,
+			})
+		}
+	}
+	return e.activations, nil
+}
+
+type explainer struct {
+	skeletons   map[string]layer
+	vars        map[string]string
+	included    map[string]bool
+	seen        map[string]bool
+	activations []inbound.Activation
+}
+
+// visit lists the conditions of a skeleton and of every skeleton it builds
+// on, whether the recipe includes them or not.
+func (e *explainer) visit(l layer) error {
+	if e.seen[l.dir] || l.manifest == nil {
+		return nil
+	}
+	e.seen[l.dir] = true
+
+	if extends, ok := e.skeletons[l.manifest.Extends]; ok {
+		if err := e.visit(extends); err != nil {
+			return err
+		}
+	}
+	for _, mixin := range l.manifest.Mixins {
+		if mixin.When != "" {
+			if err := e.add(inbound.ActivationMixin, mixin.Name, mixin.When, e.included[l.dir]); err != nil {
+				return fmt.Errorf("invalid condition for mixin %q in %s/manifest.yml: %w", mixin.Name, l.dir, err)
+			}
+		}
+		if m, ok := e.skeletons[mixin.Name]; ok {
+			if err := e.visit(m); err != nil {
+				return err
+			}
+		}
+	}
+	for _, rule := range l.manifest.Files {
+		if err := e.add(inbound.ActivationFiles, strings.Join(rule.Paths, ", "), rule.When, e.included[l.dir]); err != nil {
+			return fmt.Errorf("invalid file rules in %s/manifest.yml: %w", l.dir, err)
+		}
+	}
+	return nil
+}
+
+// add records target once for every value its condition refers to. A
+// target whose skeleton is not included is never active.
+func (e *explainer) add(kind, target, when string, included bool) error {
+	if when == "" {
+		return nil
+	}
+	condition, err := expr.Compile(when)
+	if err != nil {
+		return err
+	}
+	active := included && condition.Eval(e.vars)
+	seen := make(map[string]bool)
+	for _, name := range condition.Vars() {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		e.activations = append(e.activations, inbound.Activation{
+			Var:    name,
+			Kind:   kind,
+			Target: target,
+			When:   when,
+			Active: active,
+		})
+	}
+	return nil
+}
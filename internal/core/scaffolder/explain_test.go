@@ -0,0 +1,53 @@
+package scaffolder
+
+import (
+	"grei-cli/internal/adapters/filesystem"
+	"grei-cli/internal/core/recipe"
+	"grei-cli/internal/ports/inbound"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestExplain(t *testing.T) {
+	templatesFS := composedTemplates()
+	templatesFS["skeletons/app/manifest.yml"] = &fstest.MapFile{Data: []byte(`
+name: app
+extends: base
+options:
+  persistence:
+    values: [None, PostgreSQL]
+  dbVersion:
+    type: input
+    when: stack.persistence != "None"
+mixins:
+  - helm
+  - name: postgres
+    when: stack.persistence == "PostgreSQL"
+files:
+  - paths: [".github/**"]
+    when: stack.ci == "GitHub Actions"
+`)}
+	templatesFS["skeletons/app/docker-compose.yml.tmpl"] = &fstest.MapFile{Data: []byte("image: postgres:{{ .Stack.dbVersion }} # {{ .Stack.dbVersion }}")}
+	templatesFS["skeletons/mixins/postgres/manifest.yml"] = &fstest.MapFile{Data: []byte("name: postgres\ntype: mixin\nfiles:\n  - paths: [db/seeds]\n    when: stack.seeds")}
+
+	projRecipe := &recipe.Recipe{
+		Project: recipe.Project{Name: "My App", Type: "app"},
+		Stack:   recipe.Stack{Persistence: "None", CI: "GitHub Actions"},
+	}
+	activations, err := NewService(filesystem.NewMemoryRepository()).Explain(templatesFS, projRecipe)
+	if err != nil {
+		t.Fatalf("Explain() returned an unexpected error: %v", err)
+	}
+
+	want := []inbound.Activation{
+		{Var: "stack.persistence", Kind: inbound.ActivationOption, Target: "dbVersion", When: `stack.persistence != "None"`},
+		{Var: "stack.persistence", Kind: inbound.ActivationMixin, Target: "postgres", When: `stack.persistence == "PostgreSQL"`},
+		{Var: "stack.seeds", Kind: inbound.ActivationFiles, Target: "db/seeds", When: "stack.seeds"},
+		{Var: "stack.ci", Kind: inbound.ActivationFiles, Target: ".github/**", When: `stack.ci == "GitHub Actions"`, Active: true},
+		{Var: "stack.dbVersion", Kind: inbound.ActivationTemplate, Target: "docker-compose.yml", Active: true},
+	}
+	if !reflect.DeepEqual(activations, want) {
+		t.Errorf("Explain() =\n%+v\nwant\n%+v", activations, want)
+	}
+}
@@ -0,0 +1,233 @@
+package scaffolder
+
+import (
+	"fmt"
+	"grei-cli/internal/core/expr"
+	"grei-cli/internal/core/recipe"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Option types.
+const (
+	OptionSelect      = "select"
+	OptionMultiSelect = "multiselect"
+	OptionConfirm     = "confirm"
+	OptionInput       = "input"
+)
+
+// Option is a question about the stack, answered into the recipe's Stack
+// under Name.
+type Option struct {
+	Name    string   `yaml:"name"`
+	Type    string   `yaml:"type"`
+	Message string   `yaml:"message"`
+	Help    string   `yaml:"help"`
+	Values  []string `yaml:"values"`
+	// Default is a string, a list of strings for multiselect or a bool for
+	// confirm. A select defaults to its first value; other options without
+	// a default must be answered.
+	Default interface{} `yaml:"default"`
+	// Pattern is a regular expression free-text answers must match.
+	Pattern string `yaml:"pattern"`
+	// When is a condition on the project and the previous answers; the
+	// option is only asked when it holds.
+	When string `yaml:"when"`
+}
+
+// Options are the questions of a stack, in the order they are asked. In
+// manifest.yml they are either a mapping from name to option, kept in file
+// order, or a list of options with a name.
+type Options []Option
+
+func (o *Options) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			var option Option
+			if err := value.Content[i+1].Decode(&option); err != nil {
+				return err
+			}
+			option.Name = value.Content[i].Value
+			*o = append(*o, option)
+		}
+		return nil
+	case yaml.SequenceNode:
+		return value.Decode((*[]Option)(o))
+	}
+	return fmt.Errorf("line %d: options must be a mapping or a list", value.Line)
+}
+
+// Kind returns the type of the option, select by default.
+func (o *Option) Kind() string {
+	if o.Type == "" {
+		return OptionSelect
+	}
+	return o.Type
+}
+
+// Applies reports whether the option should be asked, given the answers so
+// far in r.
+func (o *Option) Applies(r *recipe.Recipe) (bool, error) {
+	if o.When == "" {
+		return true, nil
+	}
+	applies, err := expr.Eval(o.When, r.Vars())
+	if err != nil {
+		return false, fmt.Errorf("option %q: %w", o.Name, err)
+	}
+	return applies, nil
+}
+
+// DefaultValue returns the answer used when the option is not answered, or
+// nil when it must be answered.
+func (o *Option) DefaultValue() interface{} {
+	if o.Default == nil && o.Kind() == OptionSelect && len(o.Values) > 0 {
+		return o.Values[0]
+	}
+	return o.Default
+}
+
+// Check validates the definition of the option itself.
+func (o *Option) Check() error {
+	switch o.Kind() {
+	case OptionSelect, OptionMultiSelect:
+		if len(o.Values) == 0 {
+			return fmt.Errorf("option %q: %s options need values", o.Name, o.Kind())
+		}
+	case OptionConfirm:
+	case OptionInput:
+		if _, err := regexp.Compile(o.Pattern); err != nil {
+			return fmt.Errorf("option %q: invalid pattern: %w", o.Name, err)
+		}
+	default:
+		return fmt.Errorf("option %q: unknown type %q", o.Name, o.Type)
+	}
+	if o.When != "" {
+		if _, err := expr.Compile(o.When); err != nil {
+			return fmt.Errorf("option %q: %w", o.Name, err)
+		}
+	}
+	if o.Default != nil {
+		if _, err := o.Normalize(o.Default); err != nil {
+			return fmt.Errorf("invalid default of %w", err)
+		}
+	}
+	return nil
+}
+
+// Normalize validates an answer and converts it to the type stored in the
+// recipe: a string, a []string for multiselect or a bool for confirm.
+func (o *Option) Normalize(value interface{}) (interface{}, error) {
+	switch o.Kind() {
+	case OptionSelect:
+		s, ok := value.(string)
+		if !ok || !contains(o.Values, s) {
+			return nil, fmt.Errorf("option %q must be one of %s, got %v", o.Name, quoteAll(o.Values), value)
+		}
+		return s, nil
+
+	case OptionMultiSelect:
+		var list []string
+		switch v := value.(type) {
+		case []string:
+			list = v
+		case []interface{}:
+			for _, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("option %q must be a list of strings, got %v", o.Name, value)
+				}
+				list = append(list, s)
+			}
+		default:
+			return nil, fmt.Errorf("option %q must be a list, got %v", o.Name, value)
+		}
+		for _, s := range list {
+			if !contains(o.Values, s) {
+				return nil, fmt.Errorf("option %q only accepts %s, got %q", o.Name, quoteAll(o.Values), s)
+			}
+		}
+		return list, nil
+
+	case OptionConfirm:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("option %q must be true or false, got %v", o.Name, value)
+		}
+		return b, nil
+
+	default:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("option %q must be text, got %v", o.Name, value)
+		}
+		if o.Pattern != "" && !regexp.MustCompile(o.Pattern).MatchString(s) {
+			return nil, fmt.Errorf("option %q must match %s, got %q", o.Name, o.Pattern, s)
+		}
+		return s, nil
+	}
+}
+
+// ApplyOptions checks the stack answers of r against the options, in order,
+// and fills in the defaults of unanswered options that apply. Options whose
+// condition does not hold are ignored. Every problem found is reported.
+func (o Options) ApplyOptions(r *recipe.Recipe) error {
+	var problems []string
+	for i := range o {
+		option := &o[i]
+		if err := option.Check(); err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		applies, err := option.Applies(r)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		if !applies {
+			continue
+		}
+
+		value, ok := r.Stack.Get(option.Name)
+		if !ok {
+			value = option.DefaultValue()
+			if value == nil {
+				problems = append(problems, fmt.Sprintf("option %q is required", option.Name))
+				continue
+			}
+		}
+		normalized, err := option.Normalize(value)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		if err := r.Stack.Set(option.Name, normalized); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid stack options:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	return strings.Join(quoted, ", ")
+}
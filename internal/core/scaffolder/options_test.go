@@ -0,0 +1,165 @@
+package scaffolder
+
+import (
+	"grei-cli/internal/core/recipe"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const optionsManifest = `
+name: test-stack
+options:
+  persistence:
+    message: "Persistence?"
+    values: ["None", "PostgreSQL", "MySQL"]
+  dbVersion:
+    type: input
+    pattern: '^[0-9]+(\.[0-9]+)*$'
+    when: stack.persistence != "None"
+  features:
+    type: multiselect
+    values: ["auth", "metrics", "tracing"]
+    default: ["metrics"]
+  docker:
+    type: confirm
+    default: true
+`
+
+func parseOptions(t *testing.T, text string) Options {
+	t.Helper()
+	var manifest Manifest
+	if err := yaml.Unmarshal([]byte(text), &manifest); err != nil {
+		t.Fatalf("Failed to parse the manifest: %v", err)
+	}
+	return manifest.Options
+}
+
+func TestOptions_MappingKeepsFileOrder(t *testing.T) {
+	options := parseOptions(t, optionsManifest)
+
+	var names []string
+	for _, option := range options {
+		names = append(names, option.Name)
+	}
+	want := []string{"persistence", "dbVersion", "features", "docker"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Options are %v, want %v", names, want)
+	}
+	if options[0].Kind() != OptionSelect || options[1].Kind() != OptionInput {
+		t.Errorf("Unexpected kinds %q and %q", options[0].Kind(), options[1].Kind())
+	}
+}
+
+func TestOptions_SequenceForm(t *testing.T) {
+	options := parseOptions(t, `
+options:
+  - name: ci
+    values: ["None", "GitHub Actions"]
+  - name: docker
+    type: confirm
+`)
+	if len(options) != 2 || options[0].Name != "ci" || options[1].Kind() != OptionConfirm {
+		t.Errorf("Unexpected options %+v", options)
+	}
+}
+
+func TestApplyOptions_DefaultsAndConditions(t *testing.T) {
+	options := parseOptions(t, optionsManifest)
+	r := &recipe.Recipe{Stack: recipe.Stack{Persistence: "None"}}
+
+	if err := options.ApplyOptions(r); err != nil {
+		t.Fatalf("ApplyOptions returned an unexpected error: %v", err)
+	}
+	want := recipe.Stack{
+		Persistence: "None",
+		Options: map[string]interface{}{
+			"features": []string{"metrics"},
+			"docker":   true,
+		},
+	}
+	if !reflect.DeepEqual(r.Stack, want) {
+		t.Errorf("Stack = %v, want %v", r.Stack, want)
+	}
+}
+
+func TestApplyOptions_SelectDefaultsToFirstValue(t *testing.T) {
+	options := parseOptions(t, optionsManifest)
+	r := &recipe.Recipe{}
+
+	if err := options.ApplyOptions(r); err != nil {
+		t.Fatalf("ApplyOptions returned an unexpected error: %v", err)
+	}
+	if r.Stack.Persistence != "None" {
+		t.Errorf("persistence = %v, want None", r.Stack.Persistence)
+	}
+}
+
+func TestApplyOptions_ConditionalOptionIsRequired(t *testing.T) {
+	options := parseOptions(t, optionsManifest)
+	r := &recipe.Recipe{Stack: recipe.Stack{Persistence: "PostgreSQL"}}
+
+	err := options.ApplyOptions(r)
+	if err == nil || !strings.Contains(err.Error(), `option "dbVersion" is required`) {
+		t.Fatalf("Expected dbVersion to be required, got %v", err)
+	}
+
+	r.Stack.Set("dbVersion", "16")
+	if err := options.ApplyOptions(r); err != nil {
+		t.Errorf("ApplyOptions returned an unexpected error: %v", err)
+	}
+}
+
+func TestApplyOptions_ReportsEveryProblem(t *testing.T) {
+	options := parseOptions(t, optionsManifest)
+	var r recipe.Recipe
+	if err := yaml.Unmarshal([]byte(`
+stack:
+  persistence: Oracle
+  dbVersion: latest
+  features: [auth, logging]
+  docker: "yes"
+`), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	err := options.ApplyOptions(&r)
+	if err == nil {
+		t.Fatal("ApplyOptions should fail")
+	}
+	for _, want := range []string{`"persistence" must be one of`, `"features" only accepts`, `"docker" must be true or false`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in the error, got:\n%v", want, err)
+		}
+	}
+	// persistence is invalid, so dbVersion is asked and checked as well.
+	if !strings.Contains(err.Error(), `"dbVersion" must match`) {
+		t.Errorf("Expected the dbVersion pattern in the error, got:\n%v", err)
+	}
+}
+
+func TestOption_Check(t *testing.T) {
+	tests := []struct {
+		option Option
+		want   string
+	}{
+		{Option{Name: "a"}, "need values"},
+		{Option{Name: "a", Type: "slider"}, "unknown type"},
+		{Option{Name: "a", Type: OptionInput, Pattern: "("}, "invalid pattern"},
+		{Option{Name: "a", Type: OptionConfirm, When: "stack.x =="}, "invalid expression"},
+		{Option{Name: "a", Values: []string{"x"}, Default: "y"}, "invalid default"},
+	}
+	for _, tt := range tests {
+		err := tt.option.Check()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Check(%+v) = %v, want an error containing %q", tt.option, err, tt.want)
+		}
+	}
+
+	valid := Option{Name: "a", Type: OptionInput, Pattern: "^[a-z]+$", Default: "abc"}
+	if err := valid.Check(); err != nil {
+		t.Errorf("Check returned an unexpected error: %v", err)
+	}
+}
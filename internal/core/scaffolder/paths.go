@@ -0,0 +1,42 @@
+package scaffolder
+
+import (
+	"bytes"
+	"fmt"
+	"grei-cli/internal/templates"
+	"path/filepath"
+	"strings"
+)
+
+// renderPath renders the text/template expressions in each segment of the
+// slash-separated relative path, e.g. "cmd/{{ ToKebab .Project.Name }}/main.go".
+// Rendered segments must be plain names, so the result stays inside the
+// target directory.
+func renderPath(relativePath string, data interface{}) (string, error) {
+	if !strings.Contains(relativePath, "{{") {
+		return relativePath, nil
+	}
+
+	segments := strings.Split(relativePath, "/")
+	for i, segment := range segments {
+		if !strings.Contains(segment, "{{") {
+			continue
+		}
+		tmpl, err := templates.Parse(segment, segment)
+		if err != nil {
+			return "", fmt.Errorf("could not parse path %s: %w", relativePath, err)
+		}
+		tmpl.Option("missingkey=error")
+		var name bytes.Buffer
+		if err := tmpl.Execute(&name, data); err != nil {
+			return "", fmt.Errorf("could not render path %s: %w", relativePath, err)
+		}
+
+		rendered := strings.TrimSpace(name.String())
+		if rendered == "" || rendered == "." || rendered == ".." || strings.ContainsAny(rendered, `/\`) || !filepath.IsLocal(rendered) {
+			return "", fmt.Errorf("path %s renders segment %q, which is not a valid file name", relativePath, rendered)
+		}
+		segments[i] = rendered
+	}
+	return strings.Join(segments, "/"), nil
+}
@@ -0,0 +1,95 @@
+package scaffolder
+
+import (
+	"grei-cli/internal/adapters/filesystem"
+	"grei-cli/internal/core/recipe"
+	"grei-cli/internal/templates"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRenderPath(t *testing.T) {
+	data := templates.NewData(recipe.Recipe{
+		Project: recipe.Project{Name: "My Tool"},
+		Stack:   recipe.Stack{Options: map[string]interface{}{"db": "postgres"}},
+	})
+
+	tests := []struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{path: "cmd/app/main.go", want: "cmd/app/main.go"},
+		{path: "cmd/{{ ToKebab .Project.Name }}/main.go", want: "cmd/my-tool/main.go"},
+		{path: "cmd/{{ .Project.Name | ToKebab }}/main.go", want: "cmd/my-tool/main.go"},
+		{path: "db/{{ .Stack.db }}.sql", want: "db/postgres.sql"},
+		{path: "{{ .Project.Customer }}/x", wantErr: true},
+		{path: "{{ .Stack.missing }}/x", wantErr: true},
+		{path: "{{ \"..\" }}/x", wantErr: true},
+		{path: "{{ \"a/../../b\" }}", wantErr: true},
+		{path: "{{ .Project.Name", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := renderPath(tt.path, data)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("renderPath(%q) = %q, expected an error", tt.path, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("renderPath(%q) returned an unexpected error: %v", tt.path, err)
+		} else if got != tt.want {
+			t.Errorf("renderPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestScaffold_TemplatedPaths(t *testing.T) {
+	templatesFS := fstest.MapFS{
+		"skeletons/generic/README.md":                                   {Data: []byte("# README")},
+		"skeletons/cli/manifest.yml":                                    {Data: []byte("name: cli")},
+		"skeletons/cli/cmd/{{ ToKebab .Project.Name }}/main.go.tmpl":    {Data: []byte("package main // {{ .Project.Name }}")},
+		"skeletons/cli/cmd/{{ ToKebab .Project.Name }}/domain/.gitkeep": {Data: nil},
+	}
+	projRecipe := &recipe.Recipe{Project: recipe.Project{Name: "MyTool", Type: "cli"}}
+
+	repo := filesystem.NewMemoryRepository()
+	if err := NewService(repo).Scaffold("project", templatesFS, projRecipe); err != nil {
+		t.Fatalf("Scaffold() returned an unexpected error: %v", err)
+	}
+
+	files := repo.Files()
+	if got := string(files["project/cmd/my-tool/main.go"]); got != "package main // MyTool" {
+		t.Errorf("Expected cmd/my-tool/main.go to be rendered, got %q (files: %v)", got, files)
+	}
+	if _, ok := files["project/cmd/my-tool/domain/.gitkeep"]; !ok {
+		t.Error("Expected files below a templated directory to be generated")
+	}
+	for _, dir := range repo.Dirs() {
+		if strings.Contains(dir, "{{") {
+			t.Errorf("Directory %s was not rendered", dir)
+		}
+	}
+}
+
+func TestScaffold_PathEscape(t *testing.T) {
+	templatesFS := fstest.MapFS{
+		"skeletons/generic/README.md":                {Data: []byte("# README")},
+		"skeletons/cli/manifest.yml":                 {Data: []byte("name: cli")},
+		"skeletons/cli/{{ .Project.Name }}/evil.txt": {Data: []byte("x")},
+	}
+	projRecipe := &recipe.Recipe{Project: recipe.Project{Name: "..", Type: "cli"}}
+
+	repo := filesystem.NewMemoryRepository()
+	if err := NewService(repo).Scaffold("project", templatesFS, projRecipe); err == nil {
+		t.Fatal("Expected a path that escapes the target directory to be rejected")
+	}
+	for path := range repo.Files() {
+		if !strings.HasPrefix(path, "project/") {
+			t.Errorf("File written outside the target directory: %s", path)
+		}
+	}
+}
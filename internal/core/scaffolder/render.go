@@ -0,0 +1,148 @@
+package scaffolder
+
+import (
+	"bytes"
+	"fmt"
+	"grei-cli/internal/core/expr"
+	"grei-cli/internal/core/glob"
+	"grei-cli/internal/templates"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+const genericSkeleton = "skeletons/generic"
+
+// layer is a skeleton applied on top of the previous ones. Files of later
+// layers replace files of earlier layers with the same output path.
+type layer struct {
+	dir      string
+	manifest *Manifest
+}
+
+// projectPlan maps every output path of a project, relative and
+// slash-separated, to the template that produces it.
+type projectPlan struct {
+	files       map[string]string
+	directories map[string]bool
+}
+
+// paths returns the output files in lexical order.
+func (p *projectPlan) paths() []string {
+	paths := make([]string, 0, len(p.files))
+	for file := range p.files {
+		paths = append(paths, file)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// dirs returns the output directories in lexical order, so parents come
+// before their children.
+func (p *projectPlan) dirs() []string {
+	dirs := make([]string, 0, len(p.directories))
+	for dir := range p.directories {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// plan walks the layers once, in order, and decides which template produces
+// each file of the project. Nothing is rendered yet, so a file replaced by a
+// later layer is never rendered.
+func plan(templatesFS fs.FS, layers []layer, data templates.Data) (*projectPlan, error) {
+	project := &projectPlan{files: make(map[string]string), directories: make(map[string]bool)}
+	vars := data.Recipe.Vars()
+
+	for _, l := range layers {
+		var rules []FileRule
+		if l.manifest != nil {
+			rules = l.manifest.Files
+		}
+		include, err := fileFilter(rules, vars)
+		if err != nil {
+			return nil, fmt.Errorf("invalid file rules in %s/manifest.yml: %w", l.dir, err)
+		}
+
+		err = fs.WalkDir(templatesFS, l.dir, func(templatePath string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if templatePath == l.dir || d.Name() == "manifest.yml" {
+				return nil
+			}
+
+			// The path in the project, as named in the templates.
+			relativePath := strings.TrimSuffix(strings.TrimPrefix(templatePath, l.dir+"/"), ".tmpl")
+			if !include(relativePath) {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+
+			relativePath, err = renderPath(relativePath, data)
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				project.directories[relativePath] = true
+			} else {
+				project.files[relativePath] = templatePath
+				if dir := path.Dir(relativePath); dir != "." {
+					project.directories[dir] = true
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return project, nil
+}
+
+// fileFilter evaluates the file rules of a skeleton, and returns whether a
+// path of the skeleton should be generated.
+func fileFilter(rules []FileRule, vars map[string]string) (func(path string) bool, error) {
+	var excluded []string
+	for _, rule := range rules {
+		condition, err := expr.Compile(rule.When)
+		if err != nil {
+			return nil, err
+		}
+		if !condition.Eval(vars) {
+			excluded = append(excluded, rule.Paths...)
+		}
+	}
+
+	return func(path string) bool {
+		for _, pattern := range excluded {
+			if glob.Match(pattern, path) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// renderFile executes the template at templatePath with data.
+func renderFile(templatesFS fs.FS, templatePath string, data templates.Data) ([]byte, error) {
+	rawContent, err := fs.ReadFile(templatesFS, templatePath)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := templates.Parse(path.Base(templatePath), string(rawContent))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse template %s: %w", templatePath, err)
+	}
+
+	var content bytes.Buffer
+	if err := tmpl.Execute(&content, data); err != nil {
+		return nil, fmt.Errorf("could not execute template %s: %w", templatePath, err)
+	}
+	return content.Bytes(), nil
+}
@@ -1,24 +1,18 @@
 package scaffolder
 
 import (
-	"bytes"
 	"fmt"
+	"grei-cli/internal/core/policy"
 	"grei-cli/internal/core/recipe"
 	"grei-cli/internal/ports/inbound"
 	"grei-cli/internal/ports/outbound"
+	"grei-cli/internal/templates"
 	"io/fs"
-	"os"
 	"path/filepath"
-	"strings"
-	"text/template"
 
 	"gopkg.in/yaml.v3"
 )
 
-const (
-	templatesDir = ".grei-cli/templates"
-)
-
 type service struct {
 	fsRepo outbound.FSRepository
 }
@@ -35,117 +29,141 @@ type Manifest struct {
 		DependencyManagement string `yaml:"dependencyManagement"`
 		BuildReleaseRun      string `yaml:"buildReleaseRun"`
 	} `yaml:"provides"`
-	Options map[string]struct {
-		Message string   `yaml:"message"`
-		Values  []string `yaml:"values"`
-	} `yaml:"options"`
+	Options      Options              `yaml:"options"`
+	Required     []policy.Requirement `yaml:"required"`
+	Architecture policy.Architecture  `yaml:"architecture"`
+	Files        []FileRule           `yaml:"files"`
+	// Extends names the skeleton this one builds on. Its files are applied
+	// first, so this skeleton can replace them.
+	Extends string `yaml:"extends"`
+	// Mixins name reusable skeletons (type: mixin) applied after Extends and
+	// before this skeleton.
+	Mixins []Mixin `yaml:"mixins"`
+	// PostGenerate lists the commands run in a new project once its files
+	// are rendered, such as "npm install".
+	PostGenerate []Step `yaml:"postGenerate"`
+	// Standard lists the files and directories of the generic skeleton that
+	// init --adopt adds to existing projects. The rest, such as README.md,
+	// only belongs to new projects.
+	Standard []string `yaml:"standard"`
 }
 
-func NewService(fsRepo outbound.FSRepository) inbound.ScaffolderService {
-	return &service{
-		fsRepo: fsRepo,
+// Mixin is a skeleton included by another one, optionally only when a
+// condition on the recipe holds. In manifest.yml it is either a name or a
+// mapping:
+//
+//	mixins:
+//	  - helm
+//	  - name: postgres
+//	    when: stack.persistence == "PostgreSQL"
+type Mixin struct {
+	Name string `yaml:"name"`
+	When string `yaml:"when"`
+}
+
+func (m *Mixin) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		m.Name = value.Value
+		return nil
 	}
+	type plain Mixin
+	return value.Decode((*plain)(m))
 }
 
-func (s *service) GetTemplates() ([]fs.DirEntry, error) {
-	cacheDir, err := s.fsRepo.GetCacheDir(templatesDir)
-	if err != nil {
-		return nil, err
+// Step is a command run in a new project after it is rendered. In
+// manifest.yml it is either a command or a mapping:
+//
+//	postGenerate:
+//	  - go mod tidy
+//	  - name: Install the PHP dependencies
+//	    run: composer install --no-interaction
+//	    dir: backend
+//	    when: stack.backend == "Symfony"
+//	    timeout: 15m
+//
+// Run is run by the system shell in Dir, relative to the project. Timeout
+// is a Go duration; steps without one get the default of the command.
+type Step struct {
+	Name    string `yaml:"name"`
+	Run     string `yaml:"run"`
+	Dir     string `yaml:"dir"`
+	When    string `yaml:"when"`
+	Timeout string `yaml:"timeout"`
+}
+
+func (s *Step) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		s.Run = value.Value
+		return nil
 	}
-	return os.ReadDir(cacheDir)
+	type plain Step
+	return value.Decode((*plain)(s))
 }
 
-func (s *service) GetTemplateFile(path string) ([]byte, error) {
-	cacheDir, err := s.fsRepo.GetCacheDir(templatesDir)
-	if err != nil {
-		return nil, err
+// FileRule includes the files of a skeleton matching Paths only when the
+// When condition holds for the recipe, e.g.
+//
+//	files:
+//	  - paths: [".github/**"]
+//	    when: stack.ci == "GitHub Actions"
+//
+// Paths are globs relative to the skeleton, as named in the templates but
+// without the .tmpl suffix. A file matched by several rules is included
+// only when all of them hold.
+type FileRule struct {
+	Paths []string `yaml:"paths"`
+	When  string   `yaml:"when"`
+}
+
+func NewService(fsRepo outbound.FSRepository) inbound.ScaffolderService {
+	return &service{
+		fsRepo: fsRepo,
 	}
-	return s.fsRepo.ReadFile(filepath.Join(cacheDir, path))
 }
 
-func (s *service) Scaffold(path, cacheDir string, recipe *recipe.Recipe) error {
+func (s *service) Scaffold(path string, templatesFS fs.FS, recipe *recipe.Recipe) error {
 	fmt.Printf("\n[i] Scaffolding templates for a '%s' project...\n", recipe.Project.Type)
 
-	var skeletons []string
-	skeletons = append(skeletons, filepath.Join(cacheDir, "templates", "skeletons", "generic"))
-
-	skeletonsRoot := filepath.Join(cacheDir, "templates", "skeletons")
-	err := filepath.Walk(skeletonsRoot, func(path string, info fs.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
+	layers, err := resolveLayers(templatesFS, recipe)
+	if err != nil {
+		return err
+	}
+	return s.render(path, templatesFS, layers, recipe)
+}
 
-		if !info.IsDir() && info.Name() == "manifest.yml" {
-			manifestFile, err := s.fsRepo.ReadFile(path)
-			if err != nil {
-				return err
-			}
+func (s *service) ScaffoldComponent(path string, templatesFS fs.FS, recipe *recipe.Recipe) error {
+	fmt.Printf("\n[i] Scaffolding templates for a '%s' component...\n", recipe.Project.Type)
 
-			var manifest Manifest
-			if err := yaml.Unmarshal(manifestFile, &manifest); err != nil {
-				return err
-			}
+	layers, err := resolveLayers(templatesFS, recipe)
+	if err != nil {
+		return err
+	}
+	// The generic skeleton holds repository-level files, such as LICENSE
+	// and .githooks, which the root of the monorepo already has.
+	return s.render(path, templatesFS, layers[1:], recipe)
+}
 
-			if manifest.Name == recipe.Project.Type {
-				skeletons = append(skeletons, filepath.Dir(path))
-			}
-		}
-		return nil
-	})
+// render writes the files of layers, planned in a single pass, into path.
+func (s *service) render(path string, templatesFS fs.FS, layers []layer, recipe *recipe.Recipe) error {
+	data := templates.NewData(*recipe)
+	project, err := plan(templatesFS, layers, data)
 	if err != nil {
 		return err
 	}
 
-	for _, skeleton := range skeletons {
-		if err := s.copyTemplates(skeleton, path, recipe); err != nil {
+	for _, dir := range project.dirs() {
+		if err := s.fsRepo.CreateDir(filepath.Join(path, filepath.FromSlash(dir))); err != nil {
 			return err
 		}
 	}
-
-	return nil
-}
-
-func (s *service) copyTemplates(sourceDir, targetDir string, recipe *recipe.Recipe) error {
-	return filepath.Walk(sourceDir, func(templatePath string, info fs.FileInfo, err error) error {
+	for _, file := range project.paths() {
+		content, err := renderFile(templatesFS, project.files[file], data)
 		if err != nil {
 			return err
 		}
-
-		if info.Name() == "manifest.yml" {
-			return nil
-		}
-
-		// The path in the target directory.
-		relativePath := strings.TrimPrefix(templatePath, sourceDir)
-		relativePath = strings.TrimSuffix(relativePath, ".tmpl")
-
-		targetPath := filepath.Join(targetDir, relativePath)
-
-		if info.IsDir() {
-			return os.MkdirAll(targetPath, 0755)
-		}
-
-		// Read the template file.
-		rawContent, err := s.fsRepo.ReadFile(templatePath)
-		if err != nil {
+		if err := s.fsRepo.CreateFile(filepath.Join(path, filepath.FromSlash(file)), content); err != nil {
 			return err
 		}
-
-		// Execute the template to replace variables like {{ .Project.Name }}
-		funcMap := template.FuncMap{
-			"ToLower": strings.ToLower,
-		}
-		tmpl, err := template.New(info.Name()).Funcs(funcMap).Parse(string(rawContent))
-		if err != nil {
-			return fmt.Errorf("could not parse template %s: %w", templatePath, err)
-		}
-
-		var processedContent bytes.Buffer
-		if err := tmpl.Execute(&processedContent, recipe); err != nil {
-			return fmt.Errorf("could not execute template %s: %w", templatePath, err)
-		}
-
-		// Write the file to the target directory.
-		return s.fsRepo.CreateFile(targetPath, processedContent.Bytes())
-	})
+	}
+	return nil
 }
@@ -5,8 +5,11 @@ import (
 	"grei-cli/internal/core/recipe"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 )
 
 func TestScaffold_GoCli(t *testing.T) {
@@ -34,14 +37,13 @@ type: generic
 			Name: "TestCliProject",
 			Type: "go-cli",
 		},
-		Stack: make(map[string]interface{}),
 	}
 
 	service := NewService(fsMock)
 	tmpDir := fsMock.TempDir()
 
 	// Act
-	err := service.Scaffold(tmpDir, fsMock.TempDir(), projRecipe)
+	err := service.Scaffold(tmpDir, os.DirFS(fsMock.TempDir()), projRecipe)
 
 	// Assert
 	if err != nil {
@@ -102,16 +104,14 @@ type: generic
 			Name: "TestPostgresProject",
 			Type: "postgresql",
 		},
-		Stack: map[string]interface{}{
-			"persistence": "postgresql",
-		},
+		Stack: recipe.Stack{Persistence: "postgresql"},
 	}
 
 	service := NewService(fsMock)
 	tmpDir := fsMock.TempDir()
 
 	// Act
-	err := service.Scaffold(tmpDir, fsMock.TempDir(), projRecipe)
+	err := service.Scaffold(tmpDir, os.DirFS(fsMock.TempDir()), projRecipe)
 
 	// Assert
 	if err != nil {
@@ -138,3 +138,163 @@ type: generic
 		t.Errorf("Expected docker-compose.yml to contain the correct password, but it did not.")
 	}
 }
+
+func TestScaffold_FileRules(t *testing.T) {
+	templatesFS := fstest.MapFS{
+		"skeletons/generic/README.md.tmpl": {Data: []byte("# {{ .Project.Name }}")},
+		"skeletons/web/app/manifest.yml": {Data: []byte(`
+name: app
+files:
+  - paths: [".github/**"]
+    when: stack.ci == "GitHub Actions"
+  - paths: ["bitbucket-pipelines.yml"]
+    when: stack.ci == "Bitbucket Pipelines"
+  - paths: ["src/ormconfig.ts"]
+    when: stack.persistence in ["PostgreSQL", "MySQL"]
+`)},
+		"skeletons/web/app/.github/workflows/ci.yml.tmpl": {Data: []byte("name: ci")},
+		"skeletons/web/app/bitbucket-pipelines.yml.tmpl":  {Data: []byte("pipelines:")},
+		"skeletons/web/app/src/ormconfig.ts.tmpl":         {Data: []byte("export default {}")},
+		"skeletons/web/app/src/main.ts":                   {Data: []byte("main()")},
+	}
+
+	tests := []struct {
+		name    string
+		stack   recipe.Stack
+		present []string
+		absent  []string
+	}{
+		{
+			name:    "github and postgres",
+			stack:   recipe.Stack{CI: "GitHub Actions", Persistence: "PostgreSQL"},
+			present: []string{".github/workflows/ci.yml", "src/ormconfig.ts", "src/main.ts"},
+			absent:  []string{"bitbucket-pipelines.yml"},
+		},
+		{
+			name:    "none",
+			stack:   recipe.Stack{CI: "None", Persistence: "None"},
+			present: []string{"README.md", "src/main.ts"},
+			absent:  []string{".github/workflows/ci.yml", ".github", "bitbucket-pipelines.yml", "src/ormconfig.ts"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := filesystem.NewMemoryRepository()
+			projRecipe := &recipe.Recipe{
+				Project: recipe.Project{Name: "app", Type: "app"},
+				Stack:   tt.stack,
+			}
+
+			if err := NewService(repo).Scaffold("project", templatesFS, projRecipe); err != nil {
+				t.Fatalf("Scaffold() returned an unexpected error: %v", err)
+			}
+
+			files := repo.Files()
+			dirs := strings.Join(repo.Dirs(), " ")
+			for _, f := range tt.present {
+				if _, ok := files["project/"+f]; !ok {
+					t.Errorf("Expected %s to be generated", f)
+				}
+			}
+			for _, f := range tt.absent {
+				if _, ok := files["project/"+f]; ok {
+					t.Errorf("Expected %s to be left out", f)
+				}
+				if strings.Contains(" "+dirs+" ", " project/"+f+" ") {
+					t.Errorf("Expected directory %s to be left out", f)
+				}
+			}
+		})
+	}
+}
+
+func TestScaffold_InvalidFileRule(t *testing.T) {
+	templatesFS := fstest.MapFS{
+		"skeletons/generic/README.md": {Data: []byte("# README")},
+		"skeletons/app/manifest.yml": {Data: []byte(`
+name: app
+files:
+  - paths: ["x"]
+    when: stack.ci = "GitHub Actions"
+`)},
+	}
+	projRecipe := &recipe.Recipe{Project: recipe.Project{Name: "app", Type: "app"}}
+
+	err := NewService(filesystem.NewMemoryRepository()).Scaffold("project", templatesFS, projRecipe)
+	if err == nil || !strings.Contains(err.Error(), "skeletons/app/manifest.yml") {
+		t.Errorf("Expected an error naming the manifest, got %v", err)
+	}
+}
+
+func TestScaffold_SinglePass(t *testing.T) {
+	templatesFS := fstest.MapFS{
+		"skeletons/generic/manifest.yml":                   {Data: []byte("name: generic")},
+		"skeletons/generic/LICENSE.tmpl":                   {Data: []byte("Copyright {{ .Year }} {{ .Project.Customer }}")},
+		"skeletons/generic/README.md.tmpl":                 {Data: []byte("{{ Required \"generic README must not be rendered\" .Stack.unset }}")},
+		"skeletons/generic/.vscode/settings.json":          {Data: []byte(`{"name": {{ Quote .Project.Name }}}`)},
+		"skeletons/app/manifest.yml":                       {Data: []byte("name: app")},
+		"skeletons/app/README.md.tmpl":                     {Data: []byte("# {{ .Project.Name | ToKebab }}")},
+		"skeletons/app/src/{{ ToSnake .Project.Name }}.py": {Data: []byte("")},
+	}
+	projRecipe := &recipe.Recipe{Project: recipe.Project{Name: "My App", Customer: "Acme", Type: "app"}}
+
+	repo := filesystem.NewMemoryRepository()
+	if err := NewService(repo).Scaffold("project", templatesFS, projRecipe); err != nil {
+		t.Fatalf("Scaffold() returned an unexpected error: %v", err)
+	}
+
+	files := repo.Files()
+	expected := map[string]string{
+		"project/LICENSE":               "Copyright " + strconv.Itoa(time.Now().Year()) + " Acme",
+		"project/README.md":             "# my-app",
+		"project/.vscode/settings.json": `{"name": "My App"}`,
+		"project/src/my_app.py":         "",
+	}
+	for path, want := range expected {
+		got, ok := files[path]
+		if !ok {
+			t.Errorf("Expected %s to be generated", path)
+		} else if string(got) != want {
+			t.Errorf("Expected %s to be %q, got %q", path, want, got)
+		}
+	}
+	if _, ok := files["project/manifest.yml"]; ok {
+		t.Error("Manifests should not be copied into the project")
+	}
+	if len(files) != len(expected) {
+		t.Errorf("Unexpected files generated: %v", files)
+	}
+}
+
+func TestScaffoldComponent_LeavesGenericFilesToTheRoot(t *testing.T) {
+	templatesFS := fstest.MapFS{
+		"skeletons/generic/manifest.yml":         {Data: []byte("name: generic")},
+		"skeletons/generic/LICENSE.tmpl":         {Data: []byte("Copyright {{ .Project.Customer }}")},
+		"skeletons/generic/.githooks/pre-commit": {Data: []byte("#!/bin/sh")},
+		"skeletons/helm/manifest.yml":            {Data: []byte("name: helm\ntype: mixin")},
+		"skeletons/helm/deploy/Chart.yaml":       {Data: []byte("name: {{ .Project.Name }}")},
+		"skeletons/app/manifest.yml":             {Data: []byte("name: app\nmixins: [helm]")},
+		"skeletons/app/src/main.ts":              {Data: []byte("app")},
+	}
+	projRecipe := &recipe.Recipe{Project: recipe.Project{Name: "web", Customer: "Acme", Type: "app"}}
+
+	repo := filesystem.NewMemoryRepository()
+	if err := NewService(repo).ScaffoldComponent("apps/web", templatesFS, projRecipe); err != nil {
+		t.Fatalf("ScaffoldComponent() returned an unexpected error: %v", err)
+	}
+
+	files := repo.Files()
+	want := map[string]string{
+		"apps/web/src/main.ts":       "app",
+		"apps/web/deploy/Chart.yaml": "name: web",
+	}
+	if len(files) != len(want) {
+		t.Errorf("ScaffoldComponent() generated %v, want only the stack and its mixins", files)
+	}
+	for path, content := range want {
+		if string(files[path]) != content {
+			t.Errorf("Expected %s to be %q, got %q", path, content, files[path])
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package scaffolder
+
+import (
+	"fmt"
+	"grei-cli/internal/core/expr"
+	"grei-cli/internal/core/recipe"
+	"grei-cli/internal/ports/inbound"
+	"grei-cli/internal/templates"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+)
+
+// PostGenerateSteps returns the post-generate steps of the skeletons the
+// recipe's stack is made of, generic first, leaving out those whose
+// condition does not hold. Dir may use template expressions, like the paths
+// of template files.
+func (s *service) PostGenerateSteps(templatesFS fs.FS, recipe *recipe.Recipe) ([]inbound.PostStep, error) {
+	layers, err := resolveLayers(templatesFS, recipe)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := recipe.Vars()
+	data := templates.NewData(*recipe)
+	var steps []inbound.PostStep
+	for _, l := range layers {
+		if l.manifest == nil {
+			continue
+		}
+		for i, step := range l.manifest.PostGenerate {
+			if strings.TrimSpace(step.Run) == "" {
+				return nil, fmt.Errorf("post-generate step %d in %s/manifest.yml has no command", i+1, l.dir)
+			}
+			if step.When != "" {
+				include, err := expr.Eval(step.When, vars)
+				if err != nil {
+					return nil, fmt.Errorf("invalid condition for post-generate step %q in %s/manifest.yml: %w", step.Run, l.dir, err)
+				}
+				if !include {
+					continue
+				}
+			}
+
+			postStep, err := resolveStep(step, data)
+			if err != nil {
+				return nil, fmt.Errorf("post-generate step %q in %s/manifest.yml: %w", step.Run, l.dir, err)
+			}
+			steps = append(steps, postStep)
+		}
+	}
+	return steps, nil
+}
+
+func resolveStep(step Step, data interface{}) (inbound.PostStep, error) {
+	postStep := inbound.PostStep{Name: step.Name, Run: step.Run, Dir: "."}
+	if postStep.Name == "" {
+		postStep.Name = step.Run
+	}
+	if step.Dir != "" {
+		dir, err := renderPath(step.Dir, data)
+		if err != nil {
+			return inbound.PostStep{}, err
+		}
+		dir = path.Clean(dir)
+		if path.IsAbs(dir) || dir == ".." || strings.HasPrefix(dir, "../") {
+			return inbound.PostStep{}, fmt.Errorf("dir %q must be inside the project", step.Dir)
+		}
+		postStep.Dir = dir
+	}
+	if step.Timeout != "" {
+		timeout, err := time.ParseDuration(step.Timeout)
+		if err != nil || timeout <= 0 {
+			return inbound.PostStep{}, fmt.Errorf("timeout %q is not a positive duration such as 5m", step.Timeout)
+		}
+		postStep.Timeout = timeout
+	}
+	return postStep, nil
+}
@@ -0,0 +1,77 @@
+package scaffolder
+
+import (
+	"grei-cli/internal/adapters/filesystem"
+	"grei-cli/internal/core/recipe"
+	"grei-cli/internal/ports/inbound"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func stepTemplates(app string) fstest.MapFS {
+	return fstest.MapFS{
+		"skeletons/generic/manifest.yml": {Data: []byte("name: generic\npostGenerate:\n  - git add --all\n")},
+		"skeletons/mixins/postgres/manifest.yml": {Data: []byte(`
+name: postgres
+type: mixin
+postGenerate:
+  - name: Start the database
+    run: docker compose up -d db
+`)},
+		"skeletons/app/manifest.yml": {Data: []byte(app)},
+	}
+}
+
+func TestPostGenerateSteps(t *testing.T) {
+	templatesFS := stepTemplates(`
+name: app
+mixins:
+  - name: postgres
+    when: stack.persistence == "PostgreSQL"
+postGenerate:
+  - npm install
+  - name: Build the CLI
+    run: go build ./...
+    dir: cmd/{{ ToKebab .Project.Name }}
+    timeout: 5m
+  - run: npx prisma generate
+    when: stack.orm == "Prisma"
+`)
+	r := &recipe.Recipe{Project: recipe.Project{Name: "My Tool", Type: "app"}}
+	r.Stack.Persistence = "PostgreSQL"
+
+	steps, err := NewService(filesystem.NewMemoryRepository()).PostGenerateSteps(templatesFS, r)
+	if err != nil {
+		t.Fatalf("PostGenerateSteps() returned an unexpected error: %v", err)
+	}
+	want := []inbound.PostStep{
+		{Name: "git add --all", Run: "git add --all", Dir: "."},
+		{Name: "Start the database", Run: "docker compose up -d db", Dir: "."},
+		{Name: "npm install", Run: "npm install", Dir: "."},
+		{Name: "Build the CLI", Run: "go build ./...", Dir: "cmd/my-tool", Timeout: 5 * time.Minute},
+	}
+	if !reflect.DeepEqual(steps, want) {
+		t.Errorf("PostGenerateSteps() = %+v, want %+v", steps, want)
+	}
+}
+
+func TestPostGenerateSteps_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"no command": "name: app\npostGenerate:\n  - name: Nothing\n",
+		"outside":    "name: app\npostGenerate:\n  - run: ls\n    dir: ../other\n",
+		"timeout":    "name: app\npostGenerate:\n  - run: ls\n    timeout: soon\n",
+		"condition":  "name: app\npostGenerate:\n  - run: ls\n    when: stack.ci ==\n",
+	}
+	for name, manifest := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &recipe.Recipe{Project: recipe.Project{Name: "tool", Type: "app"}}
+			_, err := NewService(filesystem.NewMemoryRepository()).PostGenerateSteps(stepTemplates(manifest), r)
+			if err == nil || !strings.Contains(err.Error(), "skeletons/app/manifest.yml") {
+				t.Errorf("PostGenerateSteps() = %v, want an error naming the manifest", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+// Package schema describes grei.yml as a JSON Schema and validates recipes
+// against it, reporting where in the file each problem is.
+package schema
+
+import (
+	"encoding/json"
+	"grei-cli/internal/core/recipe"
+	"grei-cli/internal/core/scaffolder"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	draft = "https://json-schema.org/draft/2020-12/schema"
+	// id names the schema after the recipe apiVersion it describes.
+	id = "urn:greicodex:recipe:" + recipe.APIVersion
+)
+
+// Schema is the subset of JSON Schema used to describe recipes.
+type Schema struct {
+	Draft                string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Const                string             `json:"const,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	If                   *Schema            `json:"if,omitempty"`
+	Then                 *Schema            `json:"then,omitempty"`
+
+	never bool
+}
+
+// False is the schema no value matches, used to forbid unknown properties.
+var False = &Schema{never: true}
+
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	if s.never {
+		return []byte("false"), nil
+	}
+	type plain Schema
+	return json.Marshal((*plain)(s))
+}
+
+// Stack is a stack whose options constrain the stack section of recipes of
+// that project type.
+type Stack struct {
+	Name    string
+	Options scaffolder.Options
+}
+
+// ForRecipe returns the schema of grei.yml. The structure comes from
+// recipe.Recipe; the stack section of each of stacks is described by its
+// options, and only the declared options are allowed.
+func ForRecipe(stacks []Stack) *Schema {
+	s := FromType(reflect.TypeOf(recipe.Recipe{}))
+	s.Draft = draft
+	s.ID = id
+	s.Title = "grei.yml"
+	s.Description = "Receta de un proyecto de Greicodex."
+	s.Properties["apiVersion"].Const = recipe.APIVersion
+
+	sort.Slice(stacks, func(i, j int) bool { return stacks[i].Name < stacks[j].Name })
+	for _, stack := range stacks {
+		if len(stack.Options) == 0 {
+			continue
+		}
+		s.AllOf = append(s.AllOf, &Schema{
+			If: &Schema{
+				Required: []string{"project"},
+				Properties: map[string]*Schema{
+					"project": {
+						Required:   []string{"type"},
+						Properties: map[string]*Schema{"type": {Const: stack.Name}},
+					},
+				},
+			},
+			Then: &Schema{
+				Properties: map[string]*Schema{"stack": forOptions(stack.Options)},
+			},
+		})
+	}
+	return s
+}
+
+func forOptions(options scaffolder.Options) *Schema {
+	s := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: False,
+	}
+	for _, option := range options {
+		property := &Schema{Description: option.Message, Default: option.Default}
+		switch option.Kind() {
+		case scaffolder.OptionSelect:
+			property.Type = "string"
+			property.Enum = option.Values
+		case scaffolder.OptionMultiSelect:
+			property.Type = "array"
+			property.Items = &Schema{Type: "string", Enum: option.Values}
+		case scaffolder.OptionConfirm:
+			property.Type = "boolean"
+		default:
+			property.Type = "string"
+			property.Pattern = option.Pattern
+		}
+		s.Properties[option.Name] = property
+	}
+	return s
+}
+
+// FromType describes a Go type by its yaml tags. Struct fields without
+// omitempty are required; inline fields are merged into their parent, and an
+// inline map accepts any other key.
+func FromType(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		s := &Schema{Type: "object", Properties: make(map[string]*Schema), AdditionalProperties: False}
+		addFields(s, t)
+		return s
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: FromType(t.Elem())}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: FromType(t.Elem())}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	}
+	// interface{} and anything else accept every value.
+	return &Schema{}
+}
+
+func addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(options, "inline") {
+			if field.Type.Kind() == reflect.Map {
+				// The remaining keys of the mapping go to the map.
+				s.AdditionalProperties = FromType(field.Type.Elem())
+			} else {
+				addFields(s, field.Type)
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		s.Properties[name] = FromType(field.Type)
+		if !strings.Contains(options, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package schema
+
+import (
+	"encoding/json"
+	"grei-cli/internal/core/scaffolder"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testStacks() []Stack {
+	return []Stack{
+		{Name: "golang-cli"},
+		{Name: "typescript-express-fullstack", Options: scaffolder.Options{
+			{Name: "linter", Message: "Linter?", Values: []string{"ESLint"}},
+			{Name: "persistence", Values: []string{"None", "PostgreSQL"}},
+			{Name: "dbVersion", Type: scaffolder.OptionInput, Pattern: `^[0-9]+$`},
+			{Name: "features", Type: scaffolder.OptionMultiSelect, Values: []string{"auth", "metrics"}},
+			{Name: "docker", Type: scaffolder.OptionConfirm, Default: true},
+		}},
+	}
+}
+
+func TestForRecipe_Structure(t *testing.T) {
+	s := ForRecipe(testStacks())
+
+	if s.Type != "object" || s.Draft == "" || s.ID == "" {
+		t.Fatalf("Unexpected root schema: %+v", s)
+	}
+	if !reflect.DeepEqual(s.Required, []string{"apiVersion", "project"}) {
+		t.Errorf("Required = %v, want [apiVersion project]", s.Required)
+	}
+	if s.Properties["apiVersion"].Const != "grei/v1" {
+		t.Errorf("apiVersion should be grei/v1, got %+v", s.Properties["apiVersion"])
+	}
+	stack := s.Properties["stack"]
+	if stack.Properties["persistence"].Type != "string" || stack.AdditionalProperties == nil || stack.AdditionalProperties == False {
+		t.Errorf("stack should have typed fields and accept other options, got %+v", stack)
+	}
+	project := s.Properties["project"]
+	if !reflect.DeepEqual(project.Required, []string{"name", "customer", "type"}) {
+		t.Errorf("project.Required = %v", project.Required)
+	}
+	templates := s.Properties["templates"]
+	if templates == nil || templates.Properties["commit"].Type != "string" {
+		t.Errorf("templates should be described from recipe.Templates, got %+v", templates)
+	}
+	required := s.Properties["verify"].Properties["required"]
+	if required.Type != "array" || required.Items.Properties["notEmpty"].Type != "boolean" {
+		t.Errorf("verify.required should be an array of requirements, got %+v", required)
+	}
+
+	// Only stacks with options constrain the stack section.
+	if len(s.AllOf) != 1 {
+		t.Fatalf("Expected one stack rule, got %d", len(s.AllOf))
+	}
+	stack = s.AllOf[0].Then.Properties["stack"]
+	if stack.Properties["linter"].Enum[0] != "ESLint" || stack.Properties["features"].Items.Enum == nil ||
+		stack.Properties["docker"].Type != "boolean" || stack.Properties["dbVersion"].Pattern != `^[0-9]+$` {
+		t.Errorf("Unexpected stack schema: %+v", stack.Properties)
+	}
+}
+
+func TestForRecipe_JSON(t *testing.T) {
+	data, err := json.MarshalIndent(ForRecipe(testStacks()), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`"$schema": "https://json-schema.org/draft/2020-12/schema"`,
+		`"additionalProperties": false`,
+		`"const": "typescript-express-fullstack"`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected %s in the schema:\n%s", want, data)
+		}
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Errorf("The schema is not valid JSON: %v", err)
+	}
+}
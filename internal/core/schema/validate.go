@@ -0,0 +1,252 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Error is a problem found in a recipe, with its position in the file.
+type Error struct {
+	Line   int
+	Column int
+	// Path is the dotted location of the value, such as "stack.linter" or
+	// "verify.required[1].path". It is empty for the whole document.
+	Path    string
+	Message string
+}
+
+func (e Error) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("%d:%d: %s: %s", e.Line, e.Column, e.Path, e.Message)
+}
+
+// Errors are every problem found in a recipe, sorted by position.
+type Errors []Error
+
+func (e Errors) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Validate checks the YAML document in data against s. It returns nil when
+// the document is valid, an Errors listing every problem when it is not, or
+// the parse error when data is not YAML at all.
+func Validate(s *Schema, data []byte) error {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return err
+	}
+	if errs := ValidateNode(s, &document); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ValidateNode checks a document parsed with yaml.v3 against s and returns
+// every problem found, sorted by position.
+func ValidateNode(s *Schema, document *yaml.Node) Errors {
+	root := document
+	if root.Kind == yaml.DocumentNode || root.Kind == 0 {
+		if len(root.Content) == 0 {
+			return Errors{{Line: 1, Column: 1, Message: "the recipe is empty"}}
+		}
+		root = root.Content[0]
+	}
+
+	errs := validate(s, root, "")
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Line != errs[j].Line {
+			return errs[i].Line < errs[j].Line
+		}
+		return errs[i].Column < errs[j].Column
+	})
+	return errs
+}
+
+// valid reports whether node matches s.
+func valid(s *Schema, node *yaml.Node) bool {
+	return len(validate(s, node, "")) == 0
+}
+
+func validate(s *Schema, node *yaml.Node, path string) Errors {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	at := func(n *yaml.Node, format string, args ...interface{}) Error {
+		return Error{Line: n.Line, Column: n.Column, Path: path, Message: fmt.Sprintf(format, args...)}
+	}
+
+	if s.never {
+		return Errors{at(node, "is not allowed")}
+	}
+	// An empty value, as in "stack:" with nothing after it, is the same as
+	// leaving the key out.
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return nil
+	}
+
+	var errs Errors
+	if s.Type != "" && kind(node) != s.Type && !(s.Type == "number" && kind(node) == "integer") {
+		return Errors{at(node, "must be %s, got %s", article(s.Type), article(kind(node)))}
+	}
+	if s.Const != "" && node.Value != s.Const {
+		errs = append(errs, at(node, "must be %q", s.Const))
+	}
+	if len(s.Enum) > 0 && !contains(s.Enum, node.Value) {
+		message := fmt.Sprintf("must be one of %s, got %q", quoteAll(s.Enum), node.Value)
+		for _, value := range s.Enum {
+			if strings.EqualFold(value, node.Value) {
+				message += fmt.Sprintf(" (did you mean %q?)", value)
+				break
+			}
+		}
+		errs = append(errs, at(node, "%s", message))
+	}
+	if s.Pattern != "" && node.Kind == yaml.ScalarNode {
+		if pattern, err := regexp.Compile(s.Pattern); err == nil && !pattern.MatchString(node.Value) {
+			errs = append(errs, at(node, "must match %s, got %q", s.Pattern, node.Value))
+		}
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		seen := make(map[string]bool)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			seen[key.Value] = true
+			child := join(path, key.Value)
+			if property, ok := s.Properties[key.Value]; ok {
+				errs = append(errs, validate(property, value, child)...)
+			} else if s.AdditionalProperties != nil {
+				if s.AdditionalProperties.never {
+					errs = append(errs, Error{Line: key.Line, Column: key.Column, Path: child, Message: "is not a known field" + suggest(key.Value, s.Properties)})
+				} else {
+					errs = append(errs, validate(s.AdditionalProperties, value, child)...)
+				}
+			}
+		}
+		for _, name := range s.Required {
+			if !seen[name] {
+				errs = append(errs, at(node, "missing required field %q", name))
+			}
+		}
+	case yaml.SequenceNode:
+		if s.Items != nil {
+			for i, item := range node.Content {
+				errs = append(errs, validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+
+	for _, sub := range s.AllOf {
+		if sub.If != nil && !valid(sub.If, node) {
+			continue
+		}
+		if sub.Then != nil {
+			errs = append(errs, validate(sub.Then, node, path)...)
+		}
+		if sub.If == nil && sub.Then == nil {
+			errs = append(errs, validate(sub, node, path)...)
+		}
+	}
+	return errs
+}
+
+// kind returns the JSON Schema type of node.
+func kind(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	switch node.Tag {
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	case "!!bool":
+		return "boolean"
+	case "!!null":
+		return "null"
+	}
+	return "string"
+}
+
+func article(kind string) string {
+	switch kind {
+	case "object", "array", "integer":
+		return "an " + kind
+	case "null":
+		return kind
+	}
+	return "a " + kind
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// suggest names the known field closest to name, to point out typos.
+func suggest(name string, properties map[string]*Schema) string {
+	best, bestDistance := "", len(name)/2+1
+	for candidate := range properties {
+		if d := distance(strings.ToLower(name), strings.ToLower(candidate)); d < bestDistance || (d == bestDistance && candidate < best) {
+			best, bestDistance = candidate, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean %q?)", best)
+}
+
+// distance is the Levenshtein distance between a and b.
+func distance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,102 @@
+package schema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate_Valid(t *testing.T) {
+	s := ForRecipe(testStacks())
+	for _, recipe := range []string{
+		`
+apiVersion: grei/v1
+project:
+  name: demo
+  customer: Acme
+  type: typescript-express-fullstack
+stack:
+  linter: ESLint
+  persistence: PostgreSQL
+  dbVersion: "16"
+  features: [auth]
+  docker: false
+`,
+		`
+apiVersion: grei/v1
+project: {name: tool, customer: Acme, type: golang-cli}
+stack:
+  anything: goes
+verify:
+  coverage: 90
+  required:
+    - path: LICENSE
+      license: any
+templates:
+  source: embedded
+`,
+		// An empty section is the same as leaving it out.
+		"apiVersion: grei/v1\nproject: {name: a, customer: b, type: c}\nstack:\n",
+	} {
+		if err := Validate(s, []byte(recipe)); err != nil {
+			t.Errorf("Validate returned an unexpected error for:\n%s\n%v", recipe, err)
+		}
+	}
+}
+
+func TestValidate_Locations(t *testing.T) {
+	s := ForRecipe(testStacks())
+	recipe := `apiVersion: grei/v2
+project:
+  name: demo
+  type: typescript-express-fullstack
+stack:
+  linter: eslint
+  persistance: PostgreSQL
+  dbVersion: 16
+  features: [auth, logs]
+  docker: "no"
+verify:
+  coverage: high
+  required:
+    - pth: LICENSE
+`
+	err := Validate(s, []byte(recipe))
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected schema errors, got %v", err)
+	}
+
+	want := []string{
+		`1:13: apiVersion: must be "grei/v1"`,
+		`3:3: project: missing required field "customer"`,
+		`6:11: stack.linter: must be one of "ESLint", got "eslint" (did you mean "ESLint"?)`,
+		`7:3: stack.persistance: is not a known field (did you mean "persistence"?)`,
+		`8:14: stack.dbVersion: must be a string, got an integer`,
+		`9:20: stack.features[1]: must be one of "auth", "metrics", got "logs"`,
+		`10:11: stack.docker: must be a boolean, got a string`,
+		`12:13: verify.coverage: must be an integer, got a string`,
+		`14:7: verify.required[0].pth: is not a known field (did you mean "path"?)`,
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("Expected %d errors, got %d:\n%v", len(want), len(errs), err)
+	}
+	for i, w := range want {
+		if errs[i].Error() != w {
+			t.Errorf("Error %d = %q, want %q", i, errs[i].Error(), w)
+		}
+	}
+}
+
+func TestValidate_NotYAML(t *testing.T) {
+	err := Validate(ForRecipe(nil), []byte("project: [unclosed"))
+	var errs Errors
+	if err == nil || errors.As(err, &errs) {
+		t.Errorf("Expected a parse error, got %v", err)
+	}
+
+	err = Validate(ForRecipe(nil), []byte(""))
+	if err == nil || !strings.Contains(err.Error(), "empty") {
+		t.Errorf("Expected an empty recipe error, got %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package upgrader
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"grei-cli/internal/core/diff"
+	"grei-cli/internal/ports/inbound"
+	"grei-cli/internal/ports/outbound"
+	"io/fs"
+	"path/filepath"
+	"sort"
+)
+
+type service struct {
+	fsRepo outbound.FSRepository
+}
+
+func NewService(fsRepo outbound.FSRepository) inbound.UpgraderService {
+	return &service{
+		fsRepo: fsRepo,
+	}
+}
+
+func (s *service) Upgrade(options inbound.UpgradeOptions) (*inbound.UpgradeReport, error) {
+	if options.DryRun {
+		fmt.Println("\n[i] Dry run: showing the changes without writing them...")
+	} else {
+		fmt.Println("\n[i] Applying template changes...")
+	}
+
+	report := &inbound.UpgradeReport{}
+	for _, path := range templatePaths(options) {
+		change, err := s.upgradeFile(options, path)
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			report.Changes = append(report.Changes, *change)
+			report.Conflicts += change.Conflicts
+		}
+	}
+
+	if len(report.Changes) == 0 {
+		fmt.Println("  [✓] The project already matches the templates.")
+	}
+	return report, nil
+}
+
+// templatePaths returns every file rendered by either version of the
+// templates, sorted.
+func templatePaths(options inbound.UpgradeOptions) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, files := range []map[string][]byte{options.Base, options.Latest} {
+		for path := range files {
+			if !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// upgradeFile brings the template changes of a single file into the project.
+// It returns nil when there is nothing to do.
+func (s *service) upgradeFile(options inbound.UpgradeOptions, path string) (*inbound.FileChange, error) {
+	base, inBase := options.Base[path]
+	latest, inLatest := options.Latest[path]
+	if inBase && inLatest && bytes.Equal(base, latest) {
+		return nil, nil
+	}
+
+	target := filepath.Join(options.Path, filepath.FromSlash(path))
+	current, err := s.fsRepo.ReadFile(target)
+	exists := err == nil
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("could not read %s: %w", path, err)
+	}
+
+	switch {
+	case !inLatest:
+		if !exists {
+			return nil, nil
+		}
+		fmt.Printf("  [i] %s was removed from the templates; delete it if it is no longer needed.\n", path)
+		return &inbound.FileChange{Path: path, Kind: inbound.ChangeRemoved}, nil
+
+	case !exists && inBase:
+		fmt.Printf("  [i] %s was deleted from the project; not restoring it.\n", path)
+		return &inbound.FileChange{Path: path, Kind: inbound.ChangeKeptDelete}, nil
+
+	case !exists:
+		fmt.Printf("  [✓] Added %s\n", path)
+		return &inbound.FileChange{Path: path, Kind: inbound.ChangeAdded}, s.write(options, target, path, nil, latest)
+
+	case bytes.Equal(current, latest):
+		return nil, nil
+
+	case inBase && bytes.Equal(current, base):
+		fmt.Printf("  [✓] Updated %s\n", path)
+		return &inbound.FileChange{Path: path, Kind: inbound.ChangeUpdated}, s.write(options, target, path, current, latest)
+	}
+
+	if diff.IsBinary(current) || diff.IsBinary(base) || diff.IsBinary(latest) {
+		fmt.Printf("  [✗] %s is binary and was modified; keeping the project version.\n", path)
+		return &inbound.FileChange{Path: path, Kind: inbound.ChangeConflict, Conflicts: 1}, nil
+	}
+
+	merged, conflicts := diff.Merge3(base, current, latest, diff.Labels{Ours: "current", Theirs: "templates"})
+	if conflicts == 0 {
+		fmt.Printf("  [✓] Merged %s\n", path)
+		return &inbound.FileChange{Path: path, Kind: inbound.ChangeMerged}, s.write(options, target, path, current, merged)
+	}
+
+	change := &inbound.FileChange{Path: path, Kind: inbound.ChangeConflict, Conflicts: conflicts}
+	if options.Reject {
+		fmt.Printf("  [✗] %s has %d conflicts; template changes written to %s.rej\n", path, conflicts, path)
+		rej := []byte(diff.Unified("a/"+path, "b/"+path, base, latest))
+		return change, s.write(options, target+".rej", path+".rej", nil, rej)
+	}
+	fmt.Printf("  [✗] %s has %d conflicts; resolve the conflict markers.\n", path, conflicts)
+	return change, s.write(options, target, path, current, merged)
+}
+
+// write stores content at target, or prints the diff from current when
+// running dry.
+func (s *service) write(options inbound.UpgradeOptions, target, path string, current, content []byte) error {
+	if options.DryRun {
+		from := "a/" + path
+		if current == nil {
+			from = "/dev/null"
+		}
+		fmt.Print(diff.Unified(from, "b/"+path, current, content))
+		return nil
+	}
+
+	if err := s.fsRepo.CreateDir(filepath.Dir(target)); err != nil {
+		return err
+	}
+	return s.fsRepo.CreateFile(target, content)
+}
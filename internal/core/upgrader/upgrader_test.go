@@ -0,0 +1,161 @@
+package upgrader
+
+import (
+	"grei-cli/internal/adapters/filesystem"
+	"grei-cli/internal/ports/inbound"
+	"strings"
+	"testing"
+)
+
+func newProject(t *testing.T, files map[string]string) *filesystem.MemoryRepository {
+	t.Helper()
+	repo := filesystem.NewMemoryRepository()
+	for name, content := range files {
+		if err := repo.CreateFile("project/"+name, []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return repo
+}
+
+func toBytes(files map[string]string) map[string][]byte {
+	out := make(map[string][]byte, len(files))
+	for name, content := range files {
+		out[name] = []byte(content)
+	}
+	return out
+}
+
+func TestUpgrade(t *testing.T) {
+	base := map[string]string{
+		"Dockerfile":   "FROM node:18\nWORKDIR /app\nCMD [\"npm\", \"start\"]\n",
+		"Makefile":     "test:\n\tnpm test\n",
+		"ci.yml":       "image: node:18\n",
+		"README.md":    "# Project\n",
+		"old.txt":      "obsolete\n",
+		"deleted.yml":  "v1\n",
+		"unchanged.md": "same\n",
+	}
+	latest := map[string]string{
+		"Dockerfile":   "FROM node:20\nWORKDIR /app\nCMD [\"npm\", \"start\"]\n",
+		"Makefile":     "test:\n\tnpm test -- --coverage\n",
+		"ci.yml":       "image: node:20\n",
+		"README.md":    "# Project\n",
+		"deleted.yml":  "v2\n",
+		"unchanged.md": "same\n",
+		"new.txt":      "fresh\n",
+	}
+	current := map[string]string{
+		"Dockerfile":   "FROM node:18\nWORKDIR /app\nRUN npm ci\nCMD [\"npm\", \"start\"]\n",
+		"Makefile":     "test:\n\tnpm test\n",
+		"ci.yml":       "image: node:19\n",
+		"README.md":    "# My project\n",
+		"old.txt":      "obsolete\n",
+		"unchanged.md": "same\n",
+	}
+
+	repo := newProject(t, current)
+	service := NewService(repo)
+	report, err := service.Upgrade(inbound.UpgradeOptions{
+		Path:   "project",
+		Base:   toBytes(base),
+		Latest: toBytes(latest),
+	})
+	if err != nil {
+		t.Fatalf("Upgrade() returned an unexpected error: %v", err)
+	}
+
+	kinds := make(map[string]string)
+	for _, change := range report.Changes {
+		kinds[change.Path] = change.Kind
+	}
+	expectedKinds := map[string]string{
+		"Dockerfile":  inbound.ChangeMerged,
+		"Makefile":    inbound.ChangeUpdated,
+		"ci.yml":      inbound.ChangeConflict,
+		"old.txt":     inbound.ChangeRemoved,
+		"deleted.yml": inbound.ChangeKeptDelete,
+		"new.txt":     inbound.ChangeAdded,
+	}
+	for path, kind := range expectedKinds {
+		if kinds[path] != kind {
+			t.Errorf("Expected %s to be %s, got %q", path, kind, kinds[path])
+		}
+	}
+	if _, ok := kinds["README.md"]; ok {
+		t.Error("README.md did not change in the templates and should be left alone")
+	}
+	if report.Conflicts != 1 {
+		t.Errorf("Expected 1 conflict, got %d", report.Conflicts)
+	}
+
+	files := repo.Files()
+	if got := string(files["project/Dockerfile"]); got != "FROM node:20\nWORKDIR /app\nRUN npm ci\nCMD [\"npm\", \"start\"]\n" {
+		t.Errorf("Unexpected merged Dockerfile:\n%s", got)
+	}
+	if got := string(files["project/ci.yml"]); !strings.Contains(got, "<<<<<<< current\nimage: node:19\n=======\nimage: node:20\n>>>>>>> templates\n") {
+		t.Errorf("Expected conflict markers in ci.yml, got:\n%s", got)
+	}
+	if got := string(files["project/new.txt"]); got != "fresh\n" {
+		t.Errorf("Expected new.txt to be added, got %q", got)
+	}
+	if _, ok := files["project/deleted.yml"]; ok {
+		t.Error("Files deleted from the project should not be restored")
+	}
+	if got := string(files["project/old.txt"]); got != "obsolete\n" {
+		t.Error("Files removed from the templates should be kept")
+	}
+}
+
+func TestUpgrade_Reject(t *testing.T) {
+	repo := newProject(t, map[string]string{"ci.yml": "image: node:19\n"})
+	service := NewService(repo)
+
+	report, err := service.Upgrade(inbound.UpgradeOptions{
+		Path:   "project",
+		Base:   toBytes(map[string]string{"ci.yml": "image: node:18\n"}),
+		Latest: toBytes(map[string]string{"ci.yml": "image: node:20\n"}),
+		Reject: true,
+	})
+	if err != nil {
+		t.Fatalf("Upgrade() returned an unexpected error: %v", err)
+	}
+	if report.Conflicts != 1 {
+		t.Errorf("Expected 1 conflict, got %d", report.Conflicts)
+	}
+
+	files := repo.Files()
+	if got := string(files["project/ci.yml"]); got != "image: node:19\n" {
+		t.Errorf("Conflicting file should be left untouched, got %q", got)
+	}
+	if got := string(files["project/ci.yml.rej"]); !strings.Contains(got, "-image: node:18\n+image: node:20\n") {
+		t.Errorf("Expected the template change in ci.yml.rej, got:\n%s", got)
+	}
+}
+
+func TestUpgrade_DryRun(t *testing.T) {
+	current := map[string]string{"Makefile": "test:\n\tnpm test\n"}
+	repo := newProject(t, current)
+	service := NewService(repo)
+
+	report, err := service.Upgrade(inbound.UpgradeOptions{
+		Path:   "project",
+		Base:   toBytes(current),
+		Latest: toBytes(map[string]string{"Makefile": "test:\n\tnpm test -- --coverage\n", "new.txt": "fresh\n"}),
+		DryRun: true,
+	})
+	if err != nil {
+		t.Fatalf("Upgrade() returned an unexpected error: %v", err)
+	}
+	if len(report.Changes) != 2 {
+		t.Errorf("Expected 2 changes to be reported, got %+v", report.Changes)
+	}
+
+	files := repo.Files()
+	if got := string(files["project/Makefile"]); got != current["Makefile"] {
+		t.Errorf("Dry run should not modify files, got %q", got)
+	}
+	if _, ok := files["project/new.txt"]; ok {
+		t.Error("Dry run should not create files")
+	}
+}
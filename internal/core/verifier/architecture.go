@@ -0,0 +1,81 @@
+package verifier
+
+import (
+	"fmt"
+	"grei-cli/internal/core/glob"
+	"grei-cli/internal/core/policy"
+	"grei-cli/internal/ports/inbound"
+	"grei-cli/internal/ports/outbound"
+)
+
+func (s *service) verifyArchitecture(options inbound.VerifyOptions) error {
+	arch := options.Architecture
+	if len(arch.Layers) == 0 {
+		return skipCheck(options, policy.CheckArchitecture, "No architecture layers declared for this stack")
+	}
+	if err := arch.Validate(); err != nil {
+		return fmt.Errorf("invalid architecture rules: %w", err)
+	}
+
+	imports, err := s.importScanner.Scan(options.Path)
+	if err != nil {
+		return fmt.Errorf("could not scan imports: %w", err)
+	}
+
+	violations := 0
+	for _, imp := range imports {
+		if msg := checkImport(arch, imp); msg != "" {
+			fmt.Printf("  [✗] %s:%d %s\n", imp.File, imp.Line, msg)
+			violations++
+		}
+	}
+
+	if violations > 0 {
+		return fmt.Errorf("found %d architecture violations", violations)
+	}
+	fmt.Printf("  [✓] %d imports respect the %d architecture layers.\n", len(imports), len(arch.Layers))
+	return nil
+}
+
+// checkImport returns a description of the rule imp breaks, or an empty
+// string when the import is allowed. Files outside every layer are not
+// constrained.
+func checkImport(arch policy.Architecture, imp outbound.SourceImport) string {
+	from := layerOf(arch, imp.File)
+	if from == nil {
+		return ""
+	}
+
+	if imp.Target == "" {
+		for _, pattern := range from.Forbid {
+			if glob.Match(pattern, imp.Specifier) || glob.Match(pattern+"/**", imp.Specifier) {
+				return fmt.Sprintf("%s layer must not import %s", from.Name, imp.Specifier)
+			}
+		}
+		return ""
+	}
+
+	to := layerOf(arch, imp.Target)
+	if to == nil || to.Name == from.Name {
+		return ""
+	}
+	for _, allowed := range from.Allow {
+		if allowed == to.Name {
+			return ""
+		}
+	}
+	return fmt.Sprintf("%s layer must not depend on %s layer (%s)", from.Name, to.Name, imp.Specifier)
+}
+
+// layerOf returns the first layer with a path matching p. A path also
+// belongs to a layer when it is the directory a "dir/**" pattern covers.
+func layerOf(arch policy.Architecture, p string) *policy.Layer {
+	for i, layer := range arch.Layers {
+		for _, pattern := range layer.Paths {
+			if glob.Match(pattern, p) {
+				return &arch.Layers[i]
+			}
+		}
+	}
+	return nil
+}
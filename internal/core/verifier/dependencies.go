@@ -0,0 +1,195 @@
+package verifier
+
+import (
+	"errors"
+	"fmt"
+	"grei-cli/internal/core/glob"
+	"grei-cli/internal/core/policy"
+	"grei-cli/internal/ports/inbound"
+	"grei-cli/internal/ports/outbound"
+	"regexp"
+	"strings"
+)
+
+var (
+	exactVersion  = regexp.MustCompile(`^v?\d+\.\d+\.\d+([-+][0-9A-Za-z.\-+]*)?$`)
+	pythonPinned  = regexp.MustCompile(`^===?\s*[^,*<>=!~]+$`)
+	localProtocol = []string{"file:", "link:", "workspace:", "path:"}
+)
+
+func (s *service) verifyDependencies(options inbound.VerifyOptions) error {
+	manifests, err := s.dependencyReader.Read(options.Path)
+	if err != nil {
+		return fmt.Errorf("could not read dependencies: %w", err)
+	}
+	if len(manifests) == 0 {
+		return skipCheck(options, policy.CheckDependencies, "No dependency manifests found")
+	}
+
+	failed := false
+	for _, manifest := range manifests {
+		fmt.Printf("  [i] Found %d dependencies in %s.\n", len(manifest.Dependencies), manifest.Path)
+		if !checkBanned(manifest, options.BannedDependencies) {
+			failed = true
+		}
+		if !options.PinDependencies {
+			continue
+		}
+		if !checkPinned(manifest) {
+			failed = true
+		}
+		if !checkLockfile(manifest) {
+			failed = true
+		}
+	}
+
+	if failed {
+		return fmt.Errorf("dependency verification failed")
+	}
+	if options.PinDependencies {
+		fmt.Println("  [✓] Dependencies are pinned, locked and allowed.")
+	} else {
+		fmt.Println("  [✓] Dependencies are allowed.")
+	}
+	return nil
+}
+
+func checkBanned(manifest outbound.DependencyManifest, rules []policy.BannedDependency) bool {
+	ok := true
+	for _, dep := range manifest.Dependencies {
+		for _, rule := range rules {
+			if rule.Ecosystem != "" && rule.Ecosystem != dep.Ecosystem {
+				continue
+			}
+			if !glob.Match(rule.Name, dep.Name) {
+				continue
+			}
+			ok = false
+			if rule.Reason != "" {
+				fmt.Printf("  [✗] Banned dependency %s in %s: %s\n", dep.Name, dep.Source, rule.Reason)
+			} else {
+				fmt.Printf("  [✗] Banned dependency %s in %s\n", dep.Name, dep.Source)
+			}
+		}
+	}
+	return ok
+}
+
+func checkPinned(manifest outbound.DependencyManifest) bool {
+	ok := true
+	for _, dep := range manifest.Dependencies {
+		if !isPinned(dep) {
+			fmt.Printf("  [✗] Unpinned dependency %s@%s in %s\n", dep.Name, dep.Version, dep.Source)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// isPinned reports whether the version constraint of dep selects exactly
+// one release. Local packages (file:, workspace:, ...) are always pinned.
+func isPinned(dep outbound.Dependency) bool {
+	if isLocal(dep) {
+		return true
+	}
+
+	version := strings.TrimSpace(dep.Version)
+	switch dep.Ecosystem {
+	case "go":
+		// go.mod only records exact (or pseudo) versions.
+		return true
+	case "pypi":
+		return pythonPinned.MatchString(version) || exactVersion.MatchString(version)
+	default:
+		return exactVersion.MatchString(strings.TrimPrefix(version, "="))
+	}
+}
+
+func isLocal(dep outbound.Dependency) bool {
+	for _, prefix := range localProtocol {
+		if strings.HasPrefix(dep.Version, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkLockfile verifies that the manifest has a lockfile and that every
+// declared dependency is locked, at the declared version when it is pinned.
+func checkLockfile(manifest outbound.DependencyManifest) bool {
+	if len(manifest.LockfileNames) == 0 || len(manifest.Dependencies) == 0 {
+		return true
+	}
+	if manifest.Lockfile == nil {
+		fmt.Printf("  [✗] Missing lockfile for %s (expected %s)\n", manifest.Path, strings.Join(manifest.LockfileNames, " or "))
+		return false
+	}
+	if manifest.Lockfile.Packages == nil {
+		fmt.Printf("  [i] Found %s, skipping sync check.\n", manifest.Lockfile.Path)
+		return true
+	}
+
+	var drift []string
+	for _, dep := range manifest.Dependencies {
+		locked, found := manifest.Lockfile.Packages[dep.Name]
+		switch {
+		case !found:
+			drift = append(drift, fmt.Sprintf("%s is not locked", dep.Name))
+		case !isLocal(dep) && isPinned(dep) && !containsVersion(locked, dep.Version):
+			drift = append(drift, fmt.Sprintf("%s %s declared, %s locked", dep.Name, dep.Version, strings.Join(locked, ", ")))
+		}
+	}
+
+	if len(drift) > 0 {
+		fmt.Printf("  [✗] %s is out of sync with %s: %s\n", manifest.Lockfile.Path, manifest.Path, strings.Join(drift, "; "))
+		return false
+	}
+	fmt.Printf("  [✓] %s is in sync with %s.\n", manifest.Lockfile.Path, manifest.Path)
+	return true
+}
+
+func containsVersion(locked []string, declared string) bool {
+	declared = strings.TrimLeft(strings.TrimSpace(declared), "=v")
+	for _, version := range locked {
+		if strings.TrimPrefix(version, "v") == declared {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *service) verifyVulnerabilities(options inbound.VerifyOptions) error {
+	vulns, err := s.vulnScanner.Scan(options.Path)
+	if errors.Is(err, outbound.ErrScannerUnavailable) {
+		return skipCheck(options, policy.CheckVulnerabilities, "No vulnerability scanner available")
+	}
+	if err != nil {
+		return fmt.Errorf("vulnerability scan failed: %w", err)
+	}
+
+	if len(vulns) == 0 {
+		fmt.Println("  [✓] No known vulnerabilities found.")
+		return nil
+	}
+
+	for _, v := range vulns {
+		line := fmt.Sprintf("  [✗] %s: %s", v.ID, v.Package)
+		if v.Version != "" {
+			line += "@" + v.Version
+		}
+		if v.AffectedRange != "" {
+			line += fmt.Sprintf(" (affected %s)", v.AffectedRange)
+		}
+		if v.Severity != "" {
+			line += fmt.Sprintf(" [%s]", v.Severity)
+		}
+		if v.Summary != "" {
+			line += " " + v.Summary
+		}
+		if v.FixedIn != "" {
+			line += fmt.Sprintf(" (fixed in %s)", v.FixedIn)
+		}
+		fmt.Println(line)
+	}
+	return fmt.Errorf("found %d known vulnerabilities", len(vulns))
+}
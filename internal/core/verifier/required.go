@@ -0,0 +1,136 @@
+package verifier
+
+import (
+	"fmt"
+	"grei-cli/internal/core/glob"
+	"grei-cli/internal/core/policy"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// verifyRequiredPaths checks every requirement and reports all of the missing
+// or invalid ones before failing. An empty list requires nothing.
+func (s *service) verifyRequiredPaths(basePath string, requirements []policy.Requirement) error {
+	if len(requirements) == 0 {
+		fmt.Println("  [i] No files or directories are required.")
+		return nil
+	}
+
+	allSatisfied := true
+	for _, r := range requirements {
+		if err := r.Validate(); err != nil {
+			return fmt.Errorf("invalid required path policy: %w", err)
+		}
+
+		found, err := checkRequirement(basePath, r)
+		if err != nil {
+			fmt.Printf("  [✗] %s: %v\n", r, err)
+			allSatisfied = false
+			continue
+		}
+		fmt.Printf("  [✓] Found: %s\n", found)
+	}
+
+	if !allSatisfied {
+		return fmt.Errorf("missing required files or directories")
+	}
+	return nil
+}
+
+// checkRequirement returns the path that satisfied r, or an error describing
+// why none of its candidates did.
+func checkRequirement(basePath string, r policy.Requirement) (string, error) {
+	candidates, err := expandCandidates(basePath, r)
+	if err != nil {
+		return "", err
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("missing")
+	}
+
+	var lastErr error
+	for _, candidate := range candidates {
+		detail, err := checkContent(filepath.Join(basePath, candidate), r)
+		if err != nil {
+			lastErr = fmt.Errorf("%s %w", candidate, err)
+			continue
+		}
+		if detail != "" {
+			return fmt.Sprintf("%s (%s)", candidate, detail), nil
+		}
+		return candidate, nil
+	}
+	return "", lastErr
+}
+
+// expandCandidates returns the existing paths, relative to basePath, that
+// may satisfy r.
+func expandCandidates(basePath string, r policy.Requirement) ([]string, error) {
+	if r.Glob != "" {
+		var matches []string
+		err := filepath.WalkDir(basePath, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(basePath, path)
+			if err != nil || rel == "." {
+				return err
+			}
+			if d.IsDir() && d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			if glob.Match(r.Glob, filepath.ToSlash(rel)) {
+				matches = append(matches, rel)
+			}
+			return nil
+		})
+		return matches, err
+	}
+
+	var existing []string
+	for _, candidate := range r.Candidates() {
+		if _, err := os.Stat(filepath.Join(basePath, candidate)); err == nil {
+			existing = append(existing, candidate)
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return existing, nil
+}
+
+// checkContent applies the content rules of r to path. Directories only need
+// to exist. The returned detail is shown next to the path on success.
+func checkContent(path string, r policy.Requirement) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() || (!r.NotEmpty && r.MinBytes == 0 && r.License == "") {
+		return "", nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if r.NotEmpty && strings.TrimSpace(string(content)) == "" {
+		return "", fmt.Errorf("is empty")
+	}
+	if len(content) < r.MinBytes {
+		return "", fmt.Errorf("is too short (%d < %d bytes)", len(content), r.MinBytes)
+	}
+	if r.License == "" {
+		return "", nil
+	}
+
+	license := policy.DetectLicense(content)
+	if license == "" {
+		return "", fmt.Errorf("does not contain a known SPDX license")
+	}
+	if r.License != "any" && !strings.EqualFold(license, r.License) {
+		return "", fmt.Errorf("contains %s, expected %s", license, r.License)
+	}
+	return license, nil
+}
@@ -3,33 +3,49 @@ package verifier
 import (
 	"fmt"
 	"grei-cli/internal/adapters/scanner"
+	"grei-cli/internal/core/policy"
+	"grei-cli/internal/core/recipe"
 	"grei-cli/internal/ports/inbound"
 	"grei-cli/internal/ports/outbound"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 type service struct {
-	coverageParser outbound.CoverageParser
-	secretScanner  outbound.SecretScanner
-	linterDetector outbound.LinterDetector
+	coverageParser   outbound.CoverageParser
+	secretScanner    outbound.SecretScanner
+	linterDetector   outbound.LinterDetector
+	dependencyReader outbound.DependencyReader
+	vulnScanner      outbound.VulnerabilityScanner
+	importScanner    outbound.ImportScanner
 }
 
 func NewService(
 	coverageParser outbound.CoverageParser,
 	secretScanner outbound.SecretScanner,
 	linterDetector outbound.LinterDetector,
+	dependencyReader outbound.DependencyReader,
+	vulnScanner outbound.VulnerabilityScanner,
+	importScanner outbound.ImportScanner,
 ) inbound.VerifierService {
 	return &service{
-		coverageParser: coverageParser,
-		secretScanner:  secretScanner,
-		linterDetector: linterDetector,
+		coverageParser:   coverageParser,
+		secretScanner:    secretScanner,
+		linterDetector:   linterDetector,
+		dependencyReader: dependencyReader,
+		vulnScanner:      vulnScanner,
+		importScanner:    importScanner,
 	}
 }
 
 func (s *service) VerifyProject(options inbound.VerifyOptions) error {
 	fmt.Println("Running verifications...")
 
+	if options.Policy != nil {
+		fmt.Printf("  [i] Applying policy '%s' (version %s).\n", options.Policy.Name, options.Policy.Version)
+	}
+
 	// Verify project against its recipe
 	if options.Recipe != nil {
 		fmt.Printf("  [i] Verifying project against recipe for '%s'...\n", options.Recipe.Project.Name)
@@ -63,6 +79,9 @@ func (s *service) VerifyProject(options inbound.VerifyOptions) error {
 	secrets, err := s.secretScanner.Scan(options.Path)
 	if err != nil {
 		if err == scanner.ErrGitleaksNotFound {
+			if isMandatory(options, policy.CheckSecrets) {
+				return fmt.Errorf("gitleaks not found, but the secret scan is mandatory")
+			}
 			fmt.Println("  [!] gitleaks not found, skipping secret scan.")
 		} else {
 			return fmt.Errorf("secret scanning failed: %w", err)
@@ -81,34 +100,35 @@ func (s *service) VerifyProject(options inbound.VerifyOptions) error {
 
 	// Check for required files and directories
 	fmt.Println("\nChecking for required files and directories...")
-	requiredPaths := []string{
-		"LICENSE",
-		"CONTRIBUTING.md",
-		"deploy/helm",
+	if err := s.verifyRequiredPaths(options.Path, options.Required); err != nil {
+		return err
 	}
 
-	allPathsExist := true
-	for _, p := range requiredPaths {
-		if _, err := os.Stat(filepath.Join(options.Path, p)); os.IsNotExist(err) {
-			fmt.Printf("  [✗] Missing: %s\n", p)
-			allPathsExist = false
-		} else {
-			fmt.Printf("  [✓] Found: %s\n", p)
-		}
+	// Check declared dependencies
+	fmt.Println("\nChecking dependencies...")
+	if err := s.verifyDependencies(options); err != nil {
+		return err
 	}
 
-	if !allPathsExist {
-		return fmt.Errorf("missing required files or directories")
+	// Check architecture layers
+	fmt.Println("\nChecking architecture layers...")
+	if err := s.verifyArchitecture(options); err != nil {
+		return err
+	}
+
+	// Audit dependencies against known advisories
+	fmt.Println("\nAuditing dependencies for known vulnerabilities...")
+	if err := s.verifyVulnerabilities(options); err != nil {
+		return err
 	}
 
 	return nil
 }
 
 func (s *service) verifyLinter(options inbound.VerifyOptions) error {
-	linter, ok := options.Recipe.Stack["linter"].(string)
-	if !ok || linter == "" {
-		fmt.Println("  [i] No linter specified in recipe, skipping check.")
-		return nil
+	linter := options.Recipe.Stack.Linter
+	if linter == "" {
+		return skipCheck(options, policy.CheckLinter, "No linter specified in recipe")
 	}
 
 	fmt.Printf("  [i] Verifying linter '%s'...\n", linter)
@@ -126,10 +146,9 @@ func (s *service) verifyLinter(options inbound.VerifyOptions) error {
 }
 
 func (s *service) verifyPersistence(options inbound.VerifyOptions) error {
-	persistence, ok := options.Recipe.Stack["persistence"].(string)
-	if !ok || persistence == "" || persistence == "None" {
-		fmt.Println("  [i] No persistence layer specified in recipe, skipping check.")
-		return nil
+	persistence := options.Recipe.Stack.Persistence
+	if persistence == "" || persistence == "None" {
+		return skipCheck(options, policy.CheckPersistence, "No persistence layer specified in recipe")
 	}
 
 	fmt.Printf("  [i] Verifying persistence layer '%s'...\n", persistence)
@@ -145,24 +164,75 @@ func (s *service) verifyPersistence(options inbound.VerifyOptions) error {
 }
 
 func (s *service) verifyDeployment(options inbound.VerifyOptions) error {
-	deployment, ok := options.Recipe.Stack["deployment"].(string)
-	if !ok || deployment == "" || deployment == "None" {
-		fmt.Println("  [i] No deployment layer specified in recipe, skipping check.")
-		return nil
+	deployment := options.Recipe.Stack.Deployment
+	if deployment == "" || deployment == "None" {
+		return skipCheck(options, policy.CheckDeployment, "No deployment layer specified in recipe")
 	}
 
 	fmt.Printf("  [i] Verifying deployment layer '%s'...\n", deployment)
-	// For now, we just check for a deploy/ directory.
+	// For now, we just check for the deployment configuration: the deploy/
+	// directory, or the serverless manifests of the serverless stacks.
 	// This could be expanded to check for specific IaC files, etc.
-	deployPath := filepath.Join(options.Path, "deploy")
-	if _, err := os.Stat(deployPath); os.IsNotExist(err) {
-		return fmt.Errorf("deploy/ directory not found for deployment layer '%s'", deployment)
+	for _, name := range deploymentPaths {
+		if _, err := os.Stat(filepath.Join(options.Path, name)); err == nil {
+			fmt.Printf("  [✓] Found %s for '%s'.\n", name, deployment)
+			return nil
+		}
+	}
+	return fmt.Errorf("no deployment configuration (%s) found for deployment layer '%s'", strings.Join(deploymentPaths, ", "), deployment)
+}
+
+// deploymentPaths are the paths any of which holds the deployment
+// configuration of a project.
+var deploymentPaths = []string{"deploy", "serverless.yml", "service.yaml"}
+
+// StackChecks lists the checks of VerifyProject that depend on a stack
+// value of the recipe, and whether they run for stack.
+func StackChecks(stack recipe.Stack) []inbound.Activation {
+	return []inbound.Activation{
+		{
+			Var:    "stack.linter",
+			Kind:   inbound.ActivationCheck,
+			Target: policy.CheckLinter,
+			When:   "stack.linter",
+			Active: stack.Linter != "",
+		},
+		{
+			Var:    "stack.persistence",
+			Kind:   inbound.ActivationCheck,
+			Target: policy.CheckPersistence + " (docker-compose.yml)",
+			When:   `stack.persistence && stack.persistence != "None"`,
+			Active: stack.Persistence != "" && stack.Persistence != "None",
+		},
+		{
+			Var:    "stack.deployment",
+			Kind:   inbound.ActivationCheck,
+			Target: policy.CheckDeployment + " (deploy/, serverless.yml or service.yaml)",
+			When:   `stack.deployment && stack.deployment != "None"`,
+			Active: stack.Deployment != "" && stack.Deployment != "None",
+		},
 	}
+}
 
-	fmt.Printf("  [✓] Found deploy/ directory for '%s'.\n", deployment)
+// skipCheck reports a check that cannot run. It fails when the check is
+// mandatory for the project.
+func skipCheck(options inbound.VerifyOptions, check, reason string) error {
+	if isMandatory(options, check) {
+		return fmt.Errorf("%s, but the %s check is mandatory", strings.ToLower(reason[:1])+reason[1:], check)
+	}
+	fmt.Printf("  [i] %s, skipping check.\n", reason)
 	return nil
 }
 
+func isMandatory(options inbound.VerifyOptions, check string) bool {
+	for _, c := range options.MandatoryChecks {
+		if c == check {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *service) findAndParseCoverage(basePath string) (float64, error) {
 	// For now, we assume a single coverage file format. This can be expanded later.
 	searchPath := filepath.Join(basePath, "coverage.out")
@@ -1,10 +1,13 @@
 package verifier
 
 import (
+	"grei-cli/internal/core/policy"
 	"grei-cli/internal/core/recipe"
 	"grei-cli/internal/ports/inbound"
+	"grei-cli/internal/ports/outbound"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -33,6 +36,31 @@ func (m *mockLinterDetector) CheckConfig(path, linterName string) (bool, error)
 	return true, nil
 }
 
+type mockDependencyReader struct {
+	manifests []outbound.DependencyManifest
+}
+
+func (m *mockDependencyReader) Read(path string) ([]outbound.DependencyManifest, error) {
+	return m.manifests, nil
+}
+
+type mockVulnScanner struct {
+	vulns []outbound.Vulnerability
+	err   error
+}
+
+func (m *mockVulnScanner) Scan(path string) ([]outbound.Vulnerability, error) {
+	return m.vulns, m.err
+}
+
+type mockImportScanner struct {
+	imports []outbound.SourceImport
+}
+
+func (m *mockImportScanner) Scan(path string) ([]outbound.SourceImport, error) {
+	return m.imports, nil
+}
+
 func TestVerifyProject_Success(t *testing.T) {
 	// Arrange
 	tmpDir, err := os.MkdirTemp("", "grei-test-*")
@@ -63,17 +91,18 @@ func TestVerifyProject_Success(t *testing.T) {
 	secretScanner := &mockSecretScanner{}
 	linterDetector := &mockLinterDetector{}
 
-	service := NewService(coverageParser, secretScanner, linterDetector)
+	service := NewService(coverageParser, secretScanner, linterDetector, &mockDependencyReader{}, &mockVulnScanner{}, &mockImportScanner{})
 
 	options := inbound.VerifyOptions{
 		Path:        tmpDir,
 		MinCoverage: 80,
+		Required:    policy.DefaultRequirements,
 		Recipe: &recipe.Recipe{
 			Project: recipe.Project{Name: "TestProject"},
-			Stack: map[string]interface{}{
-				"linter":      "golangci-lint",
-				"persistence": "postgresql",
-				"deployment":  "kubernetes",
+			Stack: recipe.Stack{
+				Linter:      "golangci-lint",
+				Persistence: "postgresql",
+				Deployment:  "kubernetes",
 			},
 		},
 	}
@@ -94,7 +123,7 @@ func TestVerifyProject_CoverageTooLow(t *testing.T) {
 	os.Create(filepath.Join(tmpDir, "coverage.out"))
 
 	coverageParser := &mockCoverageParser{} // Returns 85.0
-	service := NewService(coverageParser, &mockSecretScanner{}, &mockLinterDetector{})
+	service := NewService(coverageParser, &mockSecretScanner{}, &mockLinterDetector{}, &mockDependencyReader{}, &mockVulnScanner{}, &mockImportScanner{})
 	options := inbound.VerifyOptions{
 		Path:        tmpDir,
 		MinCoverage: 90, // Higher than mock parser's return value
@@ -119,14 +148,12 @@ func TestVerifyProject_MissingLinterConfig(t *testing.T) {
 	linterDetector := &mockLinterDetector{checkConfigFunc: func(path, linterName string) (bool, error) {
 		return false, nil // Simulate not found
 	}}
-	service := NewService(&mockCoverageParser{}, &mockSecretScanner{}, linterDetector)
+	service := NewService(&mockCoverageParser{}, &mockSecretScanner{}, linterDetector, &mockDependencyReader{}, &mockVulnScanner{}, &mockImportScanner{})
 	options := inbound.VerifyOptions{
 		Path:        tmpDir,
 		MinCoverage: 80,
 		Recipe: &recipe.Recipe{
-			Stack: map[string]interface{}{
-				"linter": "golangci-lint",
-			},
+			Stack: recipe.Stack{Linter: "golangci-lint"},
 		},
 	}
 
@@ -145,14 +172,12 @@ func TestVerifyProject_MissingPersistence(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 	os.Create(filepath.Join(tmpDir, "coverage.out"))
 
-	service := NewService(&mockCoverageParser{}, &mockSecretScanner{}, &mockLinterDetector{})
+	service := NewService(&mockCoverageParser{}, &mockSecretScanner{}, &mockLinterDetector{}, &mockDependencyReader{}, &mockVulnScanner{}, &mockImportScanner{})
 	options := inbound.VerifyOptions{
 		Path:        tmpDir,
 		MinCoverage: 80,
 		Recipe: &recipe.Recipe{
-			Stack: map[string]interface{}{
-				"persistence": "postgresql",
-			},
+			Stack: recipe.Stack{Persistence: "postgresql"},
 		},
 	}
 
@@ -171,14 +196,12 @@ func TestVerifyProject_MissingDeployment(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 	os.Create(filepath.Join(tmpDir, "coverage.out"))
 
-	service := NewService(&mockCoverageParser{}, &mockSecretScanner{}, &mockLinterDetector{})
+	service := NewService(&mockCoverageParser{}, &mockSecretScanner{}, &mockLinterDetector{}, &mockDependencyReader{}, &mockVulnScanner{}, &mockImportScanner{})
 	options := inbound.VerifyOptions{
 		Path:        tmpDir,
 		MinCoverage: 80,
 		Recipe: &recipe.Recipe{
-			Stack: map[string]interface{}{
-				"deployment": "kubernetes",
-			},
+			Stack: recipe.Stack{Deployment: "kubernetes"},
 		},
 	}
 
@@ -191,6 +214,25 @@ func TestVerifyProject_MissingDeployment(t *testing.T) {
 	}
 }
 
+func TestVerifyProject_ServerlessDeployment(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.Create(filepath.Join(tmpDir, "coverage.out"))
+	os.Create(filepath.Join(tmpDir, "serverless.yml"))
+
+	service := NewService(&mockCoverageParser{}, &mockSecretScanner{}, &mockLinterDetector{}, &mockDependencyReader{}, &mockVulnScanner{}, &mockImportScanner{})
+	options := inbound.VerifyOptions{
+		Path:        tmpDir,
+		MinCoverage: 80,
+		Recipe: &recipe.Recipe{
+			Stack: recipe.Stack{Deployment: "Lambda"},
+		},
+	}
+
+	if err := service.VerifyProject(options); err != nil {
+		t.Errorf("Expected serverless.yml to satisfy the deployment check, got %v", err)
+	}
+}
+
 type mockSecretScannerSecretsFound struct{}
 
 func (m *mockSecretScannerSecretsFound) Scan(path string) ([]string, error) {
@@ -203,7 +245,7 @@ func TestVerifyProject_SecretsFound(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 	os.Create(filepath.Join(tmpDir, "coverage.out"))
 
-	service := NewService(&mockCoverageParser{}, &mockSecretScannerSecretsFound{}, &mockLinterDetector{})
+	service := NewService(&mockCoverageParser{}, &mockSecretScannerSecretsFound{}, &mockLinterDetector{}, &mockDependencyReader{}, &mockVulnScanner{}, &mockImportScanner{})
 	options := inbound.VerifyOptions{
 		Path:        tmpDir,
 		MinCoverage: 80,
@@ -225,11 +267,12 @@ func TestVerifyProject_MissingRequiredFiles(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 	os.Create(filepath.Join(tmpDir, "coverage.out"))
 
-	service := NewService(&mockCoverageParser{}, &mockSecretScanner{}, &mockLinterDetector{})
+	service := NewService(&mockCoverageParser{}, &mockSecretScanner{}, &mockLinterDetector{}, &mockDependencyReader{}, &mockVulnScanner{}, &mockImportScanner{})
 	options := inbound.VerifyOptions{
 		Path:        tmpDir,
 		MinCoverage: 80,
 		Recipe:      &recipe.Recipe{},
+		Required:    policy.DefaultRequirements,
 	}
 
 	// Act
@@ -241,12 +284,43 @@ func TestVerifyProject_MissingRequiredFiles(t *testing.T) {
 	}
 }
 
+func TestVerifyProject_AllDefaultsIgnored(t *testing.T) {
+	// Arrange
+	tmpDir := t.TempDir()
+	os.Create(filepath.Join(tmpDir, "coverage.out"))
+
+	var overrides []policy.Requirement
+	for _, r := range policy.DefaultRequirements {
+		overrides = append(overrides, policy.Requirement{Path: r.Path, Ignore: true})
+	}
+	required := policy.Merge(policy.DefaultRequirements, overrides)
+
+	service := NewService(&mockCoverageParser{}, &mockSecretScanner{}, &mockLinterDetector{}, &mockDependencyReader{}, &mockVulnScanner{}, &mockImportScanner{})
+	options := inbound.VerifyOptions{
+		Path:        tmpDir,
+		MinCoverage: 80,
+		Recipe:      &recipe.Recipe{},
+		Required:    required,
+	}
+
+	// Act
+	err := service.VerifyProject(options)
+
+	// Assert
+	if len(required) != 0 {
+		t.Errorf("Expected no requirements left, got %v", required)
+	}
+	if err != nil {
+		t.Errorf("Expected no error when every default is ignored, but got: %v", err)
+	}
+}
+
 func TestVerifyProject_MissingCoverageFile(t *testing.T) {
 	// Arrange
 	tmpDir, _ := os.MkdirTemp("", "")
 	defer os.RemoveAll(tmpDir)
 
-	service := NewService(&mockCoverageParser{}, &mockSecretScanner{}, &mockLinterDetector{})
+	service := NewService(&mockCoverageParser{}, &mockSecretScanner{}, &mockLinterDetector{}, &mockDependencyReader{}, &mockVulnScanner{}, &mockImportScanner{})
 	options := inbound.VerifyOptions{
 		Path:        tmpDir,
 		MinCoverage: 80,
@@ -261,3 +335,296 @@ func TestVerifyProject_MissingCoverageFile(t *testing.T) {
 		t.Error("Expected an error for missing coverage file, but got none")
 	}
 }
+
+func TestVerifyProject_RequiredPolicy(t *testing.T) {
+	required := []policy.Requirement{
+		{OneOf: []string{"netlify.toml", "deploy/helm"}},
+		{Glob: ".github/workflows/*.yml"},
+		{Path: "README.md", NotEmpty: true},
+		{Path: "LICENSE", License: "MIT"},
+	}
+
+	files := map[string]string{
+		"coverage.out":             "",
+		"netlify.toml":             "[build]",
+		".github/workflows/ci.yml": "on: push",
+		"README.md":                "# Project",
+		"LICENSE":                  "MIT License\n\nPermission is hereby granted, free of charge, to any person",
+	}
+
+	tests := []struct {
+		name    string
+		replace map[string]string
+		wantErr bool
+	}{
+		{"all satisfied", nil, false},
+		{"empty README", map[string]string{"README.md": "  \n"}, true},
+		{"wrong license", map[string]string{"LICENSE": "Apache License\nVersion 2.0, January 2004"}, true},
+		{"no alternative present", map[string]string{"netlify.toml": ""}, true},
+		{"no glob match", map[string]string{".github/workflows/ci.yml": ""}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			for name, content := range files {
+				if replacement, ok := tt.replace[name]; ok {
+					if replacement == "" {
+						continue
+					}
+					content = replacement
+				}
+				p := filepath.Join(tmpDir, name)
+				if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+					t.Fatalf("Failed to create parent dir for %s: %v", name, err)
+				}
+				if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+					t.Fatalf("Failed to create file %s: %v", name, err)
+				}
+			}
+
+			service := NewService(&mockCoverageParser{}, &mockSecretScanner{}, &mockLinterDetector{}, &mockDependencyReader{}, &mockVulnScanner{}, &mockImportScanner{})
+			err := service.VerifyProject(inbound.VerifyOptions{
+				Path:        tmpDir,
+				MinCoverage: 80,
+				Recipe:      &recipe.Recipe{},
+				Required:    required,
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyProject() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyProject_BannedDependency(t *testing.T) {
+	// Arrange
+	tmpDir := t.TempDir()
+	os.Create(filepath.Join(tmpDir, "coverage.out"))
+
+	dependencyReader := &mockDependencyReader{manifests: []outbound.DependencyManifest{{
+		Path:      "package.json",
+		Ecosystem: "npm",
+		Dependencies: []outbound.Dependency{
+			{Name: "express", Version: "4.19.2", Ecosystem: "npm", Source: "package.json"},
+			{Name: "moment", Version: "2.30.1", Ecosystem: "npm", Source: "package.json"},
+		},
+	}}}
+	service := NewService(&mockCoverageParser{}, &mockSecretScanner{}, &mockLinterDetector{}, dependencyReader, &mockVulnScanner{}, &mockImportScanner{})
+	options := inbound.VerifyOptions{
+		Path:        tmpDir,
+		MinCoverage: 80,
+		Recipe:      &recipe.Recipe{},
+		Required:    []policy.Requirement{{Path: "coverage.out"}},
+		BannedDependencies: []policy.BannedDependency{
+			{Name: "moment", Ecosystem: "npm", Reason: "use date-fns"},
+		},
+	}
+
+	// Act
+	err := service.VerifyProject(options)
+
+	// Assert
+	if err == nil {
+		t.Error("Expected an error for a banned dependency, but got none")
+	}
+}
+
+func TestVerifyProject_MandatoryCheckCannotBeSkipped(t *testing.T) {
+	// Arrange
+	tmpDir := t.TempDir()
+	os.Create(filepath.Join(tmpDir, "coverage.out"))
+
+	service := NewService(&mockCoverageParser{}, &mockSecretScanner{}, &mockLinterDetector{}, &mockDependencyReader{}, &mockVulnScanner{}, &mockImportScanner{})
+	options := inbound.VerifyOptions{
+		Path:            tmpDir,
+		MinCoverage:     80,
+		Recipe:          &recipe.Recipe{},
+		Required:        []policy.Requirement{{Path: "coverage.out"}},
+		MandatoryChecks: []string{policy.CheckLinter},
+	}
+
+	// Act
+	err := service.VerifyProject(options)
+
+	// Assert
+	if err == nil {
+		t.Error("Expected an error for a skipped mandatory linter check, but got none")
+	}
+}
+
+func TestVerifyProject_DependencyPinning(t *testing.T) {
+	express := outbound.Dependency{Name: "express", Version: "4.19.2", Ecosystem: "npm", Source: "package.json"}
+	lockfile := &outbound.Lockfile{
+		Path:     "package-lock.json",
+		Packages: map[string][]string{"express": {"4.19.2"}},
+	}
+
+	tests := []struct {
+		name     string
+		manifest outbound.DependencyManifest
+		unpinned bool
+		wantErr  bool
+	}{
+		{
+			name: "pinned and locked",
+			manifest: outbound.DependencyManifest{
+				Path: "package.json", Ecosystem: "npm", LockfileNames: []string{"package-lock.json"},
+				Dependencies: []outbound.Dependency{express}, Lockfile: lockfile,
+			},
+		},
+		{
+			name: "caret range",
+			manifest: outbound.DependencyManifest{
+				Path: "package.json", Ecosystem: "npm", LockfileNames: []string{"package-lock.json"},
+				Dependencies: []outbound.Dependency{{Name: "express", Version: "^4.19.2", Ecosystem: "npm", Source: "package.json"}},
+				Lockfile:     lockfile,
+			},
+			wantErr: true,
+		},
+		{
+			name: "caret range without the policy rule",
+			manifest: outbound.DependencyManifest{
+				Path: "package.json", Ecosystem: "npm", LockfileNames: []string{"package-lock.json"},
+				Dependencies: []outbound.Dependency{{Name: "express", Version: "^4.19.2", Ecosystem: "npm", Source: "package.json"}},
+			},
+			unpinned: true,
+		},
+		{
+			name: "missing lockfile",
+			manifest: outbound.DependencyManifest{
+				Path: "package.json", Ecosystem: "npm", LockfileNames: []string{"package-lock.json"},
+				Dependencies: []outbound.Dependency{express},
+			},
+			wantErr: true,
+		},
+		{
+			name: "lockfile out of sync",
+			manifest: outbound.DependencyManifest{
+				Path: "package.json", Ecosystem: "npm", LockfileNames: []string{"package-lock.json"},
+				Dependencies: []outbound.Dependency{express},
+				Lockfile: &outbound.Lockfile{
+					Path:     "package-lock.json",
+					Packages: map[string][]string{"express": {"4.18.0"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "requirements.txt needs no lockfile",
+			manifest: outbound.DependencyManifest{
+				Path: "requirements.txt", Ecosystem: "pypi",
+				Dependencies: []outbound.Dependency{{Name: "fastapi", Version: "==0.110.0", Ecosystem: "pypi", Source: "requirements.txt"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			os.Create(filepath.Join(tmpDir, "coverage.out"))
+
+			dependencyReader := &mockDependencyReader{manifests: []outbound.DependencyManifest{tt.manifest}}
+			service := NewService(&mockCoverageParser{}, &mockSecretScanner{}, &mockLinterDetector{}, dependencyReader, &mockVulnScanner{}, &mockImportScanner{})
+			err := service.VerifyProject(inbound.VerifyOptions{
+				Path:            tmpDir,
+				MinCoverage:     80,
+				Recipe:          &recipe.Recipe{},
+				Required:        []policy.Requirement{{Path: "coverage.out"}},
+				PinDependencies: !tt.unpinned,
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyProject() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyProject_Vulnerabilities(t *testing.T) {
+	tests := []struct {
+		name      string
+		scanner   *mockVulnScanner
+		mandatory []string
+		wantErr   bool
+	}{
+		{"no findings", &mockVulnScanner{}, nil, false},
+		{"findings", &mockVulnScanner{vulns: []outbound.Vulnerability{{ID: "GHSA-1", Package: "lodash", Version: "4.17.20"}}}, nil, true},
+		{"no scanner available", &mockVulnScanner{err: outbound.ErrScannerUnavailable}, nil, false},
+		{"no scanner available but mandatory", &mockVulnScanner{err: outbound.ErrScannerUnavailable}, []string{policy.CheckVulnerabilities}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			os.Create(filepath.Join(tmpDir, "coverage.out"))
+
+			service := NewService(&mockCoverageParser{}, &mockSecretScanner{}, &mockLinterDetector{}, &mockDependencyReader{}, tt.scanner, &mockImportScanner{})
+			err := service.VerifyProject(inbound.VerifyOptions{
+				Path:            tmpDir,
+				MinCoverage:     80,
+				Recipe:          &recipe.Recipe{},
+				Required:        []policy.Requirement{{Path: "coverage.out"}},
+				MandatoryChecks: tt.mandatory,
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyProject() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyProject_Architecture(t *testing.T) {
+	arch := policy.Architecture{Layers: []policy.Layer{
+		{Name: "domain", Paths: []string{"src/domain/**"}, Forbid: []string{"express"}},
+		{Name: "ports", Paths: []string{"src/application/ports/**"}, Allow: []string{"domain"}},
+		{Name: "application", Paths: []string{"src/application/**"}, Allow: []string{"domain", "ports"}},
+		{Name: "adapters", Paths: []string{"src/infrastructure/**"}, Allow: []string{"domain", "ports", "application"}},
+	}}
+
+	tests := []struct {
+		name    string
+		imp     outbound.SourceImport
+		wantErr bool
+	}{
+		{"adapter imports port", outbound.SourceImport{File: "src/infrastructure/http.ts", Specifier: "../application/ports/inbound/user", Target: "src/application/ports/inbound/user"}, false},
+		{"domain imports framework", outbound.SourceImport{File: "src/domain/user.ts", Specifier: "express/lib/router"}, true},
+		{"port imports adapter", outbound.SourceImport{File: "src/application/ports/outbound/repo.ts", Specifier: "../../../infrastructure/db", Target: "src/infrastructure/db"}, true},
+		{"domain imports domain", outbound.SourceImport{File: "src/domain/user.ts", Specifier: "./email", Target: "src/domain/email"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			os.Create(filepath.Join(tmpDir, "coverage.out"))
+
+			importScanner := &mockImportScanner{imports: []outbound.SourceImport{tt.imp}}
+			service := NewService(&mockCoverageParser{}, &mockSecretScanner{}, &mockLinterDetector{}, &mockDependencyReader{}, &mockVulnScanner{}, importScanner)
+			err := service.VerifyProject(inbound.VerifyOptions{
+				Path:         tmpDir,
+				MinCoverage:  80,
+				Recipe:       &recipe.Recipe{},
+				Required:     []policy.Requirement{{Path: "coverage.out"}},
+				Architecture: arch,
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyProject() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStackChecks(t *testing.T) {
+	checks := StackChecks(recipe.Stack{Linter: "ESLint", Persistence: "None"})
+	active := make(map[string]bool)
+	for _, check := range checks {
+		active[check.Var] = check.Active
+	}
+	want := map[string]bool{"stack.linter": true, "stack.persistence": false, "stack.deployment": false}
+	if !reflect.DeepEqual(active, want) {
+		t.Errorf("StackChecks() activates %v, want %v", active, want)
+	}
+}
@@ -1,8 +1,28 @@
 package inbound
 
-import "grei-cli/internal/core/recipe"
+import (
+	"grei-cli/internal/core/recipe"
+	"io/fs"
+)
 
 // InitializerService defines the port for the project initialization service.
 type InitializerService interface {
-	InitializeProject(path, cacheDir string, gitInit bool, recipe *recipe.Recipe) error
+	// InitializeProject checks that templates are compatible with the CLI and
+	// prepares path for scaffolding: the project directory, its standard
+	// directories and, when gitInit is set, the git repository, with main
+	// as its unborn branch. Files are rendered by the ScaffolderService.
+	InitializeProject(path string, templates fs.FS, gitInit bool, recipe *recipe.Recipe) error
+	// AdoptProject checks that templates are compatible with the CLI and,
+	// when gitInit is set, creates the git repository at path, with main as
+	// its unborn branch. Unlike InitializeProject, it creates no
+	// directories: it serves adopted projects and monorepo components,
+	// whose layout is their own or their stack's.
+	AdoptProject(path string, templates fs.FS, gitInit bool) error
+	// SetupRepository records the rendered project at path, a repository
+	// created by InitializeProject, as the first commit of main and checks
+	// out develop, created from it. When remote is set, it is added as
+	// origin first.
+	SetupRepository(path, remote string) error
+	// AddRemote adds remote as the origin of the repository at path.
+	AddRemote(path, remote string) error
 }
@@ -0,0 +1,24 @@
+package inbound
+
+import "time"
+
+// PostStep is a post-generate step of a stack, ready to run.
+type PostStep struct {
+	// Name describes the step; it is the command itself when the manifest
+	// gives no name.
+	Name string
+	Run  string
+	// Dir is the directory the command runs in, relative to the project.
+	Dir string
+	// Timeout bounds the step; zero selects the default timeout.
+	Timeout time.Duration
+}
+
+// PostHooksService defines the port for running the post-generate steps of
+// a new project.
+type PostHooksService interface {
+	// RunPostHooks runs steps in order in the project at path, streaming
+	// their output, and stops at the first that fails or runs longer than
+	// its timeout, or timeout when the step has none.
+	RunPostHooks(path string, steps []PostStep, timeout time.Duration) error
+}
@@ -0,0 +1,31 @@
+package inbound
+
+// File states reported by a preview.
+const (
+	FileNew       = "new"
+	FileModified  = "modified"
+	FileUnchanged = "unchanged"
+)
+
+// PreviewOptions holds the options for previewing generated files.
+type PreviewOptions struct {
+	// Path is the directory the files would be written to.
+	Path string
+	// Files holds the generated content, keyed by slash-separated path
+	// relative to Path.
+	Files map[string][]byte
+	// Dirs lists the generated directories, relative to Path.
+	Dirs []string
+}
+
+// PreviewReport counts the files of a preview by state.
+type PreviewReport struct {
+	States                   map[string]string
+	New, Modified, Unchanged int
+}
+
+// PreviewService defines the port for previewing generated files against
+// the existing ones.
+type PreviewService interface {
+	Preview(options PreviewOptions) (*PreviewReport, error)
+}
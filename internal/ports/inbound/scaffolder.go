@@ -5,9 +5,50 @@ import (
 	"io/fs"
 )
 
+// Kinds of Activation.
+const (
+	ActivationFiles    = "files"
+	ActivationMixin    = "mixin"
+	ActivationOption   = "option"
+	ActivationTemplate = "template"
+	ActivationCheck    = "check"
+)
+
+// Activation is something a recipe value turns on or off: files or a mixin
+// included under a condition, an option asked under a condition, a
+// template that reads the value or a verify check that depends on it.
+type Activation struct {
+	// Var is the recipe value, e.g. stack.ci.
+	Var string
+	// Kind is one of the Activation* constants.
+	Kind string
+	// Target names what is activated: paths, a skeleton, an option, a
+	// generated file or a check.
+	Target string
+	// When is the condition on Var, empty when any value activates Target.
+	When string
+	// Active reports whether Target is active for the recipe as it is.
+	Active bool
+}
+
 // ScaffolderService defines the port for the project scaffolding service.
 type ScaffolderService interface {
-	Scaffold(path, cacheDir string, recipe *recipe.Recipe) error
-	GetTemplates() ([]fs.DirEntry, error)
-	GetTemplateFile(path string) ([]byte, error)
+	// Scaffold renders the generic skeleton and the skeleton of the recipe's
+	// stack from templates into path, in a single pass where files of the
+	// stack replace generic ones.
+	Scaffold(path string, templates fs.FS, recipe *recipe.Recipe) error
+	// ScaffoldComponent renders the skeleton of the recipe's stack, with the
+	// skeletons it extends and its mixins, into path, a component of a
+	// monorepo. The generic skeleton is left to the root of the repository.
+	ScaffoldComponent(path string, templates fs.FS, recipe *recipe.Recipe) error
+	// Adopt renders the standard files of the generic skeleton, such as
+	// LICENSE and .githooks, into path, an existing project. Only missing
+	// files are written; it returns them.
+	Adopt(path string, templates fs.FS, recipe *recipe.Recipe) ([]string, error)
+	// Explain lists the files, mixins, options and templates of the
+	// recipe's stack that depend on a recipe value.
+	Explain(templates fs.FS, recipe *recipe.Recipe) ([]Activation, error)
+	// PostGenerateSteps returns the post-generate steps of the recipe's
+	// stack whose condition holds, in the order the skeletons are applied.
+	PostGenerateSteps(templates fs.FS, recipe *recipe.Recipe) ([]PostStep, error)
 }
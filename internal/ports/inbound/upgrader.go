@@ -0,0 +1,45 @@
+package inbound
+
+// UpgradeOptions holds the options for upgrading a project to newer
+// templates.
+type UpgradeOptions struct {
+	Path string
+	// Base is the project rendered from the templates it was created with,
+	// keyed by slash-separated path.
+	Base map[string][]byte
+	// Latest is the project rendered from the new templates.
+	Latest map[string][]byte
+	// DryRun prints the changes as a diff instead of writing them.
+	DryRun bool
+	// Reject leaves conflicting files untouched and writes the template
+	// changes to <file>.rej instead of adding conflict markers.
+	Reject bool
+}
+
+// Change kinds reported by an upgrade.
+const (
+	ChangeAdded      = "added"
+	ChangeUpdated    = "updated"
+	ChangeMerged     = "merged"
+	ChangeConflict   = "conflict"
+	ChangeRemoved    = "removed"
+	ChangeKeptDelete = "kept-deleted"
+)
+
+// FileChange describes what an upgrade did, or would do, to a file.
+type FileChange struct {
+	Path      string
+	Kind      string
+	Conflicts int
+}
+
+// UpgradeReport summarizes an upgrade.
+type UpgradeReport struct {
+	Changes   []FileChange
+	Conflicts int
+}
+
+// UpgraderService defines the port for upgrading projects to newer templates.
+type UpgraderService interface {
+	Upgrade(options UpgradeOptions) (*UpgradeReport, error)
+}
@@ -1,12 +1,31 @@
 package inbound
 
-import "grei-cli/internal/core/recipe"
+import (
+	"grei-cli/internal/core/policy"
+	"grei-cli/internal/core/recipe"
+)
 
 type VerifyOptions struct {
 	Path        string
 	MinCoverage int
 	JSONOutput  bool
 	Recipe      *recipe.Recipe
+	// Required lists the files and directories the project must contain.
+	// When empty, nothing is required.
+	Required []policy.Requirement
+	// BannedDependencies fail verification when declared by the project.
+	BannedDependencies []policy.BannedDependency
+	// PinDependencies makes version ranges, missing lockfiles and lockfiles
+	// out of sync with their manifest fail verification.
+	PinDependencies bool
+	// MandatoryChecks lists the checks that fail instead of being skipped
+	// when they cannot run (see the policy.Check* constants).
+	MandatoryChecks []string
+	// Architecture holds the layer rules declared by the stack manifest.
+	Architecture policy.Architecture
+	// Policy is the organization policy pack the options were derived
+	// from, or nil when no pack applies.
+	Policy *policy.Pack
 }
 
 // VerifierService defines the port for the project verification service.
@@ -0,0 +1,43 @@
+package outbound
+
+// Dependency is a package declared in a project's dependency manifest.
+type Dependency struct {
+	Name string
+	// Version is the constraint as written in the manifest.
+	Version string
+	// Ecosystem is the package manager: npm, composer, go or pypi.
+	Ecosystem string
+	// Source is the manifest file the dependency was read from, relative to
+	// the project root.
+	Source string
+}
+
+// DependencyManifest is a dependency manifest (package.json, go.mod, ...)
+// together with the lockfile that pins it.
+type DependencyManifest struct {
+	// Path is relative to the project root.
+	Path         string
+	Ecosystem    string
+	Dependencies []Dependency
+	// LockfileNames are the lockfiles accepted for this manifest, in order
+	// of preference. Empty when the manifest pins its own versions, as
+	// requirements.txt does.
+	LockfileNames []string
+	// Lockfile is nil when none of LockfileNames exists.
+	Lockfile *Lockfile
+}
+
+// Lockfile holds the versions resolved by a package manager.
+type Lockfile struct {
+	// Path is relative to the project root.
+	Path string
+	// Packages maps each locked package name to its resolved versions. It
+	// is nil when the lockfile format cannot be parsed, in which case only
+	// its presence can be checked.
+	Packages map[string][]string
+}
+
+// DependencyReader defines the port for reading declared dependencies.
+type DependencyReader interface {
+	Read(path string) ([]DependencyManifest, error)
+}
@@ -4,6 +4,9 @@ import "context"
 
 // Downloader is the port for downloading files from a remote repository.
 type Downloader interface {
-	// Download clones or pulls a remote repository into a local cache directory.
-	Download(ctx context.Context, url, branch, cacheDir string) error
+	// Download fetches ref, a branch, tag or commit, of a remote repository
+	// into a local cache directory and checks it out.
+	Download(ctx context.Context, url, ref, cacheDir string) error
+	// Revision returns the commit checked out in a cache directory.
+	Revision(ctx context.Context, cacheDir string) (string, error)
 }
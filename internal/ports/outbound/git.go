@@ -5,4 +5,14 @@ type GitRepository interface {
 	SetConfig(path, key, value string) error
 	Init(path string) error
 	CreateBranch(path, branchName string) error
+	// SetInitialBranch names the branch of a repository without commits,
+	// so that its first commit goes to branchName.
+	SetInitialBranch(path, branchName string) error
+	// AddAll stages every file of the working tree.
+	AddAll(path string) error
+	// Commit records the staged files with message, without running the
+	// commit hooks.
+	Commit(path, message string) error
+	// AddRemote adds the remote name, pointing at url.
+	AddRemote(path, name, url string) error
 }
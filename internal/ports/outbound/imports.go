@@ -0,0 +1,20 @@
+package outbound
+
+// SourceImport is an import statement found in a source file.
+type SourceImport struct {
+	// File is the importing file, relative to the project root.
+	File string
+	Line int
+	// Specifier is the import as written, e.g. "express", "../domain/user"
+	// or "App\Domain\User".
+	Specifier string
+	// Target is the imported path relative to the project root when the
+	// import refers to project code, and empty for external packages.
+	Target string
+}
+
+// ImportScanner defines the port for listing the imports of a project's
+// source files.
+type ImportScanner interface {
+	Scan(path string) ([]SourceImport, error)
+}
@@ -0,0 +1,13 @@
+package outbound
+
+import (
+	"context"
+	"io"
+)
+
+// CommandRunner defines the port for running shell commands.
+type CommandRunner interface {
+	// Run runs command with the system shell in dir, writing its output to
+	// stdout and stderr as it is produced, until it exits or ctx is done.
+	Run(ctx context.Context, dir, command string, stdout, stderr io.Writer) error
+}
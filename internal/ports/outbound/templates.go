@@ -0,0 +1,30 @@
+package outbound
+
+import (
+	"context"
+	"io/fs"
+)
+
+// TemplateBundle is a template tree ready to be rendered.
+type TemplateBundle struct {
+	// FS is rooted at the directory holding manifest.json and skeletons/.
+	FS fs.FS
+	// Source describes where the templates were loaded from.
+	Source string
+	// Location selects the same source again: a git URL, a path or
+	// "embedded".
+	Location string
+	// Ref is the git branch or tag the templates were read from.
+	Ref string
+	// Commit is the git commit the templates were read from.
+	Commit string
+	// Stale reports that the templates come from a cached copy because
+	// updating them failed.
+	Stale bool
+}
+
+// TemplateSource defines the port for locating a template bundle.
+type TemplateSource interface {
+	// Load fetches the templates if needed and returns them.
+	Load(ctx context.Context) (*TemplateBundle, error)
+}
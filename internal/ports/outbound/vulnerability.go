@@ -0,0 +1,30 @@
+package outbound
+
+import "errors"
+
+// ErrScannerUnavailable is returned by a VulnerabilityScanner that cannot run
+// in the current environment, e.g. because its tool or database is missing.
+var ErrScannerUnavailable = errors.New("vulnerability scanner unavailable")
+
+// Vulnerability is a known advisory affecting a project dependency.
+type Vulnerability struct {
+	// ID is the advisory identifier, e.g. GHSA-xxxx or GO-2024-0001.
+	ID        string
+	Aliases   []string
+	Package   string
+	Version   string
+	Ecosystem string
+	Summary   string
+	Severity  string
+	// FixedIn is the first version that fixes the advisory, if known.
+	FixedIn string
+	// AffectedRange is the range of versions the advisory applies to, for
+	// scanners that report one, e.g. "<6.10.3".
+	AffectedRange string
+}
+
+// VulnerabilityScanner defines the port for auditing project dependencies
+// against known advisories.
+type VulnerabilityScanner interface {
+	Scan(path string) ([]Vulnerability, error)
+}
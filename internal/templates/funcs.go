@@ -0,0 +1,218 @@
+package templates
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+const alphaNum = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// FuncMap returns the functions available to every template and templated
+// path, so skeletons render the same way wherever they are used.
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		// Case conversions.
+		"ToLower":  strings.ToLower,
+		"ToUpper":  strings.ToUpper,
+		"ToKebab":  ToKebab,
+		"ToSnake":  ToSnake,
+		"ToCamel":  ToCamel,
+		"ToPascal": ToPascal,
+
+		// Values.
+		"Default":  Default,
+		"Required": Required,
+		"Env":      os.Getenv,
+
+		// Time.
+		"Now":  time.Now,
+		"Year": func() int { return time.Now().Year() },
+
+		// Generated values.
+		"UUID":         UUID,
+		"RandomString": RandomString,
+		"Secret":       Secret,
+
+		// Formatting.
+		"Indent":  Indent,
+		"Nindent": func(spaces int, s string) string { return "\n" + Indent(spaces, s) },
+		"Quote":   Quote,
+		"SQuote":  SQuote,
+		"ToJSON":  ToJSON,
+		"ToYAML":  ToYAML,
+	}
+}
+
+// Parse parses text as a template named name with the function library.
+func Parse(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(FuncMap()).Parse(text)
+}
+
+// words splits s into lower-case words at spaces, punctuation and case
+// changes: "myHTTPServer v2" gives [my http server v2].
+func words(s string) []string {
+	var result []string
+	var current strings.Builder
+	runes := []rune(s)
+	flush := func() {
+		if current.Len() > 0 {
+			result = append(result, current.String())
+			current.Reset()
+		}
+	}
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		current.WriteRune(unicode.ToLower(r))
+	}
+	flush()
+	return result
+}
+
+// ToKebab converts s to kebab-case: "My CLI tool" becomes "my-cli-tool".
+func ToKebab(s string) string {
+	return strings.Join(words(s), "-")
+}
+
+// ToSnake converts s to snake_case: "My CLI tool" becomes "my_cli_tool".
+func ToSnake(s string) string {
+	return strings.Join(words(s), "_")
+}
+
+// ToCamel converts s to camelCase: "My CLI tool" becomes "myCliTool".
+func ToCamel(s string) string {
+	pascal := ToPascal(s)
+	if pascal == "" {
+		return ""
+	}
+	runes := []rune(pascal)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
+
+// ToPascal converts s to PascalCase: "My CLI tool" becomes "MyCliTool".
+func ToPascal(s string) string {
+	var b strings.Builder
+	for _, word := range words(s) {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
+
+// empty reports whether value is nil or the zero value of its type.
+func empty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	return reflect.ValueOf(value).IsZero()
+}
+
+// Default returns value, or fallback when value is empty:
+// {{ Default "None" .Stack.persistence }}.
+func Default(fallback, value interface{}) interface{} {
+	if empty(value) {
+		return fallback
+	}
+	return value
+}
+
+// Required returns value, and fails the rendering with message when it is
+// empty: {{ Required "the customer is required" .Project.Customer }}.
+func Required(message string, value interface{}) (interface{}, error) {
+	if empty(value) {
+		return nil, errors.New(message)
+	}
+	return value, nil
+}
+
+// UUID returns a random (version 4) UUID.
+func UUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// RandomString returns n random letters and digits.
+func RandomString(n int) (string, error) {
+	b := make([]byte, n)
+	for i := range b {
+		index, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphaNum))))
+		if err != nil {
+			return "", err
+		}
+		b[i] = alphaNum[index.Int64()]
+	}
+	return string(b), nil
+}
+
+// Secret returns n random bytes, hex encoded, for passwords and keys in
+// local development files.
+func Secret(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Indent prefixes every non-empty line of s with the given number of spaces.
+func Indent(spaces int, s string) string {
+	pad := strings.Repeat(" ", spaces)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = pad + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Quote returns s as a double-quoted string, valid in both JSON and YAML.
+func Quote(s interface{}) string {
+	data, _ := json.Marshal(fmt.Sprint(s))
+	return string(data)
+}
+
+// SQuote returns s as a single-quoted YAML string.
+func SQuote(s interface{}) string {
+	return "'" + strings.ReplaceAll(fmt.Sprint(s), "'", "''") + "'"
+}
+
+// ToJSON encodes value as compact JSON.
+func ToJSON(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	return string(data), err
+}
+
+// ToYAML encodes value as YAML, without the trailing newline.
+func ToYAML(value interface{}) (string, error) {
+	data, err := yaml.Marshal(value)
+	return strings.TrimSuffix(string(data), "\n"), err
+}
@@ -0,0 +1,123 @@
+package templates
+
+import (
+	"bytes"
+	"grei-cli/internal/core/recipe"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func render(t *testing.T, text string, data interface{}) (string, error) {
+	t.Helper()
+	tmpl, err := Parse("test", text)
+	if err != nil {
+		t.Fatalf("Parse(%q) returned an unexpected error: %v", text, err)
+	}
+	var out bytes.Buffer
+	err = tmpl.Execute(&out, data)
+	return out.String(), err
+}
+
+func TestCaseConversions(t *testing.T) {
+	tests := []struct {
+		in                        string
+		kebab, snake, camel, pasc string
+	}{
+		{"my-app", "my-app", "my_app", "myApp", "MyApp"},
+		{"My CLI tool", "my-cli-tool", "my_cli_tool", "myCliTool", "MyCliTool"},
+		{"myHTTPServer", "my-http-server", "my_http_server", "myHttpServer", "MyHttpServer"},
+		{"grei_cli v2", "grei-cli-v2", "grei_cli_v2", "greiCliV2", "GreiCliV2"},
+		{"  Spaced Out ", "spaced-out", "spaced_out", "spacedOut", "SpacedOut"},
+		{"", "", "", "", ""},
+	}
+	for _, tt := range tests {
+		if got := ToKebab(tt.in); got != tt.kebab {
+			t.Errorf("ToKebab(%q) = %q, want %q", tt.in, got, tt.kebab)
+		}
+		if got := ToSnake(tt.in); got != tt.snake {
+			t.Errorf("ToSnake(%q) = %q, want %q", tt.in, got, tt.snake)
+		}
+		if got := ToCamel(tt.in); got != tt.camel {
+			t.Errorf("ToCamel(%q) = %q, want %q", tt.in, got, tt.camel)
+		}
+		if got := ToPascal(tt.in); got != tt.pasc {
+			t.Errorf("ToPascal(%q) = %q, want %q", tt.in, got, tt.pasc)
+		}
+	}
+}
+
+func TestFuncMap(t *testing.T) {
+	t.Setenv("GREI_TEST_VALUE", "from-env")
+	data := NewData(recipe.Recipe{
+		Project: recipe.Project{Name: "My App", Customer: "O'Brien"},
+		Stack:   recipe.Stack{Persistence: "PostgreSQL"},
+	})
+	data.Year = 2024
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{`{{ .Project.Name | ToSnake }}`, "my_app"},
+		{`{{ ToUpper .Project.Name }}`, "MY APP"},
+		{`{{ Default "None" .Stack.ci }}`, "None"},
+		{`{{ Default "None" .Stack.persistence }}`, "PostgreSQL"},
+		{`{{ Required "customer required" .Project.Customer }}`, "O'Brien"},
+		{`{{ Env "GREI_TEST_VALUE" }}`, "from-env"},
+		{`{{ Year }}`, strconv.Itoa(time.Now().Year())},
+		{`{{ .Year }}`, "2024"},
+		{`{{ Now.Year }}`, strconv.Itoa(time.Now().Year())},
+		{`{{ Quote .Project.Customer }}`, `"O'Brien"`},
+		{`{{ SQuote .Project.Customer }}`, `'O''Brien'`},
+		{`{{ Quote "say \"hi\"" }}`, `"say \"hi\""`},
+		{`{{ ToJSON .Stack }}`, `{"persistence":"PostgreSQL"}`},
+		{`{{ ToYAML .Stack }}`, "persistence: PostgreSQL"},
+		{`a:{{ Indent 2 "b: 1\nc: 2" }}`, "a:  b: 1\n  c: 2"},
+		{`a:{{ Nindent 2 "b: 1\n\nc: 2" }}`, "a:\n  b: 1\n\n  c: 2"},
+	}
+
+	for _, tt := range tests {
+		got, err := render(t, tt.text, data)
+		if err != nil {
+			t.Errorf("%s returned an unexpected error: %v", tt.text, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestRequired_Empty(t *testing.T) {
+	_, err := render(t, `{{ Required "the customer is required" .Project.Customer }}`, recipe.Recipe{})
+	if err == nil || !strings.Contains(err.Error(), "the customer is required") {
+		t.Errorf("Expected the Required message as error, got %v", err)
+	}
+}
+
+func TestGeneratedValues(t *testing.T) {
+	uuid, err := render(t, `{{ UUID }}`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(uuid) {
+		t.Errorf("UUID() = %q is not a version 4 UUID", uuid)
+	}
+
+	random, err := render(t, `{{ RandomString 24 }}`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^[a-zA-Z0-9]{24}$`).MatchString(random) {
+		t.Errorf("RandomString(24) = %q", random)
+	}
+
+	first, _ := Secret(16)
+	second, _ := Secret(16)
+	if len(first) != 32 || first == second {
+		t.Errorf("Secret(16) should return distinct 32-character values, got %q and %q", first, second)
+	}
+}
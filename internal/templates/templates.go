@@ -1,8 +1,23 @@
 package templates
 
-import "grei-cli/internal/core/recipe"
+import (
+	"grei-cli/internal/core/recipe"
+	"time"
+)
 
 type Data struct {
 	recipe.Recipe
-	Year int
+	// Stack shadows Recipe.Stack so templates see every answer by option
+	// name, as in {{ .Stack.persistence }}.
+	Stack map[string]interface{}
+	Year  int
+}
+
+// NewData returns the data templates are rendered with for r.
+func NewData(r recipe.Recipe) Data {
+	return Data{
+		Recipe: r,
+		Stack:  r.Stack.Values(),
+		Year:   time.Now().Year(),
+	}
 }
@@ -0,0 +1,11 @@
+// Package templates embeds the template bundle in the binary so that projects
+// can be initialized without network access or a populated template cache.
+package templates
+
+import "embed"
+
+// FS holds the templates tree as it was when the binary was built. Paths are
+// relative to this directory, e.g. "manifest.json" or "skeletons/generic".
+//
+//go:embed all:skeletons all:policies all:advisories manifest.json
+var FS embed.FS